@@ -4,19 +4,27 @@ import (
 	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/lib/pq"
 	"github.com/tcp_snm/flux/internal/api"
+	"github.com/tcp_snm/flux/internal/blob_store"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/judge"
+	"github.com/tcp_snm/flux/internal/rate_limit"
 	"github.com/tcp_snm/flux/internal/service"
 	"github.com/tcp_snm/flux/internal/service/auth_service"
 	"github.com/tcp_snm/flux/internal/service/contest_service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/relay_service"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/tournament_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
+	"github.com/tcp_snm/flux/middleware"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
@@ -45,30 +53,101 @@ func initDatabase() (*pgxpool.Pool, *database.Queries) {
 	return pool, database.New(pool)
 }
 
-func initUserService(db *database.Queries) *user_service.UserService {
+func initUserService(pool *pgxpool.Pool, db *database.Queries) *user_service.UserService {
 	log.Info("initializing user service")
+
+	// roles are cached, the ttl bounds how long a missed invalidation lives
+	ttl := user_service.DefaultRolesCacheTTL
+	if value := os.Getenv(user_service.KeyRolesCacheTTL); value != "" {
+		var err error
+		ttl, err = time.ParseDuration(value)
+		if err != nil {
+			panic(err)
+		}
+	}
+	log.Infof("using roles cache with ttl %v", ttl)
+
 	us := user_service.UserService{
 		DB: db,
+		RolesCache: user_service.NewLRURolesCache(
+			user_service.DefaultRolesCacheCapacity,
+			ttl,
+		),
 	}
 	err := us.IntializeUserServices()
 	if err != nil {
 		panic(err)
 	}
+
+	// keep the cache in sync with the other instances
+	go us.ListenRoleChanges(context.Background(), pool)
+
 	return &us
 }
 
-func initAuthService(db *database.Queries, us *user_service.UserService) *auth_service.AuthService {
+func initAuthService(
+	db *database.Queries,
+	us *user_service.UserService,
+	limiter *rate_limit.Limiter,
+) *auth_service.AuthService {
 	log.Info("initializing auth service")
-	return &auth_service.AuthService{
+	as := auth_service.AuthService{
 		DB:         db,
 		UserConfig: us,
+		Limiter:    limiter,
+	}
+	config, ok, err := auth_service.OIDCConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	if ok {
+		log.Info("oidc login enabled")
+		as.OIDC = auth_service.NewOIDCProvider(config)
+	}
+	return &as
+}
+
+func initRateLimiter(db *database.Queries) *rate_limit.Limiter {
+	switch storeName := os.Getenv(rate_limit.KeyRateLimitStore); storeName {
+	case rate_limit.StorePostgres:
+		log.Info("using postgres as rate limit store")
+		return rate_limit.NewLimiter(&rate_limit.PGStore{DB: db})
+	case "", rate_limit.StoreMemory:
+		log.Info("using memory as rate limit store")
+		return rate_limit.NewLimiter(rate_limit.NewMemoryStore())
+	default:
+		panic("unknown rate limit store " + storeName)
 	}
 }
 
 func initLockService(db *database.Queries, us *user_service.UserService) *lock_service.LockService {
-	return &lock_service.LockService{
+	// the scheduler picks up the pending timer locks on every start
+	scheduler := lock_service.NewLockScheduler(db)
+	ls := &lock_service.LockService{
 		DB:                db,
 		UserServiceConfig: us,
+		Scheduler:         scheduler,
+	}
+	// subscribe before starting, so no release is missed
+	scheduler.Subscribe(ls.NotifyContestStart)
+	scheduler.Start(context.Background())
+	return ls
+}
+
+func initBlobStore(pool *pgxpool.Pool) blob_store.Store {
+	switch storeName := os.Getenv(blob_store.KeyBlobStore); storeName {
+	case blob_store.StorePostgres:
+		log.Info("using postgres large objects as blob store")
+		return &blob_store.PGStore{Pool: pool}
+	case "", blob_store.StoreFileSystem:
+		store, err := blob_store.NewFSStore(os.Getenv(blob_store.KeyBlobStoreDir))
+		if err != nil {
+			panic(err)
+		}
+		log.Infof("using %s as blob store", store.Root)
+		return store
+	default:
+		panic("unknown blob store " + storeName)
 	}
 }
 
@@ -76,12 +155,25 @@ func initProblemService(
 	db *database.Queries,
 	ls *lock_service.LockService,
 	us *user_service.UserService,
+	store blob_store.Store,
 ) *problem_service.ProblemService {
 	log.Info("initializing problem service")
 	return &problem_service.ProblemService{
 		DB:                db,
 		LockServiceConfig: ls,
 		UserServiceConfig: us,
+		TestCaseStore:     store,
+	}
+}
+
+func initTeamService(
+	db *database.Queries,
+	us *user_service.UserService,
+) *team_service.TeamService {
+	log.Info("initializing team service")
+	return &team_service.TeamService{
+		DB:                db,
+		UserServiceConfig: us,
 	}
 }
 
@@ -90,6 +182,7 @@ func initContestService(
 	ls *lock_service.LockService,
 	us *user_service.UserService,
 	ps *problem_service.ProblemService,
+	tms *team_service.TeamService,
 ) *contest_service.ContestService {
 	log.Info("initializing contest service")
 	return &contest_service.ContestService{
@@ -97,6 +190,7 @@ func initContestService(
 		LockServiceConfig:    ls,
 		UserServiceConfig:    us,
 		ProblemServiceConfig: ps,
+		TeamServiceConfig:    tms,
 	}
 }
 
@@ -107,34 +201,108 @@ func initTournamentService(
 	cs *contest_service.ContestService,
 ) *tournament_service.TournamentService {
 	log.Info("initializing tournament service")
-	return &tournament_service.TournamentService{
+	ts := tournament_service.TournamentService{
 		DB:                   db,
 		UserServiceConfig:    us,
 		LockServiceConfig:    ls,
 		ContestServiceConfig: cs,
 	}
+	err := ts.IntializeTournamentService()
+	if err != nil {
+		panic(err)
+	}
+	return &ts
+}
+
+func initSubmissionService(
+	db *database.Queries,
+	us *user_service.UserService,
+	ps *problem_service.ProblemService,
+	cs *contest_service.ContestService,
+	tms *team_service.TeamService,
+) *submission_service.SubmissionService {
+	log.Info("initializing submission service")
+	return &submission_service.SubmissionService{
+		DB:                   db,
+		UserServiceConfig:    us,
+		ProblemServiceConfig: ps,
+		ContestServiceConfig: cs,
+		TeamServiceConfig:    tms,
+	}
+}
+
+func initRelayService(
+	db *database.Queries,
+	ss *submission_service.SubmissionService,
+) *relay_service.RelayService {
+	log.Info("initializing relay service")
+	return &relay_service.RelayService{
+		DB:                      db,
+		SubmissionServiceConfig: ss,
+		Clients: map[database.Platform]relay_service.PlatformClient{
+			database.PlatformCodeforces: relay_service.NewCodeforcesClient(
+				os.Getenv(relay_service.KeyCodeforcesBaseURL),
+			),
+		},
+	}
+}
+
+func startJudge(ss *submission_service.SubmissionService) {
+	languages, err := judge.LoadLanguages(os.Getenv(judge.KeyJudgeLanguages))
+	if err != nil {
+		panic(err)
+	}
+	// submissions are not judged locally without an isolated sandbox
+	err = judge.StartJudgeWorkers(2, languages, ss.ReportJudgeVerdict)
+	if err != nil {
+		log.Errorf("local judge is not started, %v", err)
+		return
+	}
+
+	// pick up submissions left pending by the previous run
+	go func() {
+		err := ss.RequeuePendingSubmissions(context.Background())
+		if err != nil {
+			log.Errorf("cannot requeue pending submissions, %v", err)
+		}
+	}()
 }
 
 func initApi(pool *pgxpool.Pool, db *database.Queries) *api.Api {
 	log.Info("initializing api config")
-	us := initUserService(db)
+	us := initUserService(pool, db)
 	log.Info("user service created")
-	as := initAuthService(db, us)
+	limiter := initRateLimiter(db)
+	middleware.InitializeRateLimiter(limiter)
+	as := initAuthService(db, us, limiter)
 	log.Info("auth service created")
+	as.StartTokenJanitor(auth_service.DefaultTokenJanitorInterval)
+	middleware.InitializeMiddleware(as.IsSessionActive)
 	ls := initLockService(db, us)
 	log.Info("lock service created")
-	ps := initProblemService(db, ls, us)
+	ps := initProblemService(db, ls, us, initBlobStore(pool))
 	log.Info("problem service created")
-	cs := initContestService(db, ls, us, ps)
+	tms := initTeamService(db, us)
+	log.Info("team service created")
+	cs := initContestService(db, ls, us, ps, tms)
 	log.Info("contest service created")
 	ts := initTournamentService(db, us, ls, cs)
 	log.Info("tournament service created")
+	ss := initSubmissionService(db, us, ps, cs, tms)
+	log.Info("submission service created")
+	rs := initRelayService(db, ss)
+	log.Info("relay service created")
+	rs.StartRelayWorkers(1)
+	startJudge(ss)
 	a := api.Api{
 		AuthServiceConfig:       as,
 		ProblemServiceConfig:    ps,
 		LockServiceConfig:       ls,
 		ContestServiceConfig:    cs,
 		TournamentServiceConfig: ts,
+		SubmissionServiceConfig: ss,
+		TeamServiceConfig:       tms,
+		UserServiceConfig:       us,
 	}
 	return &a
 }
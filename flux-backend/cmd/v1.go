@@ -12,11 +12,24 @@ func NewV1Router() *chi.Mux {
 	v1.Get("/healthz", middleware.JWTMiddleware(apiConfig.HandlerReadiness))
 
 	// auth layer
-	v1.Get("/auth/signup", apiConfig.HandlerSignUpSendMail)
-	v1.Post("/auth/signup", apiConfig.HandlerSignUp)
-	v1.Post("/auth/login", apiConfig.HandlerLogin)
-	v1.Get("/auth/reset-password", apiConfig.HandlerResetPasswordSendMail)
-	v1.Post("/auth/reset-password", apiConfig.HandlerResetPassword)
+	v1.Get("/auth/signup", middleware.RateLimitMiddleware(apiConfig.HandlerSignUpSendMail))
+	v1.Post("/auth/signup", middleware.RateLimitMiddleware(apiConfig.HandlerSignUp))
+	v1.Post("/auth/login", middleware.RateLimitMiddleware(apiConfig.HandlerLogin))
+	v1.Post("/auth/login/2fa", middleware.RateLimitMiddleware(apiConfig.HandlerLoginTwoFactor))
+	v1.Post("/auth/login/2fa/enroll", middleware.RateLimitMiddleware(apiConfig.HandlerLoginTwoFactorEnroll))
+	v1.Post("/auth/refresh", apiConfig.HandlerRefreshSession)
+	v1.Post("/auth/logout", apiConfig.HandlerLogout)
+	v1.Post("/auth/logout-all", middleware.JWTMiddleware(apiConfig.HandlerLogoutAll))
+	v1.Get("/auth/reset-password", middleware.RateLimitMiddleware(apiConfig.HandlerResetPasswordSendMail))
+	v1.Post("/auth/reset-password", middleware.RateLimitMiddleware(apiConfig.HandlerResetPassword))
+	v1.Post("/auth/change-password", middleware.JWTMiddleware(apiConfig.HandlerChangePassword))
+	v1.Get("/auth/2fa", middleware.JWTMiddleware(apiConfig.HandlerGetTwoFactorStatus))
+	v1.Post("/auth/2fa/enroll", middleware.JWTMiddleware(apiConfig.HandlerEnrollTwoFactor))
+	v1.Post("/auth/2fa/confirm", middleware.JWTMiddleware(apiConfig.HandlerConfirmTwoFactor))
+	v1.Post("/auth/2fa/recovery-codes", middleware.JWTMiddleware(apiConfig.HandlerRegenerateRecoveryCodes))
+	v1.Delete("/auth/2fa", middleware.JWTMiddleware(apiConfig.HandlerDisableTwoFactor))
+	v1.Get("/auth/oidc/login", middleware.RateLimitMiddleware(apiConfig.HandlerOIDCLogin))
+	v1.Get("/auth/oidc/callback", middleware.RateLimitMiddleware(apiConfig.HandlerOIDCCallback))
 
 	// locks layer
 	// get locks
@@ -28,6 +41,18 @@ func NewV1Router() *chi.Mux {
 	v1.Put("/locks", middleware.JWTMiddleware(apiConfig.HandlerUpdateLock))
 	// delete lock
 	v1.Delete("/locks", middleware.JWTMiddleware(apiConfig.HanlderDeleteLockById))
+	// release or engage a manual lock
+	v1.Post("/locks/release", middleware.JWTMiddleware(apiConfig.HandlerReleaseLock))
+	v1.Post("/locks/engage", middleware.JWTMiddleware(apiConfig.HandlerEngageLock))
+	v1.Get("/locks/audit", middleware.JWTMiddleware(apiConfig.HandlerGetLockAuditLogs))
+	// users allowed to see a lock's entities without its access role
+	v1.Get("/locks/users", middleware.JWTMiddleware(apiConfig.HandlerGetLockAllowedUsers))
+	v1.Put("/locks/users", middleware.JWTMiddleware(apiConfig.HandlerSetLockAllowedUsers))
+	// everything a lock protects, and moving all of it to another lock
+	v1.Get("/locks/usage", middleware.JWTMiddleware(apiConfig.HandlerGetLockUsage))
+	v1.Post("/locks/reassign", middleware.JWTMiddleware(apiConfig.HandlerReassignLock))
+	// postpone or advance a timer lock that has not expired
+	v1.Post("/locks/reschedule", middleware.JWTMiddleware(apiConfig.HandlerRescheduleLock))
 
 	// problems layer
 	// search
@@ -37,18 +62,31 @@ func NewV1Router() *chi.Mux {
 	v1.Post("/problems", middleware.JWTMiddleware(apiConfig.HandlerAddProblem))
 	// update
 	v1.Put("/problems", middleware.JWTMiddleware(apiConfig.HandlerUpdateProblem))
+	// hidden test cases
+	v1.Get("/problems/tests", middleware.JWTMiddleware(apiConfig.HandlerGetTestCases))
+	v1.Post("/problems/tests", middleware.JWTMiddleware(apiConfig.HandlerUploadTestCase))
+	v1.Put("/problems/tests/order", middleware.JWTMiddleware(apiConfig.HandlerReorderTestCases))
+	v1.Delete("/problems/tests", middleware.JWTMiddleware(apiConfig.HandlerDeleteTestCase))
 
 	// contest
 	// search
 	v1.Get("/contests", middleware.JWTMiddleware(apiConfig.HandlerGetContestByID))
 	v1.Get("/contests/problems", middleware.JWTMiddleware(apiConfig.HandlerGetContestProblems))
 	v1.Get("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerGetContestUsers))
+	v1.Get("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerGetContestTeams))
 	v1.Post("/contests/search", middleware.JWTMiddleware(apiConfig.HandlerGetContestsByFilters))
 	v1.Get("/contests/user-registered", middleware.JWTMiddleware(apiConfig.HandlerGetUserRegisteredContests))
+	v1.Get("/contests/leaderboard", middleware.JWTMiddleware(apiConfig.HandlerGetContestLeaderboard))
 	// create
 	v1.Post("/contests", middleware.JWTMiddleware(apiConfig.HandlerCreateContest))
 	// update
 	v1.Put("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerSetUsersInContest))
+	v1.Post("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerAddUsersToContest))
+	v1.Delete("/contests/users", middleware.JWTMiddleware(apiConfig.HandlerRemoveUsersFromContest))
+	v1.Post("/contests/register", middleware.JWTMiddleware(apiConfig.HandlerRegisterForContest))
+	v1.Delete("/contests/register", middleware.JWTMiddleware(apiConfig.HandlerUnregisterFromContest))
+	v1.Post("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerRegisterTeamForContest))
+	v1.Delete("/contests/teams", middleware.JWTMiddleware(apiConfig.HandlerUnregisterTeamFromContest))
 	v1.Put("/contests/problems", middleware.JWTMiddleware(apiConfig.HandlerSetProblemsInContest))
 	v1.Put("/contests", middleware.JWTMiddleware(apiConfig.HandlerUpdateContest))
 	// delete
@@ -58,11 +96,55 @@ func NewV1Router() *chi.Mux {
 	// search
 	v1.Get("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerGetTournament))
 	v1.Get("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentRound))
+	v1.Get("/tournaments/standings", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentStandings))
 	v1.Post("/tournaments/search", middleware.JWTMiddleware(apiConfig.HandlerGetTournamentsByFilters))
 	// create
 	v1.Post("/tournaments", middleware.JWTMiddleware(apiConfig.HandlerCreateTournament))
 	v1.Post("/tournaments/rounds", middleware.JWTMiddleware(apiConfig.HandlerCreateTournamentRound))
+	v1.Post("/tournaments/rounds/advance", middleware.JWTMiddleware(apiConfig.HandlerAdvanceTournamentRound))
 	// update
 	v1.Put("/tournaments/contests", middleware.JWTMiddleware(apiConfig.HandlerChangeTournamentContest))
+	v1.Put("/tournaments/rounds/scoring", middleware.JWTMiddleware(apiConfig.HandlerUpdateRoundScoring))
+	v1.Put("/tournaments/rounds/advancement", middleware.JWTMiddleware(apiConfig.HandlerUpdateRoundAdvancement))
+
+	// roles
+	// search
+	v1.Get("/roles", middleware.JWTMiddleware(apiConfig.HandlerGetRoleHolders))
+	v1.Get("/roles/permissions", middleware.JWTMiddleware(apiConfig.HandlerGetRolePermissions))
+	v1.Get("/roles/cache", middleware.JWTMiddleware(apiConfig.HandlerGetRolesCacheStats))
+	// update
+	v1.Post("/roles", middleware.JWTMiddleware(apiConfig.HandlerGrantRole))
+	v1.Delete("/roles", middleware.JWTMiddleware(apiConfig.HandlerRevokeRole))
+	v1.Post("/roles/permissions", middleware.JWTMiddleware(apiConfig.HandlerGrantRolePermission))
+	v1.Delete("/roles/permissions", middleware.JWTMiddleware(apiConfig.HandlerRevokeRolePermission))
+
+	// users
+	// search
+	v1.Get("/users", middleware.JWTMiddleware(apiConfig.HandlerGetUserProfile))
+	v1.Get("/users/me", middleware.JWTMiddleware(apiConfig.HandlerGetMyProfile))
+	v1.Get("/users/me/email", middleware.JWTMiddleware(apiConfig.HandlerChangeEmailSendMail))
+	// update
+	v1.Put("/users/me", middleware.JWTMiddleware(apiConfig.HandlerUpdateMyProfile))
+	v1.Put("/users/me/email", middleware.JWTMiddleware(apiConfig.HandlerChangeEmail))
+
+	// teams
+	// search
+	v1.Get("/teams", middleware.JWTMiddleware(apiConfig.HandlerGetTeam))
+	v1.Get("/teams/mine", middleware.JWTMiddleware(apiConfig.HandlerGetUserTeams))
+	v1.Get("/teams/invites", middleware.JWTMiddleware(apiConfig.HandlerGetTeamInvites))
+	// create
+	v1.Post("/teams", middleware.JWTMiddleware(apiConfig.HandlerCreateTeam))
+	v1.Post("/teams/invites", middleware.JWTMiddleware(apiConfig.HandlerInviteToTeam))
+	// update
+	v1.Put("/teams/invites", middleware.JWTMiddleware(apiConfig.HandlerRespondToTeamInvite))
+	// delete
+	v1.Delete("/teams/members", middleware.JWTMiddleware(apiConfig.HandlerLeaveTeam))
+
+	// submissions
+	// search
+	v1.Get("/submissions", middleware.JWTMiddleware(apiConfig.HandlerGetSubmissionByID))
+	v1.Post("/submissions/search", middleware.JWTMiddleware(apiConfig.HandlerGetSubmissionsByFilters))
+	// create
+	v1.Post("/submissions", middleware.JWTMiddleware(apiConfig.HandlerSubmit))
 	return v1
 }
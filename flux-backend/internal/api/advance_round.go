@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerUpdateRoundAdvancement(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		RoundNumber      int32     `json:"round_number"`
+		TournamentID     uuid.UUID `json:"tournament_id"`
+		Advancement      string    `json:"advancement"`
+		AdvanceK         *int32    `json:"advance_k"`
+		AdvanceThreshold *float64  `json:"advance_threshold"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update using service
+	err = a.TournamentServiceConfig.UpdateRoundAdvancement(
+		r.Context(),
+		tournament_service.UpdateRoundAdvancementRequest{
+			TournamentID:     request.TournamentID,
+			RoundNumber:      request.RoundNumber,
+			Advancement:      tournament_service.RoundAdvancement(request.Advancement),
+			AdvanceK:         request.AdvanceK,
+			AdvanceThreshold: request.AdvanceThreshold,
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("round advancement updated successfully"))
+}
+
+func (a *Api) HandlerAdvanceTournamentRound(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		RoundNumber  int32     `json:"round_number"`
+		TournamentID uuid.UUID `json:"tournament_id"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// advance using service
+	qualifiers, err := a.TournamentServiceConfig.AdvanceTournamentRound(
+		r.Context(),
+		tournament_service.AdvanceRoundRequest{
+			TournamentID: request.TournamentID,
+			RoundNumber:  request.RoundNumber,
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(qualifiers)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", qualifiers, err.Error())
+		http.Error(w, "users advanced but error in preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
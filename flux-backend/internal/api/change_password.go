@@ -0,0 +1,35 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func (a *Api) HandlerChangePassword(w http.ResponseWriter, r *http.Request) {
+	// get the passwords
+	type params struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		msg := fmt.Sprintf("invalid request payload, %s", err.Error())
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// change password
+	err = a.AuthServiceConfig.ChangePassword(
+		r.Context(),
+		request.CurrentPassword,
+		request.NewPassword,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// respond with success
+	respondWithJson(w, http.StatusOK, []byte("password changed successfully"))
+}
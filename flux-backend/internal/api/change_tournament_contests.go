@@ -14,6 +14,7 @@ func (a *Api) HandlerChangeTournamentContest(w http.ResponseWriter, r *http.Requ
 		RoundNumber  int32       `json:"round_number"`
 		TournamentID uuid.UUID   `json:"tournament_id"`
 		ContestIDs   []uuid.UUID `json:"contest_ids"`
+		Weights      []int32     `json:"weights"`
 	}
 	var request params
 	err := decodeJsonBody(r.Body, &request)
@@ -29,6 +30,7 @@ func (a *Api) HandlerChangeTournamentContest(w http.ResponseWriter, r *http.Requ
 			TournamentID: request.TournamentID,
 			RoundNumber:  request.RoundNumber,
 			ContestIDs:   request.ContestIDs,
+			Weights:      request.Weights,
 		},
 	)
 	if err != nil {
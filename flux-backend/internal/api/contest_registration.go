@@ -0,0 +1,160 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+)
+
+func (a *Api) HandlerRegisterForContest(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestIDStr := r.URL.Query().Get("contest_id")
+
+	// parse
+	contestID, err := uuid.Parse(contestIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// register
+	err = a.ContestServiceConfig.RegisterForContest(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("registered successfully"))
+}
+
+func (a *Api) HandlerUnregisterFromContest(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestIDStr := r.URL.Query().Get("contest_id")
+
+	// parse
+	contestID, err := uuid.Parse(contestIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// unregister
+	err = a.ContestServiceConfig.UnregisterFromContest(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("unregistered successfully"))
+}
+
+func (a *Api) HandlerAddUsersToContest(w http.ResponseWriter, r *http.Request) {
+	// parse the body
+	var request contest_service.ChangeContestUsersRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// add users
+	err = a.ContestServiceConfig.AddUsersToContest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("users added successfully"))
+}
+
+func (a *Api) HandlerRemoveUsersFromContest(w http.ResponseWriter, r *http.Request) {
+	// parse the body
+	var request contest_service.ChangeContestUsersRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// remove users
+	err = a.ContestServiceConfig.RemoveUsersFromContest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("users removed successfully"))
+}
+
+func (a *Api) HandlerRegisterTeamForContest(w http.ResponseWriter, r *http.Request) {
+	// parse the body
+	var request contest_service.TeamRegistrationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// register
+	err = a.ContestServiceConfig.RegisterTeamForContest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("team registered successfully"))
+}
+
+func (a *Api) HandlerUnregisterTeamFromContest(w http.ResponseWriter, r *http.Request) {
+	// parse the body
+	var request contest_service.TeamRegistrationRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// unregister
+	err = a.ContestServiceConfig.UnregisterTeamFromContest(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("team unregistered successfully"))
+}
+
+func (a *Api) HandlerGetContestTeams(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestIDStr := r.URL.Query().Get("contest_id")
+
+	// parse
+	contestID, err := uuid.Parse(contestIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// fetch teams using service
+	teams, err := a.ContestServiceConfig.GetContestRegisteredTeams(r.Context(), contestID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(teams)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", teams, err.Error())
+		http.Error(
+			w, "cannot send teams, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -186,3 +186,53 @@ func (a *Api) HandlerGetUserRegisteredContests(w http.ResponseWriter, r *http.Re
 
 	respondWithJson(w, http.StatusOK, response)
 }
+
+func (a *Api) HandlerGetContestLeaderboard(w http.ResponseWriter, r *http.Request) {
+	// get the contest id
+	contestID, err := uuid.Parse(r.URL.Query().Get("contest_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the page number
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("page_number"))
+	if err != nil {
+		http.Error(w, "invalid page number", http.StatusBadRequest)
+		return
+	}
+
+	// get page size
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil {
+		http.Error(w, "invalid page size", http.StatusBadRequest)
+		return
+	}
+
+	// get the leaderboard
+	leaderboard, err := a.ContestServiceConfig.GetContestLeaderboard(
+		r.Context(),
+		contest_service.GetLeaderboardRequest{
+			ContestID:  contestID,
+			PageNumber: int32(pageNumber),
+			PageSize:   int32(pageSize),
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(leaderboard)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", leaderboard, err.Error())
+		http.Error(
+			w, "cannot send leaderboard, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
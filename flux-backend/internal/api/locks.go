@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -52,7 +53,7 @@ func (a *Api) HandlerCreateLock(w http.ResponseWriter, r *http.Request) {
 
 func (a *Api) HandlerUpdateLock(w http.ResponseWriter, r *http.Request) {
 	// get the data
-	var currentLock lock_service.FluxLock
+	var currentLock lock_service.UpdateLockRequest
 	err := decodeJsonBody(r.Body, &currentLock)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -101,3 +102,198 @@ func (a *Api) HanlderDeleteLockById(w http.ResponseWriter, r *http.Request) {
 
 	respondWithJson(w, http.StatusOK, []byte("lock deleted successfully"))
 }
+
+func (a *Api) HandlerReleaseLock(w http.ResponseWriter, r *http.Request) {
+	a.handleManualLockAction(w, r, a.LockServiceConfig.ReleaseLock)
+}
+
+func (a *Api) HandlerEngageLock(w http.ResponseWriter, r *http.Request) {
+	a.handleManualLockAction(w, r, a.LockServiceConfig.EngageLock)
+}
+
+func (a *Api) HandlerGetLockAuditLogs(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	lockIdStr := r.URL.Query().Get("lock_id")
+	lockId, err := uuid.Parse(lockIdStr)
+	if err != nil {
+		http.Error(w, "invalid lock id provided", http.StatusBadRequest)
+		return
+	}
+
+	logs, err := a.LockServiceConfig.GetLockAuditLogs(r.Context(), lockId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(logs)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", logs, err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) handleManualLockAction(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(context.Context, lock_service.LockActionRequest) (lock_service.FluxLock, error),
+) {
+	// decode the body
+	var request lock_service.LockActionRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload, %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	lock, err := action(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(lock)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", lock, err)
+		http.Error(w, "lock was changed, but there was an error preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerGetLockAllowedUsers(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	lockIdStr := r.URL.Query().Get("lock_id")
+	lockId, err := uuid.Parse(lockIdStr)
+	if err != nil {
+		http.Error(w, "invalid lock id provided", http.StatusBadRequest)
+		return
+	}
+
+	users, err := a.LockServiceConfig.GetLockAllowedUsers(r.Context(), lockId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(users)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", users, err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerSetLockAllowedUsers(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request lock_service.LockUsersRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload, %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	allowedUsers, err := a.LockServiceConfig.SetLockAllowedUsers(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(allowedUsers)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", allowedUsers, err)
+		http.Error(w, "allowed users were updated, but there was an error preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerGetLockUsage(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	lockIdStr := r.URL.Query().Get("lock_id")
+	lockId, err := uuid.Parse(lockIdStr)
+	if err != nil {
+		http.Error(w, "invalid lock id provided", http.StatusBadRequest)
+		return
+	}
+
+	usage, err := a.LockServiceConfig.GetLockUsage(r.Context(), lockId)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(usage)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", usage, err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerReassignLock(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request lock_service.ReassignLockRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload, %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	// respond with everything that was moved
+	usage, err := a.LockServiceConfig.ReassignLock(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(usage)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", usage, err)
+		http.Error(w, "lock was reassigned, but there was an error preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
+
+func (a *Api) HandlerRescheduleLock(w http.ResponseWriter, r *http.Request) {
+	// decode the body
+	var request lock_service.RescheduleLockRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid request payload, %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	lock, err := a.LockServiceConfig.RescheduleLock(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	bytes, err := json.Marshal(lock)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", lock, err)
+		http.Error(w, "lock was rescheduled, but there was an error preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, bytes)
+}
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
-	"github.com/tcp_snm/flux/middleware"
 )
 
 func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
@@ -28,7 +27,7 @@ func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// validate the user and gen a jwt token
-	userLoginResponse, jwtToken, tokenExpiry, err := a.AuthServiceConfig.Login(
+	userLoginResponse, tokens, err := a.AuthServiceConfig.Login(
 		r.Context(),
 		param.UserName,
 		param.RollNo,
@@ -47,17 +46,14 @@ func (a *Api) HandlerLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// set jwt session cookie
-	cookie := &http.Cookie{
-		Name:     middleware.KeyJwtSessionCookieName,
-		Value:    jwtToken,
-		Expires:  tokenExpiry,
-		Path:     "/",                  // Important: Makes the cookie available across the entire site
-		HttpOnly: true,                 // Crucial: Prevents JavaScript access
-		Secure:   true,                 // Crucial: Only send over HTTPS
-		SameSite: http.SameSiteLaxMode, // Recommended: Protects against CSRF
+	// a login waiting for the 2fa code gets no session yet
+	if userLoginResponse.TwoFactor != nil {
+		respondWithJson(w, http.StatusAccepted, responseBytes)
+		return
 	}
-	http.SetCookie(w, cookie)
+
+	// set session cookies
+	setSessionCookies(w, tokens)
 
 	log.WithFields(log.Fields{
 		"user_name": userLoginResponse.UserName,
@@ -5,7 +5,10 @@ import (
 	"github.com/tcp_snm/flux/internal/service/contest_service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/tournament_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 type Api struct {
@@ -14,4 +17,7 @@ type Api struct {
 	LockServiceConfig       *lock_service.LockService
 	ContestServiceConfig    *contest_service.ContestService
 	TournamentServiceConfig *tournament_service.TournamentService
+	SubmissionServiceConfig *submission_service.SubmissionService
+	TeamServiceConfig       *team_service.TeamService
+	UserServiceConfig       *user_service.UserService
 }
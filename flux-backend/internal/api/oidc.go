@@ -0,0 +1,118 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+	"github.com/tcp_snm/flux/middleware"
+)
+
+// the user must finish the login at the provider within this time
+const oidcCookieMaxAge = 10 * time.Minute
+
+func (a *Api) HandlerOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	authURL, request, err := a.AuthServiceConfig.BeginOIDCLogin(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// the random values are url safe, so they can be joined with dots
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyOIDCCookieName,
+		Value:    strings.Join([]string{request.State, request.Nonce, request.CodeVerifier}, "."),
+		Expires:  time.Now().Add(oidcCookieMaxAge),
+		Path:     middleware.OIDCCookiePath,
+		HttpOnly: true,
+		Secure:   true,
+		// the callback is a top level redirect from the provider
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func (a *Api) HandlerOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	// the cookie is needed only once
+	oidcCookie, cookieErr := r.Cookie(middleware.KeyOIDCCookieName)
+	clearOIDCCookie(w)
+
+	// the provider redirects with an error when the user cancels
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		handlerError(
+			fmt.Errorf(
+				"%w, identity provider returned %s, %s",
+				flux_errors.ErrInvalidRequestCredentials,
+				providerErr,
+				query.Get("error_description"),
+			),
+			w,
+		)
+		return
+	}
+
+	if cookieErr != nil {
+		handlerError(
+			fmt.Errorf("%w, oidc login was not started or has expired", flux_errors.ErrInvalidRequestCredentials),
+			w,
+		)
+		return
+	}
+	parts := strings.Split(oidcCookie.Value, ".")
+	if len(parts) != 3 {
+		handlerError(fmt.Errorf("%w, invalid oidc cookie", flux_errors.ErrInvalidRequestCredentials), w)
+		return
+	}
+
+	userLoginResponse, tokens, err := a.AuthServiceConfig.OIDCLogin(
+		r.Context(),
+		query.Get("code"),
+		query.Get("state"),
+		auth_service.OIDCLoginRequest{
+			State:        parts[0],
+			Nonce:        parts[1],
+			CodeVerifier: parts[2],
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	responseBytes, err := json.Marshal(userLoginResponse)
+	if err != nil {
+		log.WithField("resonse", userLoginResponse).Errorf("unable to marshal login response %v", err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	// a login waiting for the 2fa code gets no session yet
+	if userLoginResponse.TwoFactor != nil {
+		respondWithJson(w, http.StatusAccepted, responseBytes)
+		return
+	}
+
+	// set session cookies
+	setSessionCookies(w, tokens)
+
+	respondWithJson(w, http.StatusOK, responseBytes)
+}
+
+func clearOIDCCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyOIDCCookieName,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		Path:     middleware.OIDCCookiePath,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (a *Api) HandlerGetRoleHolders(w http.ResponseWriter, r *http.Request) {
+	// get the roles
+	roles, err := a.UserServiceConfig.GetRoleHolders(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(roles)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", roles, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGrantRole(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.ChangeUserRoleRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// grant
+	err = a.UserServiceConfig.GrantRole(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("role granted successfully"))
+}
+
+func (a *Api) HandlerRevokeRole(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.ChangeUserRoleRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// revoke
+	err = a.UserServiceConfig.RevokeRole(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("role revoked successfully"))
+}
+
+func (a *Api) HandlerGetRolePermissions(w http.ResponseWriter, r *http.Request) {
+	// get the mapping
+	permissions, err := a.UserServiceConfig.GetRolePermissions(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(permissions)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", permissions, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGrantRolePermission(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.ChangeRolePermissionRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// grant
+	err = a.UserServiceConfig.GrantRolePermission(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("permission granted successfully"))
+}
+
+func (a *Api) HandlerRevokeRolePermission(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.ChangeRolePermissionRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// revoke
+	err = a.UserServiceConfig.RevokeRolePermission(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("permission revoked successfully"))
+}
+
+func (a *Api) HandlerGetRolesCacheStats(w http.ResponseWriter, r *http.Request) {
+	// get the stats
+	stats, err := a.UserServiceConfig.GetRolesCacheStats(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(stats)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", stats, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/auth_service"
+	"github.com/tcp_snm/flux/middleware"
+)
+
+func (a *Api) HandlerRefreshSession(w http.ResponseWriter, r *http.Request) {
+	// refresh token is passed as cookie
+	refreshCookie, err := r.Cookie(middleware.KeyRefreshCookieName)
+	if err != nil {
+		handlerError(flux_errors.ErrInvalidRequestCredentials, w)
+		return
+	}
+
+	// rotate the tokens
+	tokens, err := a.AuthServiceConfig.RefreshSession(r.Context(), refreshCookie.Value)
+	if err != nil {
+		// the cookies are useless now
+		clearSessionCookies(w)
+		handlerError(err, w)
+		return
+	}
+
+	// set session cookies
+	setSessionCookies(w, tokens)
+
+	respondWithJson(w, http.StatusOK, []byte("session refreshed successfully"))
+}
+
+func (a *Api) HandlerLogout(w http.ResponseWriter, r *http.Request) {
+	// refresh token is passed as cookie
+	refreshCookie, err := r.Cookie(middleware.KeyRefreshCookieName)
+	if err != nil {
+		handlerError(flux_errors.ErrInvalidRequestCredentials, w)
+		return
+	}
+
+	// revoke the session
+	err = a.AuthServiceConfig.Logout(r.Context(), refreshCookie.Value)
+	if err != nil {
+		// the cookies are useless either way
+		clearSessionCookies(w)
+		handlerError(err, w)
+		return
+	}
+
+	clearSessionCookies(w)
+	respondWithJson(w, http.StatusOK, []byte("logged out successfully"))
+}
+
+func (a *Api) HandlerLogoutAll(w http.ResponseWriter, r *http.Request) {
+	// revoke all the sessions
+	err := a.AuthServiceConfig.LogoutAllSessions(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	clearSessionCookies(w)
+	respondWithJson(w, http.StatusOK, []byte("logged out of all devices successfully"))
+}
+
+func setSessionCookies(w http.ResponseWriter, tokens auth_service.SessionTokens) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyJwtSessionCookieName,
+		Value:    tokens.AccessToken,
+		Expires:  tokens.AccessExpiry,
+		Path:     "/",                  // Important: Makes the cookie available across the entire site
+		HttpOnly: true,                 // Crucial: Prevents JavaScript access
+		Secure:   true,                 // Crucial: Only send over HTTPS
+		SameSite: http.SameSiteLaxMode, // Recommended: Protects against CSRF
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyRefreshCookieName,
+		Value:    tokens.RefreshToken,
+		Expires:  tokens.RefreshExpiry,
+		Path:     middleware.RefreshCookiePath,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyJwtSessionCookieName,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.KeyRefreshCookieName,
+		Value:    "",
+		Expires:  time.Unix(0, 0),
+		Path:     middleware.RefreshCookiePath,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+func (a *Api) HandlerSubmit(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request submission_service.SubmitRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// submit using service
+	submission, err := a.SubmissionServiceConfig.Submit(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(submission)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", submission, err)
+		http.Error(
+			w, "submission created but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetSubmissionByID(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	idStr := r.URL.Query().Get("submission_id")
+
+	// parse
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the submission
+	submission, err := a.SubmissionServiceConfig.GetSubmissionByID(r.Context(), id)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(submission)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", submission, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetSubmissionsByFilters(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request submission_service.GetSubmissionsRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get submissions
+	submissions, err := a.SubmissionServiceConfig.GetSubmissionsByFilters(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(submissions)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", submissions, err)
+		http.Error(
+			w, "cannot send submissions, internal error. please try again later",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
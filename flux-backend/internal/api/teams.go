@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/service/team_service"
+)
+
+func (a *Api) HandlerCreateTeam(w http.ResponseWriter, r *http.Request) {
+	// parse from body
+	var team team_service.Team
+	err := decodeJsonBody(r.Body, &team)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// create team using service
+	serviceTeam, err := a.TeamServiceConfig.CreateTeam(r.Context(), team)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(serviceTeam)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", serviceTeam, err)
+		http.Error(w, "team created but error in preparing response", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetTeam(w http.ResponseWriter, r *http.Request) {
+	// get the team id
+	teamIDStr := r.URL.Query().Get("team_id")
+
+	// parse
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the team
+	team, err := a.TeamServiceConfig.GetTeamByID(r.Context(), teamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(team)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", team, err)
+		http.Error(w, "cannot send team, internal error", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetUserTeams(w http.ResponseWriter, r *http.Request) {
+	// get the teams of the user
+	teams, err := a.TeamServiceConfig.GetUserTeams(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(teams)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", teams, err)
+		http.Error(w, "cannot send teams, internal error", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerLeaveTeam(w http.ResponseWriter, r *http.Request) {
+	// get the team id
+	teamIDStr := r.URL.Query().Get("team_id")
+
+	// parse
+	teamID, err := uuid.Parse(teamIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// leave
+	err = a.TeamServiceConfig.LeaveTeam(r.Context(), teamID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("left the team successfully"))
+}
+
+func (a *Api) HandlerInviteToTeam(w http.ResponseWriter, r *http.Request) {
+	// parse from body
+	var request team_service.InviteRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// invite
+	err = a.TeamServiceConfig.InviteToTeam(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("invited successfully"))
+}
+
+func (a *Api) HandlerGetTeamInvites(w http.ResponseWriter, r *http.Request) {
+	// get the invites of the user
+	invites, err := a.TeamServiceConfig.GetTeamInvites(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(invites)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", invites, err)
+		http.Error(w, "cannot send invites, internal error", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerRespondToTeamInvite(w http.ResponseWriter, r *http.Request) {
+	// parse from body
+	var request team_service.RespondToInviteRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// accept or decline
+	err = a.TeamServiceConfig.RespondToInvite(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	if request.Accept {
+		respondWithJson(w, http.StatusOK, []byte("invite accepted"))
+	} else {
+		respondWithJson(w, http.StatusOK, []byte("invite declined"))
+	}
+}
@@ -0,0 +1,176 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+)
+
+const (
+	maxTestCaseUploadBytes = 256 << 20
+	maxTestCaseMemoryBytes = 32 << 20
+)
+
+func (a *Api) HandlerUploadTestCase(w http.ResponseWriter, r *http.Request) {
+	// parse the multipart form, large files are spilled to disk
+	r.Body = http.MaxBytesReader(w, r.Body, maxTestCaseUploadBytes)
+	err := r.ParseMultipartForm(maxTestCaseMemoryBytes)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	// get the problem id
+	problemID, err := strconv.Atoi(r.FormValue("problem_id"))
+	if err != nil {
+		http.Error(w, "invalid problem id, problem id must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	// get the metadata
+	request := problem_service.UploadTestCaseRequest{
+		ProblemID: int32(problemID),
+	}
+	if group := r.FormValue("group"); group != "" {
+		request.Group = &group
+	}
+	if pointsStr := r.FormValue("points"); pointsStr != "" {
+		points, err := strconv.Atoi(pointsStr)
+		if err != nil {
+			http.Error(w, "invalid points, points must be an integer", http.StatusBadRequest)
+			return
+		}
+		request.Points = int32(points)
+	}
+
+	// read the data
+	request.Input, err = readFormFile(r, "input")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Output, err = readFormFile(r, "output")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// upload using service
+	testCase, err := a.ProblemServiceConfig.UploadTestCase(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(testCase)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", testCase, err)
+		http.Error(
+			w, "test case uploaded but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusCreated, response)
+}
+
+func (a *Api) HandlerGetTestCases(w http.ResponseWriter, r *http.Request) {
+	// get problem id
+	problemID, err := strconv.Atoi(r.URL.Query().Get("problem_id"))
+	if err != nil {
+		http.Error(w, "invalid problem id, problem id must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	// fetch using service
+	testCases, err := a.ProblemServiceConfig.GetTestCases(r.Context(), int32(problemID))
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(testCases)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", testCases, err)
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerReorderTestCases(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request problem_service.ReorderTestCasesRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// reorder using service
+	testCases, err := a.ProblemServiceConfig.ReorderTestCases(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(testCases)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", testCases, err)
+		http.Error(
+			w, "test cases reordered but error in preparing response",
+			http.StatusInternalServerError,
+		)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerDeleteTestCase(w http.ResponseWriter, r *http.Request) {
+	// get the id
+	testCaseIDStr := r.URL.Query().Get("test_case_id")
+
+	// parse
+	testCaseID, err := uuid.Parse(testCaseIDStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// delete using service
+	err = a.ProblemServiceConfig.DeleteTestCase(r.Context(), testCaseID)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("test case deleted successfully"))
+}
+
+func readFormFile(r *http.Request, field string) ([]byte, error) {
+	file, _, err := r.FormFile(field)
+	if err != nil {
+		return nil, fmt.Errorf("%s file is required, %w", field, err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s file, %w", field, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/tournament_service"
+)
+
+func (a *Api) HandlerGetTournamentStandings(w http.ResponseWriter, r *http.Request) {
+	// get the tournament id
+	tournamentID, err := uuid.Parse(r.URL.Query().Get("tournament_id"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// get the page number
+	pageNumber, err := strconv.Atoi(r.URL.Query().Get("page_number"))
+	if err != nil {
+		http.Error(w, "invalid page number", http.StatusBadRequest)
+		return
+	}
+
+	// get page size
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil {
+		http.Error(w, "invalid page size", http.StatusBadRequest)
+		return
+	}
+
+	// get the standings
+	standings, err := a.TournamentServiceConfig.GetTournamentStandings(
+		r.Context(),
+		tournament_service.GetStandingsRequest{
+			TournamentID: tournamentID,
+			PageNumber:   int32(pageNumber),
+			PageSize:     int32(pageSize),
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(standings)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", standings, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerUpdateRoundScoring(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		RoundNumber  int32     `json:"round_number"`
+		TournamentID uuid.UUID `json:"tournament_id"`
+		Scoring      string    `json:"scoring"`
+		BestN        *int32    `json:"best_n"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update using service
+	err = a.TournamentServiceConfig.UpdateRoundScoring(
+		r.Context(),
+		tournament_service.UpdateRoundScoringRequest{
+			TournamentID: request.TournamentID,
+			RoundNumber:  request.RoundNumber,
+			Scoring:      tournament_service.RoundScoring(request.Scoring),
+			BestN:        request.BestN,
+		},
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("round scoring updated successfully"))
+}
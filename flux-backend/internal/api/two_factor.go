@@ -0,0 +1,168 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+func (a *Api) HandlerLoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	// get the challenge and the code
+	type params struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		msg := fmt.Sprintf("invalid request payload, %s", err.Error())
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// finish the login
+	userLoginResponse, tokens, err := a.AuthServiceConfig.CompleteTwoFactorLogin(
+		r.Context(),
+		request.ChallengeToken,
+		request.Code,
+	)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	responseBytes, err := json.Marshal(userLoginResponse)
+	if err != nil {
+		log.WithField("resonse", userLoginResponse).Errorf("unable to marshal login response %v", err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	// set session cookies
+	setSessionCookies(w, tokens)
+
+	log.WithFields(log.Fields{
+		"user_name": userLoginResponse.UserName,
+		"roll_no":   userLoginResponse.RollNo,
+	}).Info("logged in with 2fa")
+
+	respondWithJson(w, http.StatusOK, responseBytes)
+}
+
+func (a *Api) HandlerLoginTwoFactorEnroll(w http.ResponseWriter, r *http.Request) {
+	// get the challenge
+	type params struct {
+		ChallengeToken string `json:"challenge_token"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		msg := fmt.Sprintf("invalid request payload, %s", err.Error())
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// enroll
+	enrollment, err := a.AuthServiceConfig.EnrollTwoFactorForChallenge(r.Context(), request.ChallengeToken)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithTwoFactorResult(w, enrollment)
+}
+
+func (a *Api) HandlerGetTwoFactorStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := a.AuthServiceConfig.GetTwoFactorStatus(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithTwoFactorResult(w, status)
+}
+
+func (a *Api) HandlerEnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	enrollment, err := a.AuthServiceConfig.EnrollTwoFactor(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithTwoFactorResult(w, enrollment)
+}
+
+func (a *Api) HandlerConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	// enable 2fa
+	recoveryCodes, err := a.AuthServiceConfig.ConfirmTwoFactor(r.Context(), code)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithTwoFactorResult(w, map[string][]string{"recovery_codes": recoveryCodes})
+}
+
+func (a *Api) HandlerRegenerateRecoveryCodes(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	// replace the recovery codes
+	recoveryCodes, err := a.AuthServiceConfig.RegenerateRecoveryCodes(r.Context(), code)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithTwoFactorResult(w, map[string][]string{"recovery_codes": recoveryCodes})
+}
+
+func (a *Api) HandlerDisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	code, ok := decodeTwoFactorCode(w, r)
+	if !ok {
+		return
+	}
+
+	// disable 2fa
+	err := a.AuthServiceConfig.DisableTwoFactor(r.Context(), code)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("2fa disabled successfully"))
+}
+
+func decodeTwoFactorCode(w http.ResponseWriter, r *http.Request) (string, bool) {
+	type params struct {
+		Code string `json:"code"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		msg := fmt.Sprintf("invalid request payload, %s", err.Error())
+		http.Error(w, msg, http.StatusBadRequest)
+		return "", false
+	}
+	return request.Code, true
+}
+
+func respondWithTwoFactorResult(w http.ResponseWriter, result any) {
+	responseBytes, err := json.Marshal(result)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", result, err)
+		http.Error(w, "internal error. please try again later", http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, responseBytes)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (a *Api) HandlerGetMyProfile(w http.ResponseWriter, r *http.Request) {
+	// get the profile
+	profile, err := a.UserServiceConfig.GetMyProfile(r.Context())
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(profile)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", profile, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerUpdateMyProfile(w http.ResponseWriter, r *http.Request) {
+	// parse the request
+	var request user_service.UpdateProfileRequest
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// update
+	profile, err := a.UserServiceConfig.UpdateMyProfile(r.Context(), request)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(profile)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", profile, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerGetUserProfile(w http.ResponseWriter, r *http.Request) {
+	// get the user name
+	userName := r.URL.Query().Get("user_name")
+	if userName == "" {
+		http.Error(w, "user_name is required", http.StatusBadRequest)
+		return
+	}
+
+	// get the profile
+	profile, err := a.UserServiceConfig.GetPublicProfile(r.Context(), userName)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	// marshal
+	response, err := json.Marshal(profile)
+	if err != nil {
+		log.Errorf("cannot marshal %v, %v", profile, err.Error())
+		http.Error(w, flux_errors.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, response)
+}
+
+func (a *Api) HandlerChangeEmailSendMail(w http.ResponseWriter, r *http.Request) {
+	// extract the new email
+	newEmail := r.URL.Query().Get("email")
+
+	// send the token to the new email
+	err := a.AuthServiceConfig.ChangeEmailSendMail(r.Context(), newEmail)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("sent verification token to your new email. please check once"))
+}
+
+func (a *Api) HandlerChangeEmail(w http.ResponseWriter, r *http.Request) {
+	type params struct {
+		Email string `json:"email"`
+	}
+	var request params
+	err := decodeJsonBody(r.Body, &request)
+	if err != nil {
+		msg := fmt.Sprintf("invalid request payload, %s", err.Error())
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	// extract the verification token from header
+	verificationToken, err := extractAuthToken(r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	// change
+	err = a.AuthServiceConfig.ChangeEmail(r.Context(), request.Email, verificationToken)
+	if err != nil {
+		handlerError(err, w)
+		return
+	}
+
+	respondWithJson(w, http.StatusOK, []byte("email changed successfully"))
+}
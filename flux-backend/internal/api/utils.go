@@ -43,6 +43,8 @@ func handlerError(err error, w http.ResponseWriter) {
 			statusCode = http.StatusNotFound
 		case errors.Is(err, flux_errors.ErrUserAlreadyExists):
 			statusCode = http.StatusConflict
+		case errors.Is(err, flux_errors.ErrTooManyRequests):
+			statusCode = http.StatusTooManyRequests
 		case errors.Is(err, flux_errors.ErrCorruptedVerification):
 			fallthrough
 		case errors.Is(err, flux_errors.ErrInvalidRequestCredentials):
@@ -0,0 +1,32 @@
+package blob_store
+
+import (
+	"context"
+	"errors"
+)
+
+const (
+	KeyBlobStore      = "BLOB_STORE"
+	KeyBlobStoreDir   = "BLOB_STORE_DIR"
+	StoreFileSystem   = "fs"
+	StorePostgres     = "postgres"
+	defaultBlobFolder = "flux-blobs"
+)
+
+var (
+	ErrBlobNotFound = errors.New("blob not found")
+)
+
+/*
+	Store keeps opaque blobs like hidden test data outside the regular tables
+	Put returns the key under which the blob can be read back. Name identifies
+	the store, it is saved with the key so that a blob is always read from
+	the store that wrote it
+*/
+
+type Store interface {
+	Name() string
+	Put(ctx context.Context, data []byte) (string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,94 @@
+package blob_store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/uuid"
+)
+
+// FSStore keeps every blob in its own file under Root
+type FSStore struct {
+	Root string
+}
+
+func NewFSStore(root string) (*FSStore, error) {
+	if root == "" {
+		root = filepath.Join(os.TempDir(), defaultBlobFolder)
+	}
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("cannot create blob directory %s, %w", root, err)
+	}
+	return &FSStore{Root: root}, nil
+}
+
+func (s *FSStore) Name() string {
+	return StoreFileSystem
+}
+
+func (s *FSStore) Put(ctx context.Context, data []byte) (string, error) {
+	key := uuid.NewString()
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	// shard by the key prefix to keep directories small
+	if err = os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", fmt.Errorf("cannot create blob directory for %s, %w", key, err)
+	}
+
+	// write to a temporary file first so that a partial blob is never visible
+	tmpPath := path + ".tmp"
+	if err = os.WriteFile(tmpPath, data, 0o640); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("cannot write blob %s, %w", key, err)
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("cannot write blob %s, %w", key, err)
+	}
+
+	return key, nil
+}
+
+func (s *FSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w, %s", ErrBlobNotFound, key)
+		}
+		return nil, fmt.Errorf("cannot read blob %s, %w", key, err)
+	}
+
+	return data, nil
+}
+
+func (s *FSStore) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("cannot delete blob %s, %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *FSStore) path(key string) (string, error) {
+	// keys are always generated by Put, anything else must not escape Root
+	if err := uuid.Validate(key); err != nil {
+		return "", fmt.Errorf("invalid blob key %s, %w", key, err)
+	}
+	return filepath.Join(s.Root, key[:2], key), nil
+}
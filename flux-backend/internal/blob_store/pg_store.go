@@ -0,0 +1,110 @@
+package blob_store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	codeUndefinedObject = "42704"
+)
+
+// PGStore keeps every blob in a postgres large object, the key is its oid
+type PGStore struct {
+	Pool *pgxpool.Pool
+}
+
+func (s *PGStore) Name() string {
+	return StorePostgres
+}
+
+func (s *PGStore) Put(ctx context.Context, data []byte) (string, error) {
+	var oid uint32
+	err := pgx.BeginFunc(ctx, s.Pool, func(tx pgx.Tx) error {
+		los := tx.LargeObjects()
+		var err error
+		oid, err = los.Create(ctx, 0)
+		if err != nil {
+			return err
+		}
+		obj, err := los.Open(ctx, oid, pgx.LargeObjectModeWrite)
+		if err != nil {
+			return err
+		}
+		if _, err = obj.Write(data); err != nil {
+			return err
+		}
+		return obj.Close()
+	})
+	if err != nil {
+		return "", fmt.Errorf("cannot write large object, %w", err)
+	}
+
+	return strconv.FormatUint(uint64(oid), 10), nil
+}
+
+func (s *PGStore) Get(ctx context.Context, key string) ([]byte, error) {
+	oid, err := parseOID(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	err = pgx.BeginFunc(ctx, s.Pool, func(tx pgx.Tx) error {
+		los := tx.LargeObjects()
+		obj, err := los.Open(ctx, oid, pgx.LargeObjectModeRead)
+		if err != nil {
+			return err
+		}
+		data, err = io.ReadAll(obj)
+		if err != nil {
+			return err
+		}
+		return obj.Close()
+	})
+	if err != nil {
+		if isUndefinedObject(err) {
+			return nil, fmt.Errorf("%w, %s", ErrBlobNotFound, key)
+		}
+		return nil, fmt.Errorf("cannot read large object %s, %w", key, err)
+	}
+
+	return data, nil
+}
+
+func (s *PGStore) Delete(ctx context.Context, key string) error {
+	oid, err := parseOID(key)
+	if err != nil {
+		return err
+	}
+
+	err = pgx.BeginFunc(ctx, s.Pool, func(tx pgx.Tx) error {
+		los := tx.LargeObjects()
+		return los.Unlink(ctx, oid)
+	})
+	if err != nil && !isUndefinedObject(err) {
+		return fmt.Errorf("cannot delete large object %s, %w", key, err)
+	}
+
+	return nil
+}
+
+func parseOID(key string) (uint32, error) {
+	oid, err := strconv.ParseUint(key, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid large object key %s, %w", key, err)
+	}
+	return uint32(oid), nil
+}
+
+func isUndefinedObject(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == codeUndefinedObject
+}
@@ -38,6 +38,28 @@ func (q *Queries) AddProblemToContest(ctx context.Context, arg AddProblemToConte
 	return i, err
 }
 
+const countContestTeams = `-- name: CountContestTeams :one
+SELECT COUNT(*) FROM contest_registered_teams WHERE contest_id=$1
+`
+
+func (q *Queries) CountContestTeams(ctx context.Context, contestID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countContestTeams, contestID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countContestUsers = `-- name: CountContestUsers :one
+SELECT COUNT(*) FROM contest_registered_users WHERE contest_id=$1
+`
+
+func (q *Queries) CountContestUsers(ctx context.Context, contestID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countContestUsers, contestID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createContest = `-- name: CreateContest :one
 INSERT INTO contests (
     title,
@@ -45,25 +67,40 @@ INSERT INTO contests (
     start_time,
     end_time,
     is_published,
-    lock_id
+    lock_id,
+    self_registration,
+    registration_start,
+    registration_end,
+    max_participants,
+    team_contest
 ) VALUES (
     $1,
     $2,
     $3,
     $4,
     $5,
-    $6
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11
 )
-RETURNING id, title, created_by, created_at, updated_at, start_time, end_time, is_published, lock_id
+RETURNING id, title, created_by, created_at, updated_at, start_time, end_time, is_published, lock_id, self_registration, registration_start, registration_end, max_participants, team_contest
 `
 
 type CreateContestParams struct {
-	Title       string     `json:"title"`
-	CreatedBy   uuid.UUID  `json:"created_by"`
-	StartTime   *time.Time `json:"start_time"`
-	EndTime     time.Time  `json:"end_time"`
-	IsPublished bool       `json:"is_published"`
-	LockID      *uuid.UUID `json:"lock_id"`
+	Title             string     `json:"title"`
+	CreatedBy         uuid.UUID  `json:"created_by"`
+	StartTime         *time.Time `json:"start_time"`
+	EndTime           time.Time  `json:"end_time"`
+	IsPublished       bool       `json:"is_published"`
+	LockID            *uuid.UUID `json:"lock_id"`
+	SelfRegistration  bool       `json:"self_registration"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MaxParticipants   *int32     `json:"max_participants"`
+	TeamContest       bool       `json:"team_contest"`
 }
 
 func (q *Queries) CreateContest(ctx context.Context, arg CreateContestParams) (Contest, error) {
@@ -74,6 +111,11 @@ func (q *Queries) CreateContest(ctx context.Context, arg CreateContestParams) (C
 		arg.EndTime,
 		arg.IsPublished,
 		arg.LockID,
+		arg.SelfRegistration,
+		arg.RegistrationStart,
+		arg.RegistrationEnd,
+		arg.MaxParticipants,
+		arg.TeamContest,
 	)
 	var i Contest
 	err := row.Scan(
@@ -86,6 +128,11 @@ func (q *Queries) CreateContest(ctx context.Context, arg CreateContestParams) (C
 		&i.EndTime,
 		&i.IsPublished,
 		&i.LockID,
+		&i.SelfRegistration,
+		&i.RegistrationStart,
+		&i.RegistrationEnd,
+		&i.MaxParticipants,
+		&i.TeamContest,
 	)
 	return i, err
 }
@@ -119,7 +166,7 @@ func (q *Queries) DeleteUsersByContestId(ctx context.Context, contestID uuid.UUI
 
 const getContestByID = `-- name: GetContestByID :one
 SELECT 
-    contests.id, contests.title, contests.created_by, contests.created_at, contests.updated_at, contests.start_time, contests.end_time, contests.is_published, contests.lock_id,
+    contests.id, contests.title, contests.created_by, contests.created_at, contests.updated_at, contests.start_time, contests.end_time, contests.is_published, contests.lock_id, contests.self_registration, contests.registration_start, contests.registration_end, contests.max_participants, contests.team_contest,
     locks.timeout as lock_timeout,
     locks.access
 FROM contests
@@ -129,17 +176,22 @@ WHERE contests.id=$1
 `
 
 type GetContestByIDRow struct {
-	ID          uuid.UUID  `json:"id"`
-	Title       string     `json:"title"`
-	CreatedBy   uuid.UUID  `json:"created_by"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	StartTime   *time.Time `json:"start_time"`
-	EndTime     time.Time  `json:"end_time"`
-	IsPublished bool       `json:"is_published"`
-	LockID      *uuid.UUID `json:"lock_id"`
-	LockTimeout *time.Time `json:"lock_timeout"`
-	Access      *string    `json:"access"`
+	ID                uuid.UUID  `json:"id"`
+	Title             string     `json:"title"`
+	CreatedBy         uuid.UUID  `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	StartTime         *time.Time `json:"start_time"`
+	EndTime           time.Time  `json:"end_time"`
+	IsPublished       bool       `json:"is_published"`
+	LockID            *uuid.UUID `json:"lock_id"`
+	SelfRegistration  bool       `json:"self_registration"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MaxParticipants   *int32     `json:"max_participants"`
+	TeamContest       bool       `json:"team_contest"`
+	LockTimeout       *time.Time `json:"lock_timeout"`
+	Access            *string    `json:"access"`
 }
 
 func (q *Queries) GetContestByID(ctx context.Context, id uuid.UUID) (GetContestByIDRow, error) {
@@ -155,6 +207,11 @@ func (q *Queries) GetContestByID(ctx context.Context, id uuid.UUID) (GetContestB
 		&i.EndTime,
 		&i.IsPublished,
 		&i.LockID,
+		&i.SelfRegistration,
+		&i.RegistrationStart,
+		&i.RegistrationEnd,
+		&i.MaxParticipants,
+		&i.TeamContest,
 		&i.LockTimeout,
 		&i.Access,
 	)
@@ -185,6 +242,45 @@ func (q *Queries) GetContestProblemsByContestID(ctx context.Context, contestID u
 	return items, nil
 }
 
+const getContestTeams = `-- name: GetContestTeams :many
+SELECT
+    t.id,
+    t.name
+FROM
+    contest_registered_teams AS crt
+JOIN
+    teams AS t ON crt.team_id = t.id
+WHERE
+    crt.contest_id = $1
+ORDER BY
+    t.name
+`
+
+type GetContestTeamsRow struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+func (q *Queries) GetContestTeams(ctx context.Context, contestID uuid.UUID) ([]GetContestTeamsRow, error) {
+	rows, err := q.db.Query(ctx, getContestTeams, contestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetContestTeamsRow
+	for rows.Next() {
+		var i GetContestTeamsRow
+		if err := rows.Scan(&i.ID, &i.Name); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getContestUsers = `-- name: GetContestUsers :many
 SELECT user_id FROM contest_registered_users WHERE contest_id=$1
 `
@@ -333,21 +429,125 @@ func (q *Queries) GetUserRegisteredContests(ctx context.Context, userID uuid.UUI
 	return items, nil
 }
 
+const getUserTeamInContest = `-- name: GetUserTeamInContest :one
+SELECT
+    crt.team_id
+FROM
+    contest_registered_teams AS crt
+JOIN
+    team_members AS tm ON crt.team_id = tm.team_id
+WHERE
+    crt.contest_id = $1 AND tm.user_id = $2
+`
+
+type GetUserTeamInContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) GetUserTeamInContest(ctx context.Context, arg GetUserTeamInContestParams) (uuid.UUID, error) {
+	row := q.db.QueryRow(ctx, getUserTeamInContest, arg.ContestID, arg.UserID)
+	var team_id uuid.UUID
+	err := row.Scan(&team_id)
+	return team_id, err
+}
+
+const hasTeamMemberInOtherContestTeam = `-- name: HasTeamMemberInOtherContestTeam :one
+SELECT EXISTS(
+    SELECT tm.user_id FROM
+     team_members AS tm
+    JOIN
+     contest_registered_teams AS crt ON tm.team_id = crt.team_id
+    WHERE crt.contest_id = $1
+    AND crt.team_id <> $2
+    AND tm.user_id IN (SELECT user_id FROM team_members WHERE team_id = $2)
+)
+`
+
+type HasTeamMemberInOtherContestTeamParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	TeamID    uuid.UUID `json:"team_id"`
+}
+
+// a user can participate through only one team in a contest
+func (q *Queries) HasTeamMemberInOtherContestTeam(ctx context.Context, arg HasTeamMemberInOtherContestTeamParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasTeamMemberInOtherContestTeam, arg.ContestID, arg.TeamID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const isProblemInContest = `-- name: IsProblemInContest :one
+SELECT EXISTS(
+    SELECT contest_id, problem_id FROM
+     contest_problems WHERE contest_id=$1 AND problem_id=$2
+)
+`
+
+type IsProblemInContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	ProblemID int32     `json:"problem_id"`
+}
+
+func (q *Queries) IsProblemInContest(ctx context.Context, arg IsProblemInContestParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isProblemInContest, arg.ContestID, arg.ProblemID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const isUserRegisteredInContest = `-- name: IsUserRegisteredInContest :one
 SELECT EXISTS(
     SELECT contest_id, user_id FROM
-     contest_registered_users WHERE contest_id=$1 AND user_id=$1
+     contest_registered_users WHERE contest_id=$1 AND user_id=$2
 )
 `
 
-func (q *Queries) IsUserRegisteredInContest(ctx context.Context, contestID uuid.UUID) (bool, error) {
-	row := q.db.QueryRow(ctx, isUserRegisteredInContest, contestID)
+type IsUserRegisteredInContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) IsUserRegisteredInContest(ctx context.Context, arg IsUserRegisteredInContestParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isUserRegisteredInContest, arg.ContestID, arg.UserID)
 	var exists bool
 	err := row.Scan(&exists)
 	return exists, err
 }
 
-const registerUserToContest = `-- name: RegisterUserToContest :one
+const lockContestForUpdate = `-- name: LockContestForUpdate :one
+SELECT max_participants FROM contests WHERE id=$1 FOR UPDATE
+`
+
+func (q *Queries) LockContestForUpdate(ctx context.Context, id uuid.UUID) (*int32, error) {
+	row := q.db.QueryRow(ctx, lockContestForUpdate, id)
+	var max_participants *int32
+	err := row.Scan(&max_participants)
+	return max_participants, err
+}
+
+const registerTeamToContest = `-- name: RegisterTeamToContest :exec
+INSERT INTO contest_registered_teams (
+    team_id,
+    contest_id
+) VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (team_id, contest_id) DO NOTHING
+`
+
+type RegisterTeamToContestParams struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+}
+
+func (q *Queries) RegisterTeamToContest(ctx context.Context, arg RegisterTeamToContestParams) error {
+	_, err := q.db.Exec(ctx, registerTeamToContest, arg.TeamID, arg.ContestID)
+	return err
+}
+
+const registerUserToContest = `-- name: RegisterUserToContest :exec
 INSERT INTO contest_registered_users (
     user_id,
     contest_id
@@ -355,7 +555,7 @@ INSERT INTO contest_registered_users (
     $1,
     $2
 )
-RETURNING user_id, contest_id
+ON CONFLICT (user_id, contest_id) DO NOTHING
 `
 
 type RegisterUserToContestParams struct {
@@ -363,11 +563,18 @@ type RegisterUserToContestParams struct {
 	ContestID uuid.UUID `json:"contest_id"`
 }
 
-func (q *Queries) RegisterUserToContest(ctx context.Context, arg RegisterUserToContestParams) (ContestRegisteredUser, error) {
-	row := q.db.QueryRow(ctx, registerUserToContest, arg.UserID, arg.ContestID)
-	var i ContestRegisteredUser
-	err := row.Scan(&i.UserID, &i.ContestID)
-	return i, err
+func (q *Queries) RegisterUserToContest(ctx context.Context, arg RegisterUserToContestParams) error {
+	_, err := q.db.Exec(ctx, registerUserToContest, arg.UserID, arg.ContestID)
+	return err
+}
+
+const unRegisterContestTeams = `-- name: UnRegisterContestTeams :exec
+DELETE FROM contest_registered_teams WHERE contest_id=$1
+`
+
+func (q *Queries) UnRegisterContestTeams(ctx context.Context, contestID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, unRegisterContestTeams, contestID)
+	return err
 }
 
 const unRegisterContestUsers = `-- name: UnRegisterContestUsers :exec
@@ -379,6 +586,40 @@ func (q *Queries) UnRegisterContestUsers(ctx context.Context, contestID uuid.UUI
 	return err
 }
 
+const unregisterTeamFromContest = `-- name: UnregisterTeamFromContest :execrows
+DELETE FROM contest_registered_teams WHERE contest_id=$1 AND team_id=$2
+`
+
+type UnregisterTeamFromContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	TeamID    uuid.UUID `json:"team_id"`
+}
+
+func (q *Queries) UnregisterTeamFromContest(ctx context.Context, arg UnregisterTeamFromContestParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unregisterTeamFromContest, arg.ContestID, arg.TeamID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const unregisterUserFromContest = `-- name: UnregisterUserFromContest :execrows
+DELETE FROM contest_registered_users WHERE contest_id=$1 AND user_id=$2
+`
+
+type UnregisterUserFromContestParams struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserID    uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) UnregisterUserFromContest(ctx context.Context, arg UnregisterUserFromContestParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unregisterUserFromContest, arg.ContestID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const unsetContestProblems = `-- name: UnsetContestProblems :exec
 DELETE FROM contest_problems WHERE contest_id=$1
 `
@@ -392,16 +633,24 @@ const updateContest = `-- name: UpdateContest :one
 UPDATE contests SET
     title=$1,
     start_time=$2,
-    end_time=$3
+    end_time=$3,
+    self_registration=$5,
+    registration_start=$6,
+    registration_end=$7,
+    max_participants=$8
 WHERE id=$4
-RETURNING id, title, created_by, created_at, updated_at, start_time, end_time, is_published, lock_id
+RETURNING id, title, created_by, created_at, updated_at, start_time, end_time, is_published, lock_id, self_registration, registration_start, registration_end, max_participants, team_contest
 `
 
 type UpdateContestParams struct {
-	Title     string     `json:"title"`
-	StartTime *time.Time `json:"start_time"`
-	EndTime   time.Time  `json:"end_time"`
-	ID        uuid.UUID  `json:"id"`
+	Title             string     `json:"title"`
+	StartTime         *time.Time `json:"start_time"`
+	EndTime           time.Time  `json:"end_time"`
+	ID                uuid.UUID  `json:"id"`
+	SelfRegistration  bool       `json:"self_registration"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MaxParticipants   *int32     `json:"max_participants"`
 }
 
 func (q *Queries) UpdateContest(ctx context.Context, arg UpdateContestParams) (Contest, error) {
@@ -410,6 +659,10 @@ func (q *Queries) UpdateContest(ctx context.Context, arg UpdateContestParams) (C
 		arg.StartTime,
 		arg.EndTime,
 		arg.ID,
+		arg.SelfRegistration,
+		arg.RegistrationStart,
+		arg.RegistrationEnd,
+		arg.MaxParticipants,
 	)
 	var i Contest
 	err := row.Scan(
@@ -422,6 +675,11 @@ func (q *Queries) UpdateContest(ctx context.Context, arg UpdateContestParams) (C
 		&i.EndTime,
 		&i.IsPublished,
 		&i.LockID,
+		&i.SelfRegistration,
+		&i.RegistrationStart,
+		&i.RegistrationEnd,
+		&i.MaxParticipants,
+		&i.TeamContest,
 	)
 	return i, err
 }
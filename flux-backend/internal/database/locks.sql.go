@@ -12,21 +12,67 @@ import (
 	"github.com/google/uuid"
 )
 
+const addLockAllowedUsers = `-- name: AddLockAllowedUsers :execrows
+INSERT INTO lock_allowed_users (lock_id, user_id)
+SELECT $1, id FROM users WHERE user_name = ANY($2::text[])
+ON CONFLICT DO NOTHING
+`
+
+type AddLockAllowedUsersParams struct {
+	LockID    uuid.UUID `json:"lock_id"`
+	UserNames []string  `json:"user_names"`
+}
+
+func (q *Queries) AddLockAllowedUsers(ctx context.Context, arg AddLockAllowedUsersParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addLockAllowedUsers, arg.LockID, arg.UserNames)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimLockRelease = `-- name: ClaimLockRelease :execrows
+INSERT INTO lock_release_events (lock_id, timeout)
+SELECT id, timeout FROM locks
+WHERE id = $1 AND lock_type = 'timer' AND timeout <= NOW()
+ON CONFLICT (lock_id) DO UPDATE SET claimed_at = NOW()
+WHERE
+    lock_release_events.published_at IS NULL
+    AND lock_release_events.claimed_at < $2
+`
+
+type ClaimLockReleaseParams struct {
+	ID          uuid.UUID `json:"id"`
+	StaleBefore time.Time `json:"stale_before"`
+}
+
+// only one claim of an expired timer lock succeeds,
+// an unpublished claim older than stale_before can be taken again
+func (q *Queries) ClaimLockRelease(ctx context.Context, arg ClaimLockReleaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimLockRelease, arg.ID, arg.StaleBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const createLock = `-- name: CreateLock :one
 INSERT INTO locks (
     name,
     created_by,
     description,
     lock_type,
-    timeout
+    timeout,
+    access
 ) VALUES (
     $1, -- name
     $2, -- created_by
     $3, -- description
     $4, -- lock_type: either timer or manual
-    $5  -- timeout: null only if manual
+    $5, -- timeout: null only if manual
+    $6  -- access: role that can see the locked entities before expiry
 )
-RETURNING id, name, created_by, created_at, description, access, lock_type, timeout
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at
 `
 
 type CreateLockParams struct {
@@ -35,6 +81,7 @@ type CreateLockParams struct {
 	Description string     `json:"description"`
 	LockType    LockType   `json:"lock_type"`
 	Timeout     *time.Time `json:"timeout"`
+	Access      string     `json:"access"`
 }
 
 func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, error) {
@@ -44,6 +91,7 @@ func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, e
 		arg.Description,
 		arg.LockType,
 		arg.Timeout,
+		arg.Access,
 	)
 	var i Lock
 	err := row.Scan(
@@ -55,10 +103,55 @@ func (q *Queries) CreateLock(ctx context.Context, arg CreateLockParams) (Lock, e
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
 	)
 	return i, err
 }
 
+const createLockAuditLog = `-- name: CreateLockAuditLog :one
+INSERT INTO lock_audit_logs (lock_id, lock_name, action, performed_by, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, lock_id, action, performed_by, performed_at, note, lock_name
+`
+
+type CreateLockAuditLogParams struct {
+	LockID      *uuid.UUID `json:"lock_id"`
+	LockName    string     `json:"lock_name"`
+	Action      LockAction `json:"action"`
+	PerformedBy uuid.UUID  `json:"performed_by"`
+	Note        string     `json:"note"`
+}
+
+func (q *Queries) CreateLockAuditLog(ctx context.Context, arg CreateLockAuditLogParams) (LockAuditLog, error) {
+	row := q.db.QueryRow(ctx, createLockAuditLog,
+		arg.LockID,
+		arg.LockName,
+		arg.Action,
+		arg.PerformedBy,
+		arg.Note,
+	)
+	var i LockAuditLog
+	err := row.Scan(
+		&i.ID,
+		&i.LockID,
+		&i.Action,
+		&i.PerformedBy,
+		&i.PerformedAt,
+		&i.Note,
+		&i.LockName,
+	)
+	return i, err
+}
+
+const deleteLockAllowedUsers = `-- name: DeleteLockAllowedUsers :exec
+DELETE FROM lock_allowed_users WHERE lock_id = $1
+`
+
+func (q *Queries) DeleteLockAllowedUsers(ctx context.Context, lockID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteLockAllowedUsers, lockID)
+	return err
+}
+
 const deleteLockById = `-- name: DeleteLockById :exec
 DELETE FROM locks 
 WHERE id=$1
@@ -69,8 +162,125 @@ func (q *Queries) DeleteLockById(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const deleteLockReleaseEvent = `-- name: DeleteLockReleaseEvent :exec
+DELETE FROM lock_release_events WHERE lock_id = $1
+`
+
+func (q *Queries) DeleteLockReleaseEvent(ctx context.Context, lockID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteLockReleaseEvent, lockID)
+	return err
+}
+
+const engageManualLock = `-- name: EngageManualLock :one
+UPDATE locks SET released_at = NULL
+WHERE id = $1 AND lock_type = 'manual' AND released_at IS NOT NULL
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at
+`
+
+func (q *Queries) EngageManualLock(ctx context.Context, id uuid.UUID) (Lock, error) {
+	row := q.db.QueryRow(ctx, engageManualLock, id)
+	var i Lock
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Description,
+		&i.Access,
+		&i.LockType,
+		&i.Timeout,
+		&i.ReleasedAt,
+	)
+	return i, err
+}
+
+const getLockAllowedUsers = `-- name: GetLockAllowedUsers :many
+SELECT u.id, u.user_name, u.roll_no
+FROM lock_allowed_users a
+JOIN users u ON a.user_id = u.id
+WHERE a.lock_id = $1
+ORDER BY u.user_name
+`
+
+type GetLockAllowedUsersRow struct {
+	ID       uuid.UUID `json:"id"`
+	UserName string    `json:"user_name"`
+	RollNo   string    `json:"roll_no"`
+}
+
+func (q *Queries) GetLockAllowedUsers(ctx context.Context, lockID uuid.UUID) ([]GetLockAllowedUsersRow, error) {
+	rows, err := q.db.Query(ctx, getLockAllowedUsers, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockAllowedUsersRow
+	for rows.Next() {
+		var i GetLockAllowedUsersRow
+		if err := rows.Scan(&i.ID, &i.UserName, &i.RollNo); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockAuditLogs = `-- name: GetLockAuditLogs :many
+SELECT
+    a.id,
+    a.action,
+    a.performed_at,
+    a.note,
+    u.user_name as performed_by
+FROM
+    lock_audit_logs a
+JOIN
+    users u ON a.performed_by = u.id
+WHERE
+    a.lock_id = $1
+ORDER BY
+    a.performed_at DESC
+`
+
+type GetLockAuditLogsRow struct {
+	ID          uuid.UUID  `json:"id"`
+	Action      LockAction `json:"action"`
+	PerformedAt time.Time  `json:"performed_at"`
+	Note        string     `json:"note"`
+	PerformedBy string     `json:"performed_by"`
+}
+
+func (q *Queries) GetLockAuditLogs(ctx context.Context, lockID *uuid.UUID) ([]GetLockAuditLogsRow, error) {
+	rows, err := q.db.Query(ctx, getLockAuditLogs, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockAuditLogsRow
+	for rows.Next() {
+		var i GetLockAuditLogsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Action,
+			&i.PerformedAt,
+			&i.Note,
+			&i.PerformedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLockById = `-- name: GetLockById :one
-SELECT id, name, created_by, created_at, description, access, lock_type, timeout FROM locks WHERE id=$1
+SELECT id, name, created_by, created_at, description, access, lock_type, timeout, released_at FROM locks WHERE id=$1
 `
 
 func (q *Queries) GetLockById(ctx context.Context, groupD uuid.UUID) (Lock, error) {
@@ -85,12 +295,190 @@ func (q *Queries) GetLockById(ctx context.Context, groupD uuid.UUID) (Lock, erro
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
 	)
 	return i, err
 }
 
+const getLockContestRecipients = `-- name: GetLockContestRecipients :many
+SELECT DISTINCT c.id as contest_id, c.title, u.email
+FROM contests c
+JOIN (
+    SELECT contest_id, user_id FROM contest_registered_users
+    UNION
+    SELECT rt.contest_id, tm.user_id
+    FROM contest_registered_teams rt
+    JOIN team_members tm ON rt.team_id = tm.team_id
+) r ON r.contest_id = c.id
+JOIN users u ON r.user_id = u.id
+WHERE c.lock_id = $1
+`
+
+type GetLockContestRecipientsRow struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	Title     string    `json:"title"`
+	Email     string    `json:"email"`
+}
+
+// registered users and members of registered teams of the contests of a lock
+func (q *Queries) GetLockContestRecipients(ctx context.Context, lockID *uuid.UUID) ([]GetLockContestRecipientsRow, error) {
+	rows, err := q.db.Query(ctx, getLockContestRecipients, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockContestRecipientsRow
+	for rows.Next() {
+		var i GetLockContestRecipientsRow
+		if err := rows.Scan(&i.ContestID, &i.Title, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockContests = `-- name: GetLockContests :many
+SELECT id, title, end_time FROM contests WHERE lock_id = $1 ORDER BY end_time
+`
+
+type GetLockContestsRow struct {
+	ID      uuid.UUID `json:"id"`
+	Title   string    `json:"title"`
+	EndTime time.Time `json:"end_time"`
+}
+
+func (q *Queries) GetLockContests(ctx context.Context, lockID *uuid.UUID) ([]GetLockContestsRow, error) {
+	rows, err := q.db.Query(ctx, getLockContests, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockContestsRow
+	for rows.Next() {
+		var i GetLockContestsRow
+		if err := rows.Scan(&i.ID, &i.Title, &i.EndTime); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockContestsForUpdate = `-- name: GetLockContestsForUpdate :many
+SELECT id, title, end_time, registration_start, registration_end
+FROM contests WHERE lock_id = $1 FOR UPDATE
+`
+
+type GetLockContestsForUpdateRow struct {
+	ID                uuid.UUID  `json:"id"`
+	Title             string     `json:"title"`
+	EndTime           time.Time  `json:"end_time"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+}
+
+func (q *Queries) GetLockContestsForUpdate(ctx context.Context, lockID *uuid.UUID) ([]GetLockContestsForUpdateRow, error) {
+	rows, err := q.db.Query(ctx, getLockContestsForUpdate, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockContestsForUpdateRow
+	for rows.Next() {
+		var i GetLockContestsForUpdateRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.EndTime,
+			&i.RegistrationStart,
+			&i.RegistrationEnd,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockProblems = `-- name: GetLockProblems :many
+SELECT id, title FROM problems WHERE lock_id = $1 ORDER BY id
+`
+
+type GetLockProblemsRow struct {
+	ID    int32  `json:"id"`
+	Title string `json:"title"`
+}
+
+func (q *Queries) GetLockProblems(ctx context.Context, lockID *uuid.UUID) ([]GetLockProblemsRow, error) {
+	rows, err := q.db.Query(ctx, getLockProblems, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockProblemsRow
+	for rows.Next() {
+		var i GetLockProblemsRow
+		if err := rows.Scan(&i.ID, &i.Title); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLockTournamentRounds = `-- name: GetLockTournamentRounds :many
+SELECT id, tournament_id, round_number, title FROM tournament_rounds
+WHERE lock_id = $1
+ORDER BY tournament_id, round_number
+`
+
+type GetLockTournamentRoundsRow struct {
+	ID           uuid.UUID `json:"id"`
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_number"`
+	Title        string    `json:"title"`
+}
+
+func (q *Queries) GetLockTournamentRounds(ctx context.Context, lockID *uuid.UUID) ([]GetLockTournamentRoundsRow, error) {
+	rows, err := q.db.Query(ctx, getLockTournamentRounds, lockID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetLockTournamentRoundsRow
+	for rows.Next() {
+		var i GetLockTournamentRoundsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.TournamentID,
+			&i.RoundNumber,
+			&i.Title,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLocksByFilter = `-- name: GetLocksByFilter :many
-SELECT id, name, created_by, created_at, description, access, lock_type, timeout FROM locks
+SELECT id, name, created_by, created_at, description, access, lock_type, timeout, released_at FROM locks
 WHERE
     name ILIKE '%' || $1::text || '%'
     AND (
@@ -131,6 +519,48 @@ func (q *Queries) GetLocksByFilter(ctx context.Context, arg GetLocksByFilterPara
 			&i.Access,
 			&i.LockType,
 			&i.Timeout,
+			&i.ReleasedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingTimerLocks = `-- name: GetPendingTimerLocks :many
+SELECT l.id, l.name, l.created_by, l.created_at, l.description, l.access, l.lock_type, l.timeout, l.released_at FROM locks l
+WHERE
+    l.lock_type = 'timer'
+    AND NOT EXISTS (
+        SELECT 1 FROM lock_release_events e
+        WHERE e.lock_id = l.id AND e.published_at IS NOT NULL
+    )
+`
+
+// timer locks whose release was not published yet
+func (q *Queries) GetPendingTimerLocks(ctx context.Context) ([]Lock, error) {
+	rows, err := q.db.Query(ctx, getPendingTimerLocks)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Lock
+	for rows.Next() {
+		var i Lock
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.Description,
+			&i.Access,
+			&i.LockType,
+			&i.Timeout,
+			&i.ReleasedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -142,30 +572,185 @@ func (q *Queries) GetLocksByFilter(ctx context.Context, arg GetLocksByFilterPara
 	return items, nil
 }
 
+const getUserAllowedLockIDs = `-- name: GetUserAllowedLockIDs :many
+SELECT lock_id FROM lock_allowed_users WHERE user_id = $1
+`
+
+func (q *Queries) GetUserAllowedLockIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getUserAllowedLockIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var lock_id uuid.UUID
+		if err := rows.Scan(&lock_id); err != nil {
+			return nil, err
+		}
+		items = append(items, lock_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const isLockUserAllowed = `-- name: IsLockUserAllowed :one
+SELECT EXISTS(
+    SELECT 1 FROM lock_allowed_users WHERE lock_id = $1 AND user_id = $2
+)
+`
+
+type IsLockUserAllowedParams struct {
+	LockID uuid.UUID `json:"lock_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (q *Queries) IsLockUserAllowed(ctx context.Context, arg IsLockUserAllowedParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isLockUserAllowed, arg.LockID, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const markLockReleasePublished = `-- name: MarkLockReleasePublished :exec
+UPDATE lock_release_events SET published_at = NOW() WHERE lock_id = $1
+`
+
+func (q *Queries) MarkLockReleasePublished(ctx context.Context, lockID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markLockReleasePublished, lockID)
+	return err
+}
+
+const reassignLockContests = `-- name: ReassignLockContests :execrows
+UPDATE contests SET lock_id = $1 WHERE lock_id = $2
+`
+
+type ReassignLockContestsParams struct {
+	TargetLockID *uuid.UUID `json:"target_lock_id"`
+	LockID       *uuid.UUID `json:"lock_id"`
+}
+
+func (q *Queries) ReassignLockContests(ctx context.Context, arg ReassignLockContestsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignLockContests, arg.TargetLockID, arg.LockID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignLockProblems = `-- name: ReassignLockProblems :execrows
+UPDATE problems SET lock_id = $1 WHERE lock_id = $2
+`
+
+type ReassignLockProblemsParams struct {
+	TargetLockID *uuid.UUID `json:"target_lock_id"`
+	LockID       *uuid.UUID `json:"lock_id"`
+}
+
+func (q *Queries) ReassignLockProblems(ctx context.Context, arg ReassignLockProblemsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignLockProblems, arg.TargetLockID, arg.LockID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignLockTournamentRounds = `-- name: ReassignLockTournamentRounds :execrows
+UPDATE tournament_rounds SET lock_id = $1 WHERE lock_id = $2
+`
+
+type ReassignLockTournamentRoundsParams struct {
+	TargetLockID *uuid.UUID `json:"target_lock_id"`
+	LockID       *uuid.UUID `json:"lock_id"`
+}
+
+func (q *Queries) ReassignLockTournamentRounds(ctx context.Context, arg ReassignLockTournamentRoundsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignLockTournamentRounds, arg.TargetLockID, arg.LockID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const releaseManualLock = `-- name: ReleaseManualLock :one
+UPDATE locks SET released_at = NOW()
+WHERE id = $1 AND lock_type = 'manual' AND released_at IS NULL
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at
+`
+
+func (q *Queries) ReleaseManualLock(ctx context.Context, id uuid.UUID) (Lock, error) {
+	row := q.db.QueryRow(ctx, releaseManualLock, id)
+	var i Lock
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Description,
+		&i.Access,
+		&i.LockType,
+		&i.Timeout,
+		&i.ReleasedAt,
+	)
+	return i, err
+}
+
+const rescheduleTimerLock = `-- name: RescheduleTimerLock :one
+UPDATE locks SET timeout = $2
+WHERE id = $1 AND lock_type = 'timer' AND timeout > NOW()
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at
+`
+
+type RescheduleTimerLockParams struct {
+	ID      uuid.UUID  `json:"id"`
+	Timeout *time.Time `json:"timeout"`
+}
+
+// only a timer lock that has not expired can be rescheduled
+func (q *Queries) RescheduleTimerLock(ctx context.Context, arg RescheduleTimerLockParams) (Lock, error) {
+	row := q.db.QueryRow(ctx, rescheduleTimerLock, arg.ID, arg.Timeout)
+	var i Lock
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.Description,
+		&i.Access,
+		&i.LockType,
+		&i.Timeout,
+		&i.ReleasedAt,
+	)
+	return i, err
+}
+
 const updateLockDetails = `-- name: UpdateLockDetails :one
 UPDATE locks
 SET
     name = $2,
-    timeout = $3,
-    description = $4
+    description = $3,
+    access = COALESCE($4, access)
 WHERE
     id = $1
-RETURNING id, name, created_by, created_at, description, access, lock_type, timeout
+RETURNING id, name, created_by, created_at, description, access, lock_type, timeout, released_at
 `
 
 type UpdateLockDetailsParams struct {
-	ID          uuid.UUID  `json:"id"`
-	Name        string     `json:"name"`
-	Timeout     *time.Time `json:"timeout"`
-	Description string     `json:"description"`
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Access      *string   `json:"access"`
 }
 
+// the timeout only moves with a reschedule, a null access keeps the current one
 func (q *Queries) UpdateLockDetails(ctx context.Context, arg UpdateLockDetailsParams) (Lock, error) {
 	row := q.db.QueryRow(ctx, updateLockDetails,
 		arg.ID,
 		arg.Name,
-		arg.Timeout,
 		arg.Description,
+		arg.Access,
 	)
 	var i Lock
 	err := row.Scan(
@@ -177,6 +762,7 @@ func (q *Queries) UpdateLockDetails(ctx context.Context, arg UpdateLockDetailsPa
 		&i.Access,
 		&i.LockType,
 		&i.Timeout,
+		&i.ReleasedAt,
 	)
 	return i, err
 }
@@ -13,6 +13,92 @@ import (
 	"github.com/google/uuid"
 )
 
+type CheckerMode string
+
+const (
+	CheckerModeExact  CheckerMode = "exact"
+	CheckerModeToken  CheckerMode = "token"
+	CheckerModeFloat  CheckerMode = "float"
+	CheckerModeCustom CheckerMode = "custom"
+)
+
+func (e *CheckerMode) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = CheckerMode(s)
+	case string:
+		*e = CheckerMode(s)
+	default:
+		return fmt.Errorf("unsupported scan type for CheckerMode: %T", src)
+	}
+	return nil
+}
+
+type NullCheckerMode struct {
+	CheckerMode CheckerMode `json:"checker_mode"`
+	Valid       bool        `json:"valid"` // Valid is true if CheckerMode is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullCheckerMode) Scan(value interface{}) error {
+	if value == nil {
+		ns.CheckerMode, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.CheckerMode.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullCheckerMode) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.CheckerMode), nil
+}
+
+type LockAction string
+
+const (
+	LockActionRelease LockAction = "release"
+	LockActionEngage  LockAction = "engage"
+)
+
+func (e *LockAction) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LockAction(s)
+	case string:
+		*e = LockAction(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LockAction: %T", src)
+	}
+	return nil
+}
+
+type NullLockAction struct {
+	LockAction LockAction `json:"lock_action"`
+	Valid      bool       `json:"valid"` // Valid is true if LockAction is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLockAction) Scan(value interface{}) error {
+	if value == nil {
+		ns.LockAction, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LockAction.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLockAction) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.LockAction), nil
+}
+
 type LockType string
 
 const (
@@ -96,6 +182,93 @@ func (ns NullPlatform) Value() (driver.Value, error) {
 	return string(ns.Platform), nil
 }
 
+type RoundAdvancement string
+
+const (
+	RoundAdvancementNone           RoundAdvancement = "none"
+	RoundAdvancementTopK           RoundAdvancement = "top_k"
+	RoundAdvancementTopKPerContest RoundAdvancement = "top_k_per_contest"
+	RoundAdvancementThreshold      RoundAdvancement = "threshold"
+)
+
+func (e *RoundAdvancement) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RoundAdvancement(s)
+	case string:
+		*e = RoundAdvancement(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RoundAdvancement: %T", src)
+	}
+	return nil
+}
+
+type NullRoundAdvancement struct {
+	RoundAdvancement RoundAdvancement `json:"round_advancement"`
+	Valid            bool             `json:"valid"` // Valid is true if RoundAdvancement is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRoundAdvancement) Scan(value interface{}) error {
+	if value == nil {
+		ns.RoundAdvancement, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RoundAdvancement.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRoundAdvancement) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RoundAdvancement), nil
+}
+
+type RoundScoring string
+
+const (
+	RoundScoringSum      RoundScoring = "sum"
+	RoundScoringBestN    RoundScoring = "best_n"
+	RoundScoringWeighted RoundScoring = "weighted"
+)
+
+func (e *RoundScoring) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RoundScoring(s)
+	case string:
+		*e = RoundScoring(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RoundScoring: %T", src)
+	}
+	return nil
+}
+
+type NullRoundScoring struct {
+	RoundScoring RoundScoring `json:"round_scoring"`
+	Valid        bool         `json:"valid"` // Valid is true if RoundScoring is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRoundScoring) Scan(value interface{}) error {
+	if value == nil {
+		ns.RoundScoring, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RoundScoring.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRoundScoring) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.RoundScoring), nil
+}
+
 type Bot struct {
 	ID          uuid.UUID        `json:"id"`
 	AccountName string           `json:"account_name"`
@@ -103,18 +276,24 @@ type Bot struct {
 	WebsiteData *json.RawMessage `json:"website_data"`
 	CreatedAt   time.Time        `json:"created_at"`
 	UpdatedAt   time.Time        `json:"updated_at"`
+	LeasedUntil *time.Time       `json:"leased_until"`
 }
 
 type Contest struct {
-	ID          uuid.UUID  `json:"id"`
-	Title       string     `json:"title"`
-	CreatedBy   uuid.UUID  `json:"created_by"`
-	CreatedAt   time.Time  `json:"created_at"`
-	UpdatedAt   time.Time  `json:"updated_at"`
-	StartTime   *time.Time `json:"start_time"`
-	EndTime     time.Time  `json:"end_time"`
-	IsPublished bool       `json:"is_published"`
-	LockID      *uuid.UUID `json:"lock_id"`
+	ID                uuid.UUID  `json:"id"`
+	Title             string     `json:"title"`
+	CreatedBy         uuid.UUID  `json:"created_by"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	StartTime         *time.Time `json:"start_time"`
+	EndTime           time.Time  `json:"end_time"`
+	IsPublished       bool       `json:"is_published"`
+	LockID            *uuid.UUID `json:"lock_id"`
+	SelfRegistration  bool       `json:"self_registration"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MaxParticipants   *int32     `json:"max_participants"`
+	TeamContest       bool       `json:"team_contest"`
 }
 
 type ContestProblem struct {
@@ -123,6 +302,11 @@ type ContestProblem struct {
 	Score     int32     `json:"score"`
 }
 
+type ContestRegisteredTeam struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	ContestID uuid.UUID `json:"contest_id"`
+}
+
 type ContestRegisteredUser struct {
 	UserID    uuid.UUID `json:"user_id"`
 	ContestID uuid.UUID `json:"contest_id"`
@@ -137,6 +321,44 @@ type Lock struct {
 	Access      string     `json:"access"`
 	LockType    LockType   `json:"lock_type"`
 	Timeout     *time.Time `json:"timeout"`
+	ReleasedAt  *time.Time `json:"released_at"`
+}
+
+type LockAllowedUser struct {
+	LockID uuid.UUID `json:"lock_id"`
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type LockAuditLog struct {
+	ID          uuid.UUID  `json:"id"`
+	LockID      *uuid.UUID `json:"lock_id"`
+	Action      LockAction `json:"action"`
+	PerformedBy uuid.UUID  `json:"performed_by"`
+	PerformedAt time.Time  `json:"performed_at"`
+	Note        string     `json:"note"`
+	LockName    string     `json:"lock_name"`
+}
+
+type LockReleaseEvent struct {
+	LockID      uuid.UUID  `json:"lock_id"`
+	Timeout     time.Time  `json:"timeout"`
+	PublishedAt *time.Time `json:"published_at"`
+	ClaimedAt   time.Time  `json:"claimed_at"`
+}
+
+type LoginChallenge struct {
+	ID               uuid.UUID `json:"id"`
+	UserID           uuid.UUID `json:"user_id"`
+	HashedToken      string    `json:"hashed_token"`
+	RememberForMonth bool      `json:"remember_for_month"`
+	Attempts         int32     `json:"attempts"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 type Problem struct {
@@ -157,30 +379,119 @@ type Problem struct {
 	SubmissionLink   *string          `json:"submission_link"`
 	Platform         NullPlatform     `json:"platform"`
 	LockID           *uuid.UUID       `json:"lock_id"`
+	CheckerMode      CheckerMode      `json:"checker_mode"`
+	CheckerEpsilon   *float64         `json:"checker_epsilon"`
+	CheckerLanguage  *string          `json:"checker_language"`
+	CheckerSource    *string          `json:"checker_source"`
+}
+
+type ProblemTestCase struct {
+	ID           uuid.UUID `json:"id"`
+	ProblemID    int32     `json:"problem_id"`
+	Position     int32     `json:"position"`
+	TestGroup    *string   `json:"test_group"`
+	Points       int32     `json:"points"`
+	Storage      string    `json:"storage"`
+	InputKey     string    `json:"input_key"`
+	OutputKey    string    `json:"output_key"`
+	InputSize    int64     `json:"input_size"`
+	OutputSize   int64     `json:"output_size"`
+	InputSha256  string    `json:"input_sha256"`
+	OutputSha256 string    `json:"output_sha256"`
+	CreatedBy    uuid.UUID `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type RateLimitBucket struct {
+	Key       string    `json:"key"`
+	Tokens    float64   `json:"tokens"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RateLimitFailure struct {
+	Key         string     `json:"key"`
+	Failures    int32      `json:"failures"`
+	WindowStart time.Time  `json:"window_start"`
+	LockedUntil *time.Time `json:"locked_until"`
 }
 
 type Role struct {
 	RoleName string `json:"role_name"`
 }
 
+type RoleInheritance struct {
+	RoleName   string `json:"role_name"`
+	ParentRole string `json:"parent_role"`
+}
+
+type RolePermission struct {
+	RoleName   string `json:"role_name"`
+	Permission string `json:"permission"`
+}
+
+type Session struct {
+	ID                         uuid.UUID  `json:"id"`
+	UserID                     uuid.UUID  `json:"user_id"`
+	HashedRefreshToken         string     `json:"hashed_refresh_token"`
+	ExpiresAt                  time.Time  `json:"expires_at"`
+	RevokedAt                  *time.Time `json:"revoked_at"`
+	CreatedAt                  time.Time  `json:"created_at"`
+	LastRefreshedAt            time.Time  `json:"last_refreshed_at"`
+	TwoFactorVerified          bool       `json:"two_factor_verified"`
+	PreviousHashedRefreshToken *string    `json:"previous_hashed_refresh_token"`
+}
+
 type Solved struct {
 	UserID    uuid.UUID `json:"user_id"`
 	ContestID uuid.UUID `json:"contest_id"`
-	ProblemID uuid.UUID `json:"problem_id"`
+	ProblemID int32     `json:"problem_id"`
 }
 
 type Submission struct {
 	ID           uuid.UUID        `json:"id"`
-	BotAccountID uuid.UUID        `json:"bot_account_id"`
+	BotAccountID *uuid.UUID       `json:"bot_account_id"`
 	WebsiteData  *json.RawMessage `json:"website_data"`
 	SubmittedBy  uuid.UUID        `json:"submitted_by"`
 	ContestID    *uuid.UUID       `json:"contest_id"`
-	ProblemID    uuid.UUID        `json:"problem_id"`
+	ProblemID    int32            `json:"problem_id"`
 	Language     string           `json:"language"`
 	Solution     string           `json:"solution"`
 	Status       *string          `json:"status"`
 	CreatedAt    time.Time        `json:"created_at"`
 	UpdatedAt    time.Time        `json:"updated_at"`
+	TeamID       *uuid.UUID       `json:"team_id"`
+}
+
+type Team struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	MaxSize   int32     `json:"max_size"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TeamInvite struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	InvitedBy uuid.UUID `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type TeamMember struct {
+	TeamID   uuid.UUID `json:"team_id"`
+	UserID   uuid.UUID `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type TeamScore struct {
+	TeamID       uuid.UUID `json:"team_id"`
+	ContestID    uuid.UUID `json:"contest_id"`
+	ProblemID    int32     `json:"problem_id"`
+	Score        int32     `json:"score"`
+	SubmissionID uuid.UUID `json:"submission_id"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Token struct {
@@ -191,6 +502,14 @@ type Token struct {
 	Email       string          `json:"email"`
 	ExpiresAt   time.Time       `json:"expires_at"`
 	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int32           `json:"attempts"`
+}
+
+type TotpRecoveryCode struct {
+	ID         uuid.UUID  `json:"id"`
+	UserID     uuid.UUID  `json:"user_id"`
+	HashedCode string     `json:"hashed_code"`
+	UsedAt     *time.Time `json:"used_at"`
 }
 
 type Tournament struct {
@@ -205,16 +524,22 @@ type Tournament struct {
 type TournamentContest struct {
 	RoundID   uuid.UUID `json:"round_id"`
 	ContestID uuid.UUID `json:"contest_id"`
+	Weight    int32     `json:"weight"`
 }
 
 type TournamentRound struct {
-	ID           uuid.UUID  `json:"id"`
-	TournamentID uuid.UUID  `json:"tournament_id"`
-	RoundNumber  int32      `json:"round_number"`
-	Title        string     `json:"title"`
-	LockID       *uuid.UUID `json:"lock_id"`
-	CreatedBy    uuid.UUID  `json:"created_by"`
-	UpdatedAt    time.Time  `json:"updated_at"`
+	ID               uuid.UUID        `json:"id"`
+	TournamentID     uuid.UUID        `json:"tournament_id"`
+	RoundNumber      int32            `json:"round_number"`
+	Title            string           `json:"title"`
+	LockID           *uuid.UUID       `json:"lock_id"`
+	CreatedBy        uuid.UUID        `json:"created_by"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+	Scoring          RoundScoring     `json:"scoring"`
+	BestN            *int32           `json:"best_n"`
+	Advancement      RoundAdvancement `json:"advancement"`
+	AdvanceK         *int32           `json:"advance_k"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
 }
 
 type User struct {
@@ -236,8 +561,16 @@ type UserRole struct {
 type UserScore struct {
 	UserID       uuid.UUID `json:"user_id"`
 	ContestID    uuid.UUID `json:"contest_id"`
-	ProblemID    uuid.UUID `json:"problem_id"`
+	ProblemID    int32     `json:"problem_id"`
 	Score        int32     `json:"score"`
 	UpdatedAt    time.Time `json:"updated_at"`
 	SubmissionID uuid.UUID `json:"submission_id"`
 }
+
+type UserTotp struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Secret       string     `json:"secret"`
+	ConfirmedAt  *time.Time `json:"confirmed_at"`
+	LastUsedStep int64      `json:"last_used_step"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
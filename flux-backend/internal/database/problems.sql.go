@@ -28,7 +28,11 @@ INSERT INTO problems (
     difficulty,
     submission_link,
     platform,
-    lock_id
+    lock_id,
+    checker_mode,
+    checker_epsilon,
+    checker_language,
+    checker_source
 ) VALUES (
     $1, -- title
     $2, -- statement
@@ -43,9 +47,13 @@ INSERT INTO problems (
     $10, -- difficulty (can be NULL)
     $11, -- submission_link (can be NULL)
     $12, -- platform (can be NULL)
-    $13 -- lock_id
+    $13, -- lock_id
+    $14, -- checker_mode
+    $15, -- checker_epsilon (can be NULL)
+    $16, -- checker_language (can be NULL)
+    $17 -- checker_source (can be NULL)
 )
-RETURNING id, title, statement, input_format, output_format, example_testcases, notes, memory_limit_kb, time_limit_ms, created_by, last_updated_by, created_at, updated_at, difficulty, submission_link, platform, lock_id
+RETURNING id, title, statement, input_format, output_format, example_testcases, notes, memory_limit_kb, time_limit_ms, created_by, last_updated_by, created_at, updated_at, difficulty, submission_link, platform, lock_id, checker_mode, checker_epsilon, checker_language, checker_source
 `
 
 type AddProblemParams struct {
@@ -62,6 +70,10 @@ type AddProblemParams struct {
 	SubmissionLink   *string          `json:"submission_link"`
 	Platform         NullPlatform     `json:"platform"`
 	LockID           *uuid.UUID       `json:"lock_id"`
+	CheckerMode      CheckerMode      `json:"checker_mode"`
+	CheckerEpsilon   *float64         `json:"checker_epsilon"`
+	CheckerLanguage  *string          `json:"checker_language"`
+	CheckerSource    *string          `json:"checker_source"`
 }
 
 func (q *Queries) AddProblem(ctx context.Context, arg AddProblemParams) (Problem, error) {
@@ -79,6 +91,10 @@ func (q *Queries) AddProblem(ctx context.Context, arg AddProblemParams) (Problem
 		arg.SubmissionLink,
 		arg.Platform,
 		arg.LockID,
+		arg.CheckerMode,
+		arg.CheckerEpsilon,
+		arg.CheckerLanguage,
+		arg.CheckerSource,
 	)
 	var i Problem
 	err := row.Scan(
@@ -99,6 +115,10 @@ func (q *Queries) AddProblem(ctx context.Context, arg AddProblemParams) (Problem
 		&i.SubmissionLink,
 		&i.Platform,
 		&i.LockID,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerLanguage,
+		&i.CheckerSource,
 	)
 	return i, err
 }
@@ -115,33 +135,40 @@ func (q *Queries) CheckPlatformType(ctx context.Context, dollar_1 Platform) (Pla
 }
 
 const getProblemAuth = `-- name: GetProblemAuth :one
-SELECT locks.id, locks.access, locks.timeout
+SELECT locks.id, locks.access, locks.timeout, locks.released_at
 FROM problems
 LEFT JOIN locks ON problems.lock_id = locks.id
 WHERE problems.id = $1
 `
 
 type GetProblemAuthRow struct {
-	ID      *uuid.UUID `json:"id"`
-	Access  *string    `json:"access"`
-	Timeout *time.Time `json:"timeout"`
+	ID         *uuid.UUID `json:"id"`
+	Access     *string    `json:"access"`
+	Timeout    *time.Time `json:"timeout"`
+	ReleasedAt *time.Time `json:"released_at"`
 }
 
 func (q *Queries) GetProblemAuth(ctx context.Context, id int32) (GetProblemAuthRow, error) {
 	row := q.db.QueryRow(ctx, getProblemAuth, id)
 	var i GetProblemAuthRow
-	err := row.Scan(&i.ID, &i.Access, &i.Timeout)
+	err := row.Scan(
+		&i.ID,
+		&i.Access,
+		&i.Timeout,
+		&i.ReleasedAt,
+	)
 	return i, err
 }
 
 const getProblemById = `-- name: GetProblemById :one
 SELECT
     -- Explicitly list all columns from 'problems' except 'lock_id'
-    problems.id, problems.title, problems.statement, problems.input_format, problems.output_format, problems.example_testcases, problems.notes, problems.memory_limit_kb, problems.time_limit_ms, problems.created_by, problems.last_updated_by, problems.created_at, problems.updated_at, problems.difficulty, problems.submission_link, problems.platform, problems.lock_id,
+    problems.id, problems.title, problems.statement, problems.input_format, problems.output_format, problems.example_testcases, problems.notes, problems.memory_limit_kb, problems.time_limit_ms, problems.created_by, problems.last_updated_by, problems.created_at, problems.updated_at, problems.difficulty, problems.submission_link, problems.platform, problems.lock_id, problems.checker_mode, problems.checker_epsilon, problems.checker_language, problems.checker_source,
 
     -- Select only the 'access' column from the 'locks' table
     locks.access as lock_access,
-    locks.timeout as lock_timeout
+    locks.timeout as lock_timeout,
+    locks.released_at as lock_released_at
 FROM
     problems
 LEFT JOIN
@@ -168,8 +195,13 @@ type GetProblemByIdRow struct {
 	SubmissionLink   *string          `json:"submission_link"`
 	Platform         NullPlatform     `json:"platform"`
 	LockID           *uuid.UUID       `json:"lock_id"`
+	CheckerMode      CheckerMode      `json:"checker_mode"`
+	CheckerEpsilon   *float64         `json:"checker_epsilon"`
+	CheckerLanguage  *string          `json:"checker_language"`
+	CheckerSource    *string          `json:"checker_source"`
 	LockAccess       *string          `json:"lock_access"`
 	LockTimeout      *time.Time       `json:"lock_timeout"`
+	LockReleasedAt   *time.Time       `json:"lock_released_at"`
 }
 
 func (q *Queries) GetProblemById(ctx context.Context, id int32) (GetProblemByIdRow, error) {
@@ -193,8 +225,13 @@ func (q *Queries) GetProblemById(ctx context.Context, id int32) (GetProblemByIdR
 		&i.SubmissionLink,
 		&i.Platform,
 		&i.LockID,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerLanguage,
+		&i.CheckerSource,
 		&i.LockAccess,
 		&i.LockTimeout,
+		&i.LockReleasedAt,
 	)
 	return i, err
 }
@@ -209,6 +246,7 @@ SELECT
     p.created_at,
     l.id as lock_id,
     l.timeout as lock_timeout,
+    l.released_at as lock_released_at,
     l.access as lock_access
 FROM
     problems AS p
@@ -256,15 +294,16 @@ type GetProblemsByFiltersParams struct {
 }
 
 type GetProblemsByFiltersRow struct {
-	ID          int32        `json:"id"`
-	Title       string       `json:"title"`
-	Difficulty  int32        `json:"difficulty"`
-	Platform    NullPlatform `json:"platform"`
-	CreatedBy   uuid.UUID    `json:"created_by"`
-	CreatedAt   time.Time    `json:"created_at"`
-	LockID      *uuid.UUID   `json:"lock_id"`
-	LockTimeout *time.Time   `json:"lock_timeout"`
-	LockAccess  *string      `json:"lock_access"`
+	ID             int32        `json:"id"`
+	Title          string       `json:"title"`
+	Difficulty     int32        `json:"difficulty"`
+	Platform       NullPlatform `json:"platform"`
+	CreatedBy      uuid.UUID    `json:"created_by"`
+	CreatedAt      time.Time    `json:"created_at"`
+	LockID         *uuid.UUID   `json:"lock_id"`
+	LockTimeout    *time.Time   `json:"lock_timeout"`
+	LockReleasedAt *time.Time   `json:"lock_released_at"`
+	LockAccess     *string      `json:"lock_access"`
 }
 
 func (q *Queries) GetProblemsByFilters(ctx context.Context, arg GetProblemsByFiltersParams) ([]GetProblemsByFiltersRow, error) {
@@ -292,6 +331,7 @@ func (q *Queries) GetProblemsByFilters(ctx context.Context, arg GetProblemsByFil
 			&i.CreatedAt,
 			&i.LockID,
 			&i.LockTimeout,
+			&i.LockReleasedAt,
 			&i.LockAccess,
 		); err != nil {
 			return nil, err
@@ -319,10 +359,14 @@ SET
     submission_link = $10,
     platform = $11,
     last_updated_by = $12,
-    lock_id = $13
+    lock_id = $13,
+    checker_mode = $14,
+    checker_epsilon = $15,
+    checker_language = $16,
+    checker_source = $17
 WHERE
-    id = $14
-RETURNING id, title, statement, input_format, output_format, example_testcases, notes, memory_limit_kb, time_limit_ms, created_by, last_updated_by, created_at, updated_at, difficulty, submission_link, platform, lock_id
+    id = $18
+RETURNING id, title, statement, input_format, output_format, example_testcases, notes, memory_limit_kb, time_limit_ms, created_by, last_updated_by, created_at, updated_at, difficulty, submission_link, platform, lock_id, checker_mode, checker_epsilon, checker_language, checker_source
 `
 
 type UpdateProblemParams struct {
@@ -339,6 +383,10 @@ type UpdateProblemParams struct {
 	Platform         NullPlatform     `json:"platform"`
 	LastUpdatedBy    uuid.UUID        `json:"last_updated_by"`
 	LockID           *uuid.UUID       `json:"lock_id"`
+	CheckerMode      CheckerMode      `json:"checker_mode"`
+	CheckerEpsilon   *float64         `json:"checker_epsilon"`
+	CheckerLanguage  *string          `json:"checker_language"`
+	CheckerSource    *string          `json:"checker_source"`
 	ID               int32            `json:"id"`
 }
 
@@ -357,6 +405,10 @@ func (q *Queries) UpdateProblem(ctx context.Context, arg UpdateProblemParams) (P
 		arg.Platform,
 		arg.LastUpdatedBy,
 		arg.LockID,
+		arg.CheckerMode,
+		arg.CheckerEpsilon,
+		arg.CheckerLanguage,
+		arg.CheckerSource,
 		arg.ID,
 	)
 	var i Problem
@@ -378,6 +430,10 @@ func (q *Queries) UpdateProblem(ctx context.Context, arg UpdateProblemParams) (P
 		&i.SubmissionLink,
 		&i.Platform,
 		&i.LockID,
+		&i.CheckerMode,
+		&i.CheckerEpsilon,
+		&i.CheckerLanguage,
+		&i.CheckerSource,
 	)
 	return i, err
 }
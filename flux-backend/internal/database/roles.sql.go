@@ -11,6 +11,48 @@ import (
 	"github.com/google/uuid"
 )
 
+const getRoleHolders = `-- name: GetRoleHolders :many
+SELECT
+    r.role_name,
+    u.user_name,
+    u.roll_no
+FROM
+    roles r
+LEFT JOIN
+    user_roles ur ON r.role_name = ur.role_name
+LEFT JOIN
+    users u ON ur.user_id = u.id
+ORDER BY
+    r.role_name, u.user_name
+`
+
+type GetRoleHoldersRow struct {
+	RoleName string  `json:"role_name"`
+	UserName *string `json:"user_name"`
+	RollNo   *string `json:"roll_no"`
+}
+
+// every role with the users holding it, roles without holders have a single row of nulls
+func (q *Queries) GetRoleHolders(ctx context.Context) ([]GetRoleHoldersRow, error) {
+	rows, err := q.db.Query(ctx, getRoleHolders)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetRoleHoldersRow
+	for rows.Next() {
+		var i GetRoleHoldersRow
+		if err := rows.Scan(&i.RoleName, &i.UserName, &i.RollNo); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserRolesByUserName = `-- name: GetUserRolesByUserName :many
 SELECT user_id, role_name FROM user_roles WHERE user_id = $1
 `
@@ -34,3 +76,50 @@ func (q *Queries) GetUserRolesByUserName(ctx context.Context, userID uuid.UUID)
 	}
 	return items, nil
 }
+
+const grantUserRole = `-- name: GrantUserRole :execrows
+INSERT INTO user_roles (user_id, role_name)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type GrantUserRoleParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	RoleName string    `json:"role_name"`
+}
+
+func (q *Queries) GrantUserRole(ctx context.Context, arg GrantUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, grantUserRole, arg.UserID, arg.RoleName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeUserRole = `-- name: RevokeUserRole :execrows
+DELETE FROM user_roles WHERE user_id = $1 AND role_name = $2
+`
+
+type RevokeUserRoleParams struct {
+	UserID   uuid.UUID `json:"user_id"`
+	RoleName string    `json:"role_name"`
+}
+
+func (q *Queries) RevokeUserRole(ctx context.Context, arg RevokeUserRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeUserRole, arg.UserID, arg.RoleName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const roleExists = `-- name: RoleExists :one
+SELECT EXISTS(SELECT 1 FROM roles WHERE role_name = $1)
+`
+
+func (q *Queries) RoleExists(ctx context.Context, roleName string) (bool, error) {
+	row := q.db.QueryRow(ctx, roleExists, roleName)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
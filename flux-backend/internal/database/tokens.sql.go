@@ -9,12 +9,36 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+const consumeToken = `-- name: ConsumeToken :one
+DELETE FROM tokens WHERE id = $1
+RETURNING id, hashed_token, purpose, payload, email, expires_at, created_at, attempts
+`
+
+// deleting the token is what uses it, a concurrent use finds no row
+func (q *Queries) ConsumeToken(ctx context.Context, id uuid.UUID) (Token, error) {
+	row := q.db.QueryRow(ctx, consumeToken, id)
+	var i Token
+	err := row.Scan(
+		&i.ID,
+		&i.HashedToken,
+		&i.Purpose,
+		&i.Payload,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.Attempts,
+	)
+	return i, err
+}
+
 const createToken = `-- name: CreateToken :one
 INSERT INTO tokens (hashed_token, purpose, payload, email, expires_at)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, hashed_token, purpose, payload, email, expires_at, created_at
+RETURNING id, hashed_token, purpose, payload, email, expires_at, created_at, attempts
 `
 
 type CreateTokenParams struct {
@@ -42,6 +66,7 @@ func (q *Queries) CreateToken(ctx context.Context, arg CreateTokenParams) (Token
 		&i.Email,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.Attempts,
 	)
 	return i, err
 }
@@ -60,8 +85,29 @@ func (q *Queries) DeleteByEmailAndPurpose(ctx context.Context, arg DeleteByEmail
 	return err
 }
 
+const deleteExpiredTokens = `-- name: DeleteExpiredTokens :execrows
+DELETE FROM tokens WHERE expires_at < NOW()
+`
+
+func (q *Queries) DeleteExpiredTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteToken = `-- name: DeleteToken :exec
+DELETE FROM tokens WHERE id = $1
+`
+
+func (q *Queries) DeleteToken(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteToken, id)
+	return err
+}
+
 const getTokenByEmailAndPurpose = `-- name: GetTokenByEmailAndPurpose :one
-SELECT id, hashed_token, purpose, payload, email, expires_at, created_at
+SELECT id, hashed_token, purpose, payload, email, expires_at, created_at, attempts
 FROM tokens
 WHERE email = $1 AND purpose = $2
 ORDER BY created_at DESC 
@@ -85,6 +131,19 @@ func (q *Queries) GetTokenByEmailAndPurpose(ctx context.Context, arg GetTokenByE
 		&i.Email,
 		&i.ExpiresAt,
 		&i.CreatedAt,
+		&i.Attempts,
 	)
 	return i, err
 }
+
+const incrementTokenAttempts = `-- name: IncrementTokenAttempts :one
+UPDATE tokens SET attempts = attempts + 1 WHERE id = $1
+RETURNING attempts
+`
+
+func (q *Queries) IncrementTokenAttempts(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementTokenAttempts, id)
+	var attempts int32
+	err := row.Scan(&attempts)
+	return attempts, err
+}
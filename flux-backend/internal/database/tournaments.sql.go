@@ -13,17 +13,18 @@ import (
 )
 
 const addTournamentContest = `-- name: AddTournamentContest :exec
-INSERT INTO tournament_contests (round_id, contest_id)
-VALUES ($1, $2)
+INSERT INTO tournament_contests (round_id, contest_id, weight)
+VALUES ($1, $2, $3)
 `
 
 type AddTournamentContestParams struct {
 	RoundID   uuid.UUID `json:"round_id"`
 	ContestID uuid.UUID `json:"contest_id"`
+	Weight    int32     `json:"weight"`
 }
 
 func (q *Queries) AddTournamentContest(ctx context.Context, arg AddTournamentContestParams) error {
-	_, err := q.db.Exec(ctx, addTournamentContest, arg.RoundID, arg.ContestID)
+	_, err := q.db.Exec(ctx, addTournamentContest, arg.RoundID, arg.ContestID, arg.Weight)
 	return err
 }
 
@@ -56,17 +57,23 @@ func (q *Queries) CreateTournament(ctx context.Context, arg CreateTournamentPara
 
 const createTournamentRound = `-- name: CreateTournamentRound :one
 INSERT INTO tournament_rounds (
-    tournament_id, round_number, title, lock_id, created_by
-) VALUES ($1, $2, $3, $4, $5)
-RETURNING id, tournament_id, round_number, title, lock_id, created_by, updated_at
+    tournament_id, round_number, title, lock_id, created_by, scoring, best_n,
+    advancement, advance_k, advance_threshold
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, tournament_id, round_number, title, lock_id, created_by, updated_at, scoring, best_n, advancement, advance_k, advance_threshold
 `
 
 type CreateTournamentRoundParams struct {
-	TournamentID uuid.UUID  `json:"tournament_id"`
-	RoundNumber  int32      `json:"round_number"`
-	Title        string     `json:"title"`
-	LockID       *uuid.UUID `json:"lock_id"`
-	CreatedBy    uuid.UUID  `json:"created_by"`
+	TournamentID     uuid.UUID        `json:"tournament_id"`
+	RoundNumber      int32            `json:"round_number"`
+	Title            string           `json:"title"`
+	LockID           *uuid.UUID       `json:"lock_id"`
+	CreatedBy        uuid.UUID        `json:"created_by"`
+	Scoring          RoundScoring     `json:"scoring"`
+	BestN            *int32           `json:"best_n"`
+	Advancement      RoundAdvancement `json:"advancement"`
+	AdvanceK         *int32           `json:"advance_k"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
 }
 
 func (q *Queries) CreateTournamentRound(ctx context.Context, arg CreateTournamentRoundParams) (TournamentRound, error) {
@@ -76,6 +83,11 @@ func (q *Queries) CreateTournamentRound(ctx context.Context, arg CreateTournamen
 		arg.Title,
 		arg.LockID,
 		arg.CreatedBy,
+		arg.Scoring,
+		arg.BestN,
+		arg.Advancement,
+		arg.AdvanceK,
+		arg.AdvanceThreshold,
 	)
 	var i TournamentRound
 	err := row.Scan(
@@ -86,6 +98,11 @@ func (q *Queries) CreateTournamentRound(ctx context.Context, arg CreateTournamen
 		&i.LockID,
 		&i.CreatedBy,
 		&i.UpdatedAt,
+		&i.Scoring,
+		&i.BestN,
+		&i.Advancement,
+		&i.AdvanceK,
+		&i.AdvanceThreshold,
 	)
 	return i, err
 }
@@ -236,10 +253,16 @@ SELECT
     tr.title,
     tr.lock_id,
     tr.created_by,
+    tr.scoring,
+    tr.best_n,
+    tr.advancement,
+    tr.advance_k,
+    tr.advance_threshold,
 
     -- lock fields
     l.access,
-    l.timeout
+    l.timeout,
+    l.released_at
 FROM
     tournament_rounds tr
 LEFT JOIN
@@ -256,14 +279,20 @@ type GetTournamentRoundByNumberParams struct {
 }
 
 type GetTournamentRoundByNumberRow struct {
-	ID           uuid.UUID  `json:"id"`
-	TournamentID uuid.UUID  `json:"tournament_id"`
-	RoundNumber  int32      `json:"round_number"`
-	Title        string     `json:"title"`
-	LockID       *uuid.UUID `json:"lock_id"`
-	CreatedBy    uuid.UUID  `json:"created_by"`
-	Access       *string    `json:"access"`
-	Timeout      *time.Time `json:"timeout"`
+	ID               uuid.UUID        `json:"id"`
+	TournamentID     uuid.UUID        `json:"tournament_id"`
+	RoundNumber      int32            `json:"round_number"`
+	Title            string           `json:"title"`
+	LockID           *uuid.UUID       `json:"lock_id"`
+	CreatedBy        uuid.UUID        `json:"created_by"`
+	Scoring          RoundScoring     `json:"scoring"`
+	BestN            *int32           `json:"best_n"`
+	Advancement      RoundAdvancement `json:"advancement"`
+	AdvanceK         *int32           `json:"advance_k"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
+	Access           *string          `json:"access"`
+	Timeout          *time.Time       `json:"timeout"`
+	ReleasedAt       *time.Time       `json:"released_at"`
 }
 
 func (q *Queries) GetTournamentRoundByNumber(ctx context.Context, arg GetTournamentRoundByNumberParams) (GetTournamentRoundByNumberRow, error) {
@@ -276,12 +305,278 @@ func (q *Queries) GetTournamentRoundByNumber(ctx context.Context, arg GetTournam
 		&i.Title,
 		&i.LockID,
 		&i.CreatedBy,
+		&i.Scoring,
+		&i.BestN,
+		&i.Advancement,
+		&i.AdvanceK,
+		&i.AdvanceThreshold,
 		&i.Access,
 		&i.Timeout,
+		&i.ReleasedAt,
 	)
 	return i, err
 }
 
+const getTournamentRoundEndTime = `-- name: GetTournamentRoundEndTime :one
+SELECT
+    -- a round without contests has nothing to wait for
+    COALESCE(MAX(c.end_time), '1970-01-01'::timestamptz)::timestamptz
+FROM
+    contests c
+JOIN
+    tournament_contests tc ON c.id = tc.contest_id
+WHERE
+    tc.round_id = $1
+`
+
+func (q *Queries) GetTournamentRoundEndTime(ctx context.Context, roundID uuid.UUID) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getTournamentRoundEndTime, roundID)
+	var column_1 time.Time
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getTournamentRoundUserScores = `-- name: GetTournamentRoundUserScores :many
+SELECT
+    tc.contest_id,
+    tc.weight,
+    u.user_name,
+    SUM(us.score)::int AS score
+FROM
+    tournament_contests tc
+JOIN
+    user_scores us ON tc.contest_id = us.contest_id
+JOIN
+    users u ON us.user_id = u.id
+WHERE
+    tc.round_id = $1
+GROUP BY
+    tc.contest_id, tc.weight, u.id
+`
+
+type GetTournamentRoundUserScoresRow struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	Weight    int32     `json:"weight"`
+	UserName  string    `json:"user_name"`
+	Score     int32     `json:"score"`
+}
+
+// total score of every user in each contest of the round
+func (q *Queries) GetTournamentRoundUserScores(ctx context.Context, roundID uuid.UUID) ([]GetTournamentRoundUserScoresRow, error) {
+	rows, err := q.db.Query(ctx, getTournamentRoundUserScores, roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTournamentRoundUserScoresRow
+	for rows.Next() {
+		var i GetTournamentRoundUserScoresRow
+		if err := rows.Scan(
+			&i.ContestID,
+			&i.Weight,
+			&i.UserName,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTournamentStandingsVersion = `-- name: GetTournamentStandingsVersion :one
+SELECT
+    GREATEST(
+        COALESCE(MAX(c.end_time), '1970-01-01'::timestamptz),
+        COALESCE(MAX(us.updated_at), '1970-01-01'::timestamptz),
+        COALESCE(MAX(ts.created_at), '1970-01-01'::timestamptz)
+    )::timestamptz
+FROM
+    contests c
+JOIN
+    tournament_contests tc ON c.id = tc.contest_id
+JOIN
+    tournament_rounds tr ON tc.round_id = tr.id
+LEFT JOIN LATERAL (
+    SELECT MAX(updated_at) AS updated_at FROM user_scores WHERE contest_id = c.id
+) us ON TRUE
+LEFT JOIN LATERAL (
+    SELECT MAX(created_at) AS created_at FROM team_scores WHERE contest_id = c.id
+) ts ON TRUE
+WHERE
+    tr.tournament_id = $1
+AND
+    c.end_time <= NOW()
+`
+
+// standings only change when a contest of the tournament ends or when a late
+// verdict changes the scores of an ended contest, the latest of those times
+// identifies a version of them
+func (q *Queries) GetTournamentStandingsVersion(ctx context.Context, tournamentID uuid.UUID) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getTournamentStandingsVersion, tournamentID)
+	var column_1 time.Time
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const getTournamentTeamScores = `-- name: GetTournamentTeamScores :many
+SELECT
+    tr.round_number,
+    tr.scoring,
+    tr.best_n,
+    tc.contest_id,
+    tc.weight,
+    tm.id AS team_id,
+    tm.name AS team_name,
+    SUM(ts.score)::int AS score
+FROM
+    tournament_rounds tr
+JOIN
+    tournament_contests tc ON tr.id = tc.round_id
+JOIN
+    contests c ON tc.contest_id = c.id
+JOIN
+    team_scores ts ON c.id = ts.contest_id
+JOIN
+    teams tm ON ts.team_id = tm.id
+WHERE
+    tr.tournament_id = $1::uuid
+AND
+    c.end_time <= $2::timestamptz
+GROUP BY
+    tr.round_number, tr.scoring, tr.best_n, tc.contest_id, tc.weight, tm.id
+ORDER BY
+    tr.round_number
+`
+
+type GetTournamentTeamScoresParams struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	EndedBefore  time.Time `json:"ended_before"`
+}
+
+type GetTournamentTeamScoresRow struct {
+	RoundNumber int32        `json:"round_number"`
+	Scoring     RoundScoring `json:"scoring"`
+	BestN       *int32       `json:"best_n"`
+	ContestID   uuid.UUID    `json:"contest_id"`
+	Weight      int32        `json:"weight"`
+	TeamID      uuid.UUID    `json:"team_id"`
+	TeamName    string       `json:"team_name"`
+	Score       int32        `json:"score"`
+}
+
+// total score of every team in each ended team contest of the tournament
+func (q *Queries) GetTournamentTeamScores(ctx context.Context, arg GetTournamentTeamScoresParams) ([]GetTournamentTeamScoresRow, error) {
+	rows, err := q.db.Query(ctx, getTournamentTeamScores, arg.TournamentID, arg.EndedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTournamentTeamScoresRow
+	for rows.Next() {
+		var i GetTournamentTeamScoresRow
+		if err := rows.Scan(
+			&i.RoundNumber,
+			&i.Scoring,
+			&i.BestN,
+			&i.ContestID,
+			&i.Weight,
+			&i.TeamID,
+			&i.TeamName,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTournamentUserScores = `-- name: GetTournamentUserScores :many
+SELECT
+    tr.round_number,
+    tr.scoring,
+    tr.best_n,
+    tc.contest_id,
+    tc.weight,
+    u.id AS user_id,
+    u.user_name,
+    u.roll_no,
+    SUM(us.score)::int AS score
+FROM
+    tournament_rounds tr
+JOIN
+    tournament_contests tc ON tr.id = tc.round_id
+JOIN
+    contests c ON tc.contest_id = c.id
+JOIN
+    user_scores us ON c.id = us.contest_id
+JOIN
+    users u ON us.user_id = u.id
+WHERE
+    tr.tournament_id = $1::uuid
+AND
+    c.end_time <= $2::timestamptz
+GROUP BY
+    tr.round_number, tr.scoring, tr.best_n, tc.contest_id, tc.weight, u.id
+ORDER BY
+    tr.round_number
+`
+
+type GetTournamentUserScoresParams struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	EndedBefore  time.Time `json:"ended_before"`
+}
+
+type GetTournamentUserScoresRow struct {
+	RoundNumber int32        `json:"round_number"`
+	Scoring     RoundScoring `json:"scoring"`
+	BestN       *int32       `json:"best_n"`
+	ContestID   uuid.UUID    `json:"contest_id"`
+	Weight      int32        `json:"weight"`
+	UserID      uuid.UUID    `json:"user_id"`
+	UserName    string       `json:"user_name"`
+	RollNo      string       `json:"roll_no"`
+	Score       int32        `json:"score"`
+}
+
+// total score of every user in each ended contest of the tournament
+func (q *Queries) GetTournamentUserScores(ctx context.Context, arg GetTournamentUserScoresParams) ([]GetTournamentUserScoresRow, error) {
+	rows, err := q.db.Query(ctx, getTournamentUserScores, arg.TournamentID, arg.EndedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetTournamentUserScoresRow
+	for rows.Next() {
+		var i GetTournamentUserScoresRow
+		if err := rows.Scan(
+			&i.RoundNumber,
+			&i.Scoring,
+			&i.BestN,
+			&i.ContestID,
+			&i.Weight,
+			&i.UserID,
+			&i.UserName,
+			&i.RollNo,
+			&i.Score,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getTournamentsByFilters = `-- name: GetTournamentsByFilters :many
 SELECT
     t.id,
@@ -359,3 +654,62 @@ func (q *Queries) GetTournamentsByFilters(ctx context.Context, arg GetTournament
 	}
 	return items, nil
 }
+
+const updateTournamentRoundAdvancement = `-- name: UpdateTournamentRoundAdvancement :execrows
+UPDATE tournament_rounds SET
+    advancement = $3,
+    advance_k = $4,
+    advance_threshold = $5
+WHERE
+    tournament_id = $1 AND round_number = $2
+`
+
+type UpdateTournamentRoundAdvancementParams struct {
+	TournamentID     uuid.UUID        `json:"tournament_id"`
+	RoundNumber      int32            `json:"round_number"`
+	Advancement      RoundAdvancement `json:"advancement"`
+	AdvanceK         *int32           `json:"advance_k"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
+}
+
+func (q *Queries) UpdateTournamentRoundAdvancement(ctx context.Context, arg UpdateTournamentRoundAdvancementParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateTournamentRoundAdvancement,
+		arg.TournamentID,
+		arg.RoundNumber,
+		arg.Advancement,
+		arg.AdvanceK,
+		arg.AdvanceThreshold,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateTournamentRoundScoring = `-- name: UpdateTournamentRoundScoring :execrows
+UPDATE tournament_rounds SET
+    scoring = $3,
+    best_n = $4
+WHERE
+    tournament_id = $1 AND round_number = $2
+`
+
+type UpdateTournamentRoundScoringParams struct {
+	TournamentID uuid.UUID    `json:"tournament_id"`
+	RoundNumber  int32        `json:"round_number"`
+	Scoring      RoundScoring `json:"scoring"`
+	BestN        *int32       `json:"best_n"`
+}
+
+func (q *Queries) UpdateTournamentRoundScoring(ctx context.Context, arg UpdateTournamentRoundScoringParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateTournamentRoundScoring,
+		arg.TournamentID,
+		arg.RoundNumber,
+		arg.Scoring,
+		arg.BestN,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
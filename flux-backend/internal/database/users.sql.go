@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -49,6 +50,26 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 	return i, err
 }
 
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, roll_no, user_name, first_name, last_name, email, password_hash, created_at FROM users WHERE LOWER(email) = LOWER($1)
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, lower string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, lower)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.RollNo,
+		&i.UserName,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getUserById = `-- name: GetUserById :one
 SELECT id, roll_no, user_name, first_name, last_name, email, password_hash, created_at FROM users WHERE id = $1
 `
@@ -109,6 +130,99 @@ func (q *Queries) GetUserByUserName(ctx context.Context, userName string) (User,
 	return i, err
 }
 
+const getUserContestHistory = `-- name: GetUserContestHistory :many
+WITH participations AS (
+    SELECT cru.contest_id, NULL::uuid AS team_id
+    FROM contest_registered_users cru
+    WHERE cru.user_id = $1
+    UNION
+    SELECT us.contest_id, NULL::uuid AS team_id
+    FROM user_scores us
+    WHERE us.user_id = $1
+    UNION
+    SELECT crt.contest_id, crt.team_id
+    FROM contest_registered_teams crt
+    JOIN team_members tm ON crt.team_id = tm.team_id
+    WHERE tm.user_id = $1
+)
+SELECT
+    c.id,
+    c.title,
+    -- contests guarded by a timer lock start when the lock expires
+    COALESCE(c.start_time, l.timeout) AS start_time,
+    c.end_time,
+    (CASE WHEN p.team_id IS NULL THEN (
+        SELECT COALESCE(SUM(us.score), 0) FROM user_scores us
+        WHERE us.user_id = $1 AND us.contest_id = c.id
+    ) ELSE (
+        SELECT COALESCE(SUM(ts.score), 0) FROM team_scores ts
+        WHERE ts.team_id = p.team_id AND ts.contest_id = c.id
+    ) END)::INTEGER AS score,
+    -- a problem is scored once per participant, so the scores are the solved problems
+    (CASE WHEN p.team_id IS NULL THEN (
+        SELECT COUNT(*) FROM user_scores us
+        WHERE us.user_id = $1 AND us.contest_id = c.id
+    ) ELSE (
+        SELECT COUNT(*) FROM team_scores ts
+        WHERE ts.team_id = p.team_id AND ts.contest_id = c.id
+    ) END)::BIGINT AS solved
+FROM
+    participations p
+JOIN
+    contests c ON p.contest_id = c.id
+LEFT JOIN
+    locks l ON c.lock_id = l.id
+WHERE
+    c.end_time <= NOW()
+ORDER BY
+    c.end_time DESC
+LIMIT $2
+`
+
+type GetUserContestHistoryParams struct {
+	UserID uuid.UUID `json:"user_id"`
+	Limit  int32     `json:"limit"`
+}
+
+type GetUserContestHistoryRow struct {
+	ID        uuid.UUID  `json:"id"`
+	Title     string     `json:"title"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Score     int32      `json:"score"`
+	Solved    int64      `json:"solved"`
+}
+
+// ended contests the user took part in, latest first. private contests count
+// through registration, published ones through a score and team contests
+// through a registered team of the user
+func (q *Queries) GetUserContestHistory(ctx context.Context, arg GetUserContestHistoryParams) ([]GetUserContestHistoryRow, error) {
+	rows, err := q.db.Query(ctx, getUserContestHistory, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetUserContestHistoryRow
+	for rows.Next() {
+		var i GetUserContestHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Score,
+			&i.Solved,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getUserIDByUserName = `-- name: GetUserIDByUserName :one
 SELECT id from users WHERE user_name=$1
 `
@@ -120,6 +234,18 @@ func (q *Queries) GetUserIDByUserName(ctx context.Context, userName string) (uui
 	return id, err
 }
 
+const getUserSolvedCount = `-- name: GetUserSolvedCount :one
+SELECT COUNT(DISTINCT problem_id) FROM solved WHERE user_id = $1
+`
+
+// distinct problems solved by the user across all contests
+func (q *Queries) GetUserSolvedCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserSolvedCount, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const getUsersByFilters = `-- name: GetUsersByFilters :many
 SELECT id, user_name, roll_no FROM users
 WHERE 
@@ -193,6 +319,17 @@ func (q *Queries) GetUsersCountByUserName(ctx context.Context, userName string)
 	return count, err
 }
 
+const isEmailTaken = `-- name: IsEmailTaken :one
+SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)
+`
+
+func (q *Queries) IsEmailTaken(ctx context.Context, email string) (bool, error) {
+	row := q.db.QueryRow(ctx, isEmailTaken, email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const isUserIDValid = `-- name: IsUserIDValid :one
 SELECT EXISTS(SELECT id from users WHERE id=$1)
 `
@@ -217,3 +354,56 @@ func (q *Queries) ResetPassword(ctx context.Context, arg ResetPasswordParams) er
 	_, err := q.db.Exec(ctx, resetPassword, arg.UserName, arg.PasswordHash)
 	return err
 }
+
+const updateUserEmail = `-- name: UpdateUserEmail :execrows
+UPDATE users SET email = $2 WHERE id = $1
+`
+
+type UpdateUserEmailParams struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email"`
+}
+
+func (q *Queries) UpdateUserEmail(ctx context.Context, arg UpdateUserEmailParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateUserEmail, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET first_name = $2, last_name = $3, user_name = COALESCE($4, user_name)
+WHERE id = $1
+RETURNING id, roll_no, user_name, first_name, last_name, email, password_hash, created_at
+`
+
+type UpdateUserProfileParams struct {
+	ID        uuid.UUID `json:"id"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	UserName  *string   `json:"user_name"`
+}
+
+// a null user_name keeps the current one
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.ID,
+		arg.FirstName,
+		arg.LastName,
+		arg.UserName,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.RollNo,
+		&i.UserName,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
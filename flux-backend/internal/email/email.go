@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"slices"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/flux_errors"
@@ -19,16 +20,24 @@ const (
 	KeyEmailSMTPPort                          = 587
 	KeyEmailFrom                              = "From"
 	KeyEmailTo                                = "To"
+	KeyEmailBcc                               = "Bcc"
 	KeyEmailSubject                           = "Subject"
 	KeyEmailBodyPlain           EmailBodyType = "text/plain"
 	PurposeEmailPasswordReset   EmailPurpose  = "reset_password"
+	PurposeEmailPasswordChange  EmailPurpose  = "change_password"
 	PurposeEmailSignUp          EmailPurpose  = "sign_up"
+	PurposeEmailChange          EmailPurpose  = "change_email"
+	PurposeContestRescheduled   EmailPurpose  = "contest_rescheduled"
+	PurposeContestStarted       EmailPurpose  = "contest_started"
 	defaultEmailChannelCapacity               = 100
+	// smtp servers limit the recipients of a single mail
+	maxBulkMailRecipients = 50
 )
 
 type emailJob struct {
 	from     string
 	to       []string
+	bcc      []string
 	subject  string
 	body     string
 	bodyType EmailBodyType
@@ -69,3 +78,43 @@ func NewMail(
         return nil
     }
 }
+
+// NewBulkMail queues the same mail for many recipients, they are put in bcc so
+// they do not see each other, and split into mails of maxBulkMailRecipients.
+// It returns the number of recipients whose mail was queued
+func NewBulkMail(
+	ctx context.Context,
+	subject string,
+	body string,
+	bodyType EmailBodyType,
+	purpose EmailPurpose,
+	bcc ...string,
+) (int, error) {
+	fromMail := os.Getenv(KeyEmailSender)
+	if fromMail == "" {
+		log.Error("sender email is not configured")
+		return 0, flux_errors.ErrEmailServiceStopped
+	}
+
+	queued := 0
+	for batch := range slices.Chunk(bcc, maxBulkMailRecipients) {
+		job := emailJob{
+			from:     fromMail,
+			to:       []string{fromMail},
+			bcc:      batch,
+			subject:  subject,
+			body:     body,
+			bodyType: bodyType,
+			purpose:  purpose,
+		}
+		select {
+		case <-ctx.Done():
+			log.Errorf("bulk email job cancelled: %v", ctx.Err())
+			return queued, errors.Join(flux_errors.ErrEmailServiceStopped, ctx.Err())
+		case emailChan <- job:
+			queued += len(batch)
+		}
+	}
+
+	return queued, nil
+}
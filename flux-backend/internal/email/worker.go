@@ -102,6 +102,9 @@ func constructMail(job emailJob) *gomail.Message {
 	mail := gomail.NewMessage()
 	mail.SetHeader(KeyEmailFrom, job.from)
 	mail.SetHeader(KeyEmailTo, job.to...)
+	if len(job.bcc) > 0 {
+		mail.SetHeader(KeyEmailBcc, job.bcc...)
+	}
 	mail.SetHeader(KeyEmailSubject, job.subject)
 	mail.SetBody(string(job.bodyType), job.body)
 	return mail
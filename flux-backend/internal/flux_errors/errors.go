@@ -16,9 +16,11 @@ var (
 	ErrInvalidUserCredentials    = errors.New("invalid username or roll_no and password")
 	ErrInvalidRequestCredentials = errors.New("invalid request credentials")
 	ErrEmailServiceStopped       = errors.New("email service is stopped currently")
+	ErrJudgeServiceStopped       = errors.New("judge service is stopped currently")
 	ErrVerificationTokenExpired  = errors.New("verfication token expired. please try again")
 	ErrCorruptedVerification     = errors.New("corrupted verificaiton")
 	ErrUnAuthorized              = errors.New("user not allowed to perform this action")
 	ErrNotFound                  = errors.New("entity not found")
 	ErrPartialResult             = errors.New("unable to fetch complete list of requested entities")
+	ErrTooManyRequests           = errors.New("too many requests")
 )
@@ -0,0 +1,182 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	testlibHeader = "testlib.h"
+	checkerInput  = "input.txt"
+	checkerOutput = "output.txt"
+	checkerAnswer = "answer.txt"
+
+	// exit codes of testlib checkers
+	testlibOK                = 0
+	testlibWrongAnswer       = 1
+	testlibPresentationError = 2
+)
+
+/*
+	customChecker is a testlib compatible checker program
+	It is run as `checker <input> <output> <answer>` and reports the verdict
+	with its exit code. Any exit code other than ok, wa and pe (e.g. _fail)
+	means the checker itself is broken, which is a judge error
+*/
+
+type customChecker struct {
+	dir  string
+	run  []string
+	user sandboxUser
+}
+
+func compileChecker(
+	checker Checker,
+	user sandboxUser,
+	languages map[string]Language,
+) (*customChecker, error) {
+	language, ok := languages[checker.Language]
+	if !ok {
+		return nil, fmt.Errorf("checker language %s is not configured", checker.Language)
+	}
+
+	// compiled by the checker user, a solution runs as another one
+	dir, err := newSandboxDir("checker-", user)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create checker directory, %w", err)
+	}
+	c := &customChecker{dir: dir, run: language.Run, user: user}
+
+	err = os.WriteFile(filepath.Join(dir, language.SourceFile), []byte(checker.Source), 0o644)
+	if err != nil {
+		c.remove()
+		return nil, fmt.Errorf("cannot write checker source, %w", err)
+	}
+
+	// checkers include testlib.h from their own directory
+	if testlib := os.Getenv(KeyJudgeTestlib); testlib != "" {
+		header, err := os.ReadFile(testlib)
+		if err != nil {
+			c.remove()
+			return nil, fmt.Errorf("cannot read %s, %w", testlib, err)
+		}
+		err = os.WriteFile(filepath.Join(dir, testlibHeader), header, 0o644)
+		if err != nil {
+			c.remove()
+			return nil, fmt.Errorf("cannot copy %s, %w", testlibHeader, err)
+		}
+	}
+
+	if len(language.Compile) == 0 {
+		return c.seal()
+	}
+
+	var compileOutput limitedBuffer
+	compileOutput.limit = 4096
+	result, err := runSandboxed(
+		context.Background(),
+		runSpec{
+			dir:       dir,
+			args:      language.Compile,
+			stdin:     bytes.NewReader(nil),
+			stdout:    &compileOutput,
+			stderr:    &compileOutput,
+			user:      user,
+			timeLimit: compileTimeLimit,
+			wallLimit: 2 * compileTimeLimit,
+		},
+	)
+	if err != nil {
+		c.remove()
+		return nil, fmt.Errorf("cannot run the checker compiler, %w", err)
+	}
+	if result.timedOut || result.signaled || result.exitCode != 0 {
+		c.remove()
+		return nil, fmt.Errorf("checker does not compile, %s", compileOutput.String())
+	}
+
+	return c.seal()
+}
+
+// seal gives the checker to root, read and execute only,
+// so not even the checker can replace itself while judging
+func (c *customChecker) seal() (*customChecker, error) {
+	if err := sealDir(c.dir); err != nil {
+		c.remove()
+		return nil, fmt.Errorf("cannot seal the checker directory, %w", err)
+	}
+	return c, nil
+}
+
+func (c *customChecker) check(input, output, answer []byte, jobLogger *log.Entry) Verdict {
+	files := map[string][]byte{
+		checkerInput:  input,
+		checkerOutput: output,
+		checkerAnswer: answer,
+	}
+	for name, data := range files {
+		// only the checker user can read the answer, never the solution
+		path := filepath.Join(c.dir, name)
+		defer os.Remove(path)
+		if err := writeUserFile(path, data, c.user); err != nil {
+			jobLogger.Errorf("cannot write %s for the checker, %v", name, err)
+			return VerdictJudgeError
+		}
+	}
+
+	var messages limitedBuffer
+	messages.limit = 4096
+	args := append(append([]string{}, c.run...), checkerInput, checkerOutput, checkerAnswer)
+	result, err := runSandboxed(
+		context.Background(),
+		runSpec{
+			dir:           c.dir,
+			args:          args,
+			stdin:         bytes.NewReader(nil),
+			stdout:        &messages,
+			stderr:        &messages,
+			user:          c.user,
+			timeLimit:     checkerTimeLimit,
+			wallLimit:     2 * checkerTimeLimit,
+			memoryLimitKb: checkerMemoryLimitKb,
+		},
+	)
+	if err != nil {
+		jobLogger.Errorf("cannot run the checker, %v", err)
+		return VerdictJudgeError
+	}
+
+	switch {
+	case result.timedOut || result.signaled:
+		jobLogger.Error("checker crashed or timed out")
+		return VerdictJudgeError
+	case result.exitCode == testlibOK:
+		return VerdictAccepted
+	case result.exitCode == testlibWrongAnswer || result.exitCode == testlibPresentationError:
+		return VerdictWrongAnswer
+	default:
+		jobLogger.Errorf(
+			"checker failed with exit code %d, %s",
+			result.exitCode,
+			messages.String(),
+		)
+		return VerdictJudgeError
+	}
+}
+
+// writeUserFile writes a file that only the user can read
+func writeUserFile(path string, data []byte, user sandboxUser) error {
+	if err := os.WriteFile(path, data, 0o400); err != nil {
+		return err
+	}
+	return os.Chown(path, int(user.uid), int(user.gid))
+}
+
+func (c *customChecker) remove() {
+	os.RemoveAll(c.dir)
+}
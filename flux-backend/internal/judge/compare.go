@@ -0,0 +1,89 @@
+package judge
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+)
+
+// outputsMatch compares the output with the built in checkers
+func outputsMatch(checker Checker, expected, actual []byte) bool {
+	switch checker.Mode {
+	case CheckerToken:
+		return tokensMatch(expected, actual)
+	case CheckerFloat:
+		return floatsMatch(expected, actual, checker.Epsilon)
+	default:
+		return linesMatch(expected, actual)
+	}
+}
+
+// linesMatch compares line by line ignoring trailing whitespace
+// on every line and trailing empty lines
+func linesMatch(expected, actual []byte) bool {
+	expectedLines := normalizedLines(expected)
+	actualLines := normalizedLines(actual)
+	if len(expectedLines) != len(actualLines) {
+		return false
+	}
+	for i := range expectedLines {
+		if !bytes.Equal(expectedLines[i], actualLines[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizedLines(output []byte) [][]byte {
+	lines := bytes.Split(output, []byte("\n"))
+	for i := range lines {
+		lines[i] = bytes.TrimRight(lines[i], " \t\r")
+	}
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tokensMatch compares whitespace separated tokens
+func tokensMatch(expected, actual []byte) bool {
+	expectedTokens := bytes.Fields(expected)
+	actualTokens := bytes.Fields(actual)
+	if len(expectedTokens) != len(actualTokens) {
+		return false
+	}
+	for i := range expectedTokens {
+		if !bytes.Equal(expectedTokens[i], actualTokens[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// floatsMatch compares tokens, numbers may differ by epsilon
+// in absolute or relative terms like testlib's doubleCompare
+func floatsMatch(expected, actual []byte, epsilon float64) bool {
+	expectedTokens := bytes.Fields(expected)
+	actualTokens := bytes.Fields(actual)
+	if len(expectedTokens) != len(actualTokens) {
+		return false
+	}
+	for i := range expectedTokens {
+		if bytes.Equal(expectedTokens[i], actualTokens[i]) {
+			continue
+		}
+		want, err := strconv.ParseFloat(string(expectedTokens[i]), 64)
+		if err != nil {
+			return false
+		}
+		got, err := strconv.ParseFloat(string(actualTokens[i]), 64)
+		if err != nil || math.IsNaN(got) || math.IsInf(got, 0) {
+			return false
+		}
+		diff := math.Abs(want - got)
+		if diff > epsilon && diff > epsilon*math.Abs(want) {
+			return false
+		}
+	}
+	return true
+}
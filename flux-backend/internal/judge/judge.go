@@ -0,0 +1,86 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+type Verdict string
+type CheckerMode string
+
+const (
+	KeyJudgeWorkDir                         = "JUDGE_WORK_DIR"
+	KeyJudgeCgroupRoot                      = "JUDGE_CGROUP_ROOT"
+	KeyJudgeLanguages                       = "JUDGE_LANGUAGES"
+	KeyJudgeTestlib                         = "JUDGE_TESTLIB"
+	KeyJudgeSandboxUID                      = "JUDGE_SANDBOX_UID"
+	KeyJudgeSandboxGID                      = "JUDGE_SANDBOX_GID"
+	VerdictAccepted             Verdict     = "accepted"
+	VerdictWrongAnswer          Verdict     = "wrong_answer"
+	VerdictTimeLimitExceeded    Verdict     = "time_limit_exceeded"
+	VerdictMemoryLimitExceeded  Verdict     = "memory_limit_exceeded"
+	VerdictRuntimeError         Verdict     = "runtime_error"
+	VerdictCompilationError     Verdict     = "compilation_error"
+	VerdictJudgeError           Verdict     = "judge_error"
+	CheckerExact                CheckerMode = "exact"
+	CheckerToken                CheckerMode = "token"
+	CheckerFloat                CheckerMode = "float"
+	CheckerCustom               CheckerMode = "custom"
+	defaultJudgeChannelCapacity             = 100
+	compileTimeLimit                        = 30 * time.Second
+	checkerTimeLimit                        = 10 * time.Second
+	checkerMemoryLimitKb                    = 512 * 1024
+	maxOutputBytes                          = 64 << 20
+	maxProcesses                            = 64
+)
+
+// Reporter stores the final verdict of a submission
+type Reporter func(ctx context.Context, submissionID uuid.UUID, verdict Verdict) error
+
+type TestCase struct {
+	Input  string
+	Output string
+}
+
+// TestCaseLoader is called by the worker so that large test data
+// is only held in memory while the job is being judged
+type TestCaseLoader func(ctx context.Context) ([]TestCase, error)
+
+// Checker decides if an output is correct, the zero value is an exact checker
+type Checker struct {
+	Mode     CheckerMode
+	Epsilon  float64
+	Language string // language of Source for the custom checker
+	Source   string
+}
+
+type Job struct {
+	SubmissionID  uuid.UUID
+	Language      string
+	Solution      string
+	TimeLimitMs   int32
+	MemoryLimitKb int32
+	LoadTestCases TestCaseLoader
+	Checker       Checker
+}
+
+func NewJob(ctx context.Context, job Job) error {
+	if judgeChan == nil {
+		log.Error("judge workers are not started")
+		return flux_errors.ErrJudgeServiceStopped
+	}
+	// when all the workers are busy it shouldn't block indefinetely
+	select {
+	case <-ctx.Done():
+		log.Errorf("judge job cancelled: %v", ctx.Err())
+		return errors.Join(flux_errors.ErrJudgeServiceStopped, ctx.Err())
+
+	case judgeChan <- job:
+		return nil
+	}
+}
@@ -0,0 +1,82 @@
+package judge
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+/*
+	Language describes how a solution is built and run inside its work directory
+	Interpreted languages leave Compile empty. LimitAddressSpace applies RLIMIT_AS
+	when cgroups are not available, runtimes like the jvm and go reserve far more
+	virtual memory than they use, so it is turned off for them
+*/
+
+type Language struct {
+	SourceFile        string   `json:"source_file"`
+	Compile           []string `json:"compile"`
+	Run               []string `json:"run"`
+	LimitAddressSpace bool     `json:"limit_address_space"`
+}
+
+func DefaultLanguages() map[string]Language {
+	return map[string]Language{
+		"c": {
+			SourceFile:        "main.c",
+			Compile:           []string{"gcc", "-O2", "-std=c11", "-o", "main", "main.c", "-lm"},
+			Run:               []string{"./main"},
+			LimitAddressSpace: true,
+		},
+		"cpp": {
+			SourceFile:        "main.cpp",
+			Compile:           []string{"g++", "-O2", "-std=c++17", "-o", "main", "main.cpp"},
+			Run:               []string{"./main"},
+			LimitAddressSpace: true,
+		},
+		"java": {
+			SourceFile: "Main.java",
+			Compile:    []string{"javac", "Main.java"},
+			Run:        []string{"java", "-Xss64m", "-XX:+UseSerialGC", "-cp", ".", "Main"},
+		},
+		"python3": {
+			SourceFile:        "main.py",
+			Run:               []string{"python3", "main.py"},
+			LimitAddressSpace: true,
+		},
+		"go": {
+			SourceFile: "main.go",
+			Compile:    []string{"go", "build", "-o", "main", "main.go"},
+			Run:        []string{"./main"},
+		},
+	}
+}
+
+// LoadLanguages reads a json object of language name to Language,
+// the defaults are used if no file is given
+func LoadLanguages(path string) (map[string]Language, error) {
+	if path == "" {
+		return DefaultLanguages(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read judge languages from %s, %w", path, err)
+	}
+
+	var languages map[string]Language
+	if err = json.Unmarshal(data, &languages); err != nil {
+		return nil, fmt.Errorf("invalid judge languages in %s, %w", path, err)
+	}
+
+	for name, language := range languages {
+		if language.SourceFile == "" || len(language.Run) == 0 {
+			return nil, fmt.Errorf(
+				"judge language %s must have a source_file and a run command",
+				name,
+			)
+		}
+	}
+
+	return languages, nil
+}
@@ -0,0 +1,123 @@
+package judge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type runSpec struct {
+	dir               string
+	args              []string
+	stdin             io.Reader
+	stdout            io.Writer
+	stderr            io.Writer
+	user              sandboxUser
+	timeLimit         time.Duration // cpu time
+	wallLimit         time.Duration
+	memoryLimitKb     int64 // zero for no limit
+	limitAddressSpace bool
+}
+
+type runResult struct {
+	exitCode  int
+	signaled  bool
+	timedOut  bool // killed after the wall limit
+	oomKilled bool
+	cpuTime   time.Duration
+	memoryKb  int64
+}
+
+/*
+	sandboxUser is an unprivileged account the jobs run as
+	KeyJudgeSandboxUID is the first of a range of dedicated uids, two per worker.
+	The first half runs the solutions, one uid per worker, so that concurrent
+	jobs cannot read each others work directories. The second half runs the
+	custom checkers, so a solution cannot touch the checker judging it
+	None of them may own any file of the server
+*/
+
+type sandboxUser struct {
+	uid uint32
+	gid uint32
+}
+
+type workerUsers struct {
+	solution sandboxUser
+	checker  sandboxUser
+}
+
+func loadSandboxUsers(numWorkers int) ([]workerUsers, error) {
+	if os.Geteuid() != 0 {
+		return nil, errors.New("the judge must run as root to switch to the sandbox users")
+	}
+
+	uid, err := strconv.ParseUint(os.Getenv(KeyJudgeSandboxUID), 10, 32)
+	if err != nil || uid == 0 {
+		return nil, fmt.Errorf("%s must be set to the first of %d unprivileged uids", KeyJudgeSandboxUID, 2*numWorkers)
+	}
+	gid, err := strconv.ParseUint(os.Getenv(KeyJudgeSandboxGID), 10, 32)
+	if err != nil || gid == 0 {
+		return nil, fmt.Errorf("%s must be set to an unprivileged gid", KeyJudgeSandboxGID)
+	}
+
+	users := make([]workerUsers, numWorkers)
+	for i := range users {
+		users[i] = workerUsers{
+			solution: sandboxUser{uid: uint32(uid) + uint32(i), gid: uint32(gid)},
+			checker:  sandboxUser{uid: uint32(uid) + uint32(numWorkers+i), gid: uint32(gid)},
+		}
+	}
+	return users, nil
+}
+
+// newSandboxDir creates a work directory that only the sandbox user can use
+func newSandboxDir(pattern string, user sandboxUser) (string, error) {
+	dir, err := os.MkdirTemp(os.Getenv(KeyJudgeWorkDir), pattern)
+	if err != nil {
+		return "", err
+	}
+	if err = os.Chown(dir, int(user.uid), int(user.gid)); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// checkSandbox runs a probe as the sandbox user, the judge must not
+// start if the isolation cannot be set up
+func checkSandbox(user sandboxUser) error {
+	dir, err := newSandboxDir("probe-", user)
+	if err != nil {
+		return fmt.Errorf("cannot create a sandbox directory, %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var output limitedBuffer
+	output.limit = 64
+	result, err := runSandboxed(
+		context.Background(),
+		runSpec{
+			dir:       dir,
+			args:      []string{"id", "-u"},
+			stdin:     strings.NewReader(""),
+			stdout:    &output,
+			stderr:    &output,
+			user:      user,
+			timeLimit: time.Second,
+			wallLimit: 5 * time.Second,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.exitCode != 0 || strings.TrimSpace(output.String()) != strconv.FormatUint(uint64(user.uid), 10) {
+		return fmt.Errorf("sandbox probe did not run as uid %d, %s", user.uid, output.String())
+	}
+	return nil
+}
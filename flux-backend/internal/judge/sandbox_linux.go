@@ -0,0 +1,265 @@
+//go:build linux
+
+package judge
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxOutputFileBlocks = 131072 // 64MB in 512 byte blocks
+)
+
+/*
+	runSandboxed runs a command under rlimits set by a tiny shell wrapper
+	The command runs as the unprivileged sandbox user in its own mount, pid,
+	network, ipc and uts namespaces. The network namespace has no interfaces,
+	so a solution cannot reach the database or anything else, and the pid
+	namespace hides the server and the other jobs
+	If KeyJudgeCgroupRoot points to a delegated cgroup v2 directory, the process
+	is also started inside a fresh child cgroup that caps memory and process count
+	Without the cgroup an allocation beyond RLIMIT_AS usually fails inside the
+	solution, so memory limit exceeded is only reported reliably with cgroups
+*/
+
+func runSandboxed(ctx context.Context, spec runSpec) (runResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, spec.wallLimit)
+	defer cancel()
+
+	// shell wrapper that sets the rlimits and then becomes the command
+	args := append([]string{"/bin/sh", "-c", rlimitScript(spec), "sandbox"}, spec.args...)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = spec.dir
+	cmd.Env = sandboxEnv(spec.dir)
+	cmd.Stdin = spec.stdin
+	cmd.Stdout = spec.stdout
+	cmd.Stderr = spec.stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+		Cloneflags: syscall.CLONE_NEWNS |
+			syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNET |
+			syscall.CLONE_NEWIPC |
+			syscall.CLONE_NEWUTS,
+		Credential: &syscall.Credential{
+			Uid:    spec.user.uid,
+			Gid:    spec.user.gid,
+			Groups: []uint32{},
+		},
+	}
+
+	// memory is capped by the cgroup when one is available
+	var cg *cgroup
+	if root := os.Getenv(KeyJudgeCgroupRoot); root != "" && spec.memoryLimitKb > 0 {
+		var err error
+		cg, err = newCgroup(root, spec.memoryLimitKb)
+		if err != nil {
+			return runResult{}, err
+		}
+		defer cg.remove()
+		cmd.SysProcAttr.UseCgroupFD = true
+		cmd.SysProcAttr.CgroupFD = cg.fd
+	}
+
+	if err := cmd.Start(); err != nil {
+		return runResult{}, fmt.Errorf("cannot start %v, %w", spec.args, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	var result runResult
+	var waitErr error
+	select {
+	case waitErr = <-done:
+	case <-ctx.Done():
+		result.timedOut = true
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		waitErr = <-done
+	}
+
+	// kill anything the solution left behind
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+
+	var exitErr *exec.ExitError
+	if waitErr != nil && !errors.As(waitErr, &exitErr) {
+		return runResult{}, fmt.Errorf("cannot wait for %v, %w", spec.args, waitErr)
+	}
+
+	state := cmd.ProcessState
+	result.cpuTime = state.UserTime() + state.SystemTime()
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// maxrss is in kilobytes on linux
+		result.memoryKb = usage.Maxrss
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok {
+		result.signaled = status.Signaled()
+		result.exitCode = status.ExitStatus()
+	}
+	if cg != nil {
+		result.oomKilled = cg.oomKilled()
+		if peak := cg.memoryPeakKb(); peak > result.memoryKb {
+			result.memoryKb = peak
+		}
+	}
+
+	return result, nil
+}
+
+func rlimitScript(spec runSpec) string {
+	// rlimit cpu is in whole seconds, the exact limit is checked from rusage
+	cpuSeconds := int64(spec.timeLimit/time.Second) + 1
+	limits := []string{
+		"ulimit -c 0",
+		fmt.Sprintf("ulimit -t %d", cpuSeconds),
+		fmt.Sprintf("ulimit -f %d", maxOutputFileBlocks),
+	}
+	if spec.memoryLimitKb > 0 && spec.limitAddressSpace && os.Getenv(KeyJudgeCgroupRoot) == "" {
+		limits = append(limits, fmt.Sprintf("ulimit -v %d", spec.memoryLimitKb))
+	}
+	// the stack may not be raised above the hard limit, that is not fatal
+	stack := ""
+	if spec.memoryLimitKb > 0 {
+		stack = fmt.Sprintf("ulimit -s %d 2>/dev/null; ", spec.memoryLimitKb)
+	}
+	return stack + strings.Join(limits, " && ") + ` && exec "$@"`
+}
+
+func sandboxEnv(dir string) []string {
+	return []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + dir,
+		"TMPDIR=" + dir,
+		"GOCACHE=" + filepath.Join(dir, ".gocache"),
+		"GOPATH=" + filepath.Join(dir, ".gopath"),
+		"LANG=C.UTF-8",
+	}
+}
+
+type cgroup struct {
+	path string
+	fd   int
+}
+
+func newCgroup(root string, memoryLimitKb int64) (*cgroup, error) {
+	path := filepath.Join(root, "run-"+uuid.NewString())
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create cgroup %s, %w", path, err)
+	}
+
+	cg := &cgroup{path: path, fd: -1}
+	limits := []struct {
+		file     string
+		value    string
+		optional bool
+	}{
+		{"memory.max", strconv.FormatInt(memoryLimitKb*1024, 10), false},
+		{"memory.swap.max", "0", true},
+		{"pids.max", strconv.Itoa(maxProcesses), true},
+	}
+	for _, limit := range limits {
+		err := os.WriteFile(filepath.Join(path, limit.file), []byte(limit.value), 0o644)
+		if err != nil && !limit.optional {
+			cg.remove()
+			return nil, fmt.Errorf("cannot set %s of cgroup %s, %w", limit.file, path, err)
+		}
+	}
+
+	fd, err := syscall.Open(path, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		cg.remove()
+		return nil, fmt.Errorf("cannot open cgroup %s, %w", path, err)
+	}
+	cg.fd = fd
+
+	return cg, nil
+}
+
+func (cg *cgroup) oomKilled() bool {
+	data, err := os.ReadFile(filepath.Join(cg.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == "oom_kill" {
+			count, _ := strconv.Atoi(fields[1])
+			return count > 0
+		}
+	}
+	return false
+}
+
+// memory.peak is only available on newer kernels
+func (cg *cgroup) memoryPeakKb() int64 {
+	data, err := os.ReadFile(filepath.Join(cg.path, "memory.peak"))
+	if err != nil {
+		return 0
+	}
+	peak, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return peak / 1024
+}
+
+func (cg *cgroup) remove() {
+	if cg.fd >= 0 {
+		syscall.Close(cg.fd)
+	}
+	os.Remove(cg.path)
+}
+
+// sealDir hands a directory prepared by a sandbox user to root, read and
+// execute only, so no sandbox user can change what is in it afterwards
+func sealDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		// links could point root at files outside the directory
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			return os.Remove(path)
+		case !info.Mode().IsRegular() && !info.IsDir():
+			return fmt.Errorf("%s is not a regular file", path)
+		}
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && !info.IsDir() && stat.Nlink > 1 {
+			return fmt.Errorf("%s is a hard link", path)
+		}
+
+		mode := fs.FileMode(0o444)
+		if info.IsDir() {
+			mode = 0o755
+		} else if info.Mode()&0o111 != 0 {
+			mode = 0o555
+		}
+		if err = os.Chown(path, 0, 0); err != nil {
+			return err
+		}
+		return os.Chmod(path, mode)
+	})
+}
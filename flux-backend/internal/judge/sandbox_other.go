@@ -0,0 +1,16 @@
+//go:build !linux
+
+package judge
+
+import (
+	"context"
+	"errors"
+)
+
+func runSandboxed(ctx context.Context, spec runSpec) (runResult, error) {
+	return runResult{}, errors.New("the judge sandbox is only supported on linux")
+}
+
+func sealDir(dir string) error {
+	return errors.New("the judge sandbox is only supported on linux")
+}
@@ -0,0 +1,224 @@
+package judge
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	judgeChan chan Job
+	once      sync.Once
+)
+
+// StartJudgeWorkers refuses to start if the sandbox cannot isolate the jobs
+func StartJudgeWorkers(numWorkers int, languages map[string]Language, reporter Reporter) error {
+	var err error
+	// using sync once to ensure that this happens only once even if the function is called multiple times
+	once.Do(func() {
+		var users []workerUsers
+		users, err = loadSandboxUsers(numWorkers)
+		if err != nil {
+			return
+		}
+		for _, user := range users {
+			if err = checkSandbox(user.solution); err != nil {
+				return
+			}
+			if err = checkSandbox(user.checker); err != nil {
+				return
+			}
+		}
+
+		judgeChan = make(chan Job, defaultJudgeChannelCapacity)
+		log.Infof("starting %d judge workers", numWorkers)
+		for i, user := range users {
+			go worker(i+1, user, languages, reporter)
+		}
+	})
+	return err
+}
+
+/*
+	Worker listens through the judgeChan in an infinite loop
+	Once it gets a job, it compiles the solution in a fresh work directory,
+	runs it against every test case in order and reports the first failing verdict
+*/
+
+func worker(id int, users workerUsers, languages map[string]Language, reporter Reporter) {
+	workerLogger := log.WithField("judge_worker", id)
+	for job := range judgeChan {
+		jobLogger := workerLogger.WithFields(
+			log.Fields{
+				"submission_id": job.SubmissionID,
+				"language":      job.Language,
+			},
+		)
+
+		verdict := judgeJob(job, users, languages, jobLogger)
+		jobLogger.Infof("submission judged as %s", verdict)
+
+		if err := reporter(context.Background(), job.SubmissionID, verdict); err != nil {
+			jobLogger.Errorf("cannot report verdict, %v", err)
+		}
+	}
+	workerLogger.Info("judge channel is closed. judge worker stopped")
+}
+
+func judgeJob(job Job, users workerUsers, languages map[string]Language, jobLogger *log.Entry) Verdict {
+	language, ok := languages[job.Language]
+	if !ok {
+		jobLogger.Error("language is not configured for the judge")
+		return VerdictJudgeError
+	}
+	testCases, err := job.LoadTestCases(context.Background())
+	if err != nil {
+		jobLogger.Errorf("cannot load test cases, %v", err)
+		return VerdictJudgeError
+	}
+	if len(testCases) == 0 {
+		jobLogger.Error("problem has no test cases")
+		return VerdictJudgeError
+	}
+
+	// every job gets its own work directory
+	dir, err := newSandboxDir("judge-", users.solution)
+	if err != nil {
+		jobLogger.Errorf("cannot create work directory, %v", err)
+		return VerdictJudgeError
+	}
+	defer os.RemoveAll(dir)
+
+	err = os.WriteFile(filepath.Join(dir, language.SourceFile), []byte(job.Solution), 0o644)
+	if err != nil {
+		jobLogger.Errorf("cannot write the solution, %v", err)
+		return VerdictJudgeError
+	}
+
+	// compile
+	if len(language.Compile) > 0 {
+		var compileOutput limitedBuffer
+		compileOutput.limit = 4096
+		result, err := runSandboxed(
+			context.Background(),
+			runSpec{
+				dir:       dir,
+				args:      language.Compile,
+				stdin:     strings.NewReader(""),
+				stdout:    &compileOutput,
+				stderr:    &compileOutput,
+				user:      users.solution,
+				timeLimit: compileTimeLimit,
+				wallLimit: 2 * compileTimeLimit,
+			},
+		)
+		if err != nil {
+			jobLogger.Errorf("cannot run the compiler, %v", err)
+			return VerdictJudgeError
+		}
+		if result.timedOut || result.signaled || result.exitCode != 0 {
+			jobLogger.Debugf("compilation failed, %s", compileOutput.String())
+			return VerdictCompilationError
+		}
+	}
+
+	// prepare the custom checker
+	var checker *customChecker
+	if job.Checker.Mode == CheckerCustom {
+		checker, err = compileChecker(job.Checker, users.checker, languages)
+		if err != nil {
+			jobLogger.Errorf("cannot prepare the custom checker, %v", err)
+			return VerdictJudgeError
+		}
+		defer checker.remove()
+	}
+
+	// run against every test case
+	timeLimit := time.Duration(job.TimeLimitMs) * time.Millisecond
+	for i, testCase := range testCases {
+		var output limitedBuffer
+		output.limit = maxOutputBytes
+		result, err := runSandboxed(
+			context.Background(),
+			runSpec{
+				dir:               dir,
+				args:              language.Run,
+				stdin:             strings.NewReader(testCase.Input),
+				stdout:            &output,
+				stderr:            &limitedBuffer{limit: 0},
+				user:              users.solution,
+				timeLimit:         timeLimit,
+				wallLimit:         3*timeLimit + time.Second,
+				memoryLimitKb:     int64(job.MemoryLimitKb),
+				limitAddressSpace: language.LimitAddressSpace,
+			},
+		)
+		if err != nil {
+			jobLogger.Errorf("cannot run test case %d, %v", i+1, err)
+			return VerdictJudgeError
+		}
+
+		verdict := runVerdict(result, timeLimit, int64(job.MemoryLimitKb))
+		if verdict == VerdictAccepted {
+			switch {
+			case output.exceeded:
+				verdict = VerdictRuntimeError
+			case checker != nil:
+				verdict = checker.check(
+					[]byte(testCase.Input),
+					output.Bytes(),
+					[]byte(testCase.Output),
+					jobLogger,
+				)
+			case !outputsMatch(job.Checker, []byte(testCase.Output), output.Bytes()):
+				verdict = VerdictWrongAnswer
+			}
+		}
+		if verdict != VerdictAccepted {
+			jobLogger.Debugf("failed on test case %d with %s", i+1, verdict)
+			return verdict
+		}
+	}
+
+	return VerdictAccepted
+}
+
+func runVerdict(result runResult, timeLimit time.Duration, memoryLimitKb int64) Verdict {
+	switch {
+	case result.oomKilled:
+		return VerdictMemoryLimitExceeded
+	case result.timedOut || result.cpuTime > timeLimit:
+		return VerdictTimeLimitExceeded
+	case result.memoryKb > memoryLimitKb:
+		return VerdictMemoryLimitExceeded
+	case result.signaled || result.exitCode != 0:
+		return VerdictRuntimeError
+	default:
+		return VerdictAccepted
+	}
+}
+
+// limitedBuffer keeps at most limit bytes and remembers if more were written
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); len(p) > remaining {
+		b.exceeded = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		// pretend everything was written so that the process isn't blocked
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
@@ -0,0 +1,111 @@
+package rate_limit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/tcp_snm/flux/internal/database"
+)
+
+// PGStore keeps the buckets and failures in postgres so that
+// every instance sees the same limits, updates are single statements
+type PGStore struct {
+	DB *database.Queries
+}
+
+func (s *PGStore) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	// refill and take a token in one statement, nothing is updated if the bucket is empty
+	_, err := s.DB.TakeRateLimitToken(
+		ctx,
+		database.TakeRateLimitTokenParams{
+			Key:   key,
+			Burst: float64(limit.Burst),
+			Rate:  limit.Rate,
+		},
+	)
+	if err == nil {
+		return true, 0, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return false, 0, fmt.Errorf("cannot take a token of %s, %w", key, err)
+	}
+
+	// empty, find out how long until the next token
+	tokens, err := s.DB.GetRateLimitTokens(
+		ctx,
+		database.GetRateLimitTokensParams{
+			Burst: float64(limit.Burst),
+			Rate:  limit.Rate,
+			Key:   key,
+		},
+	)
+	if err != nil {
+		return false, 0, fmt.Errorf("cannot read bucket of %s, %w", key, err)
+	}
+	return false, waitFor(tokens, limit), nil
+}
+
+func (s *PGStore) AddFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	failures, err := s.DB.AddRateLimitFailure(
+		ctx,
+		database.AddRateLimitFailureParams{
+			Key:           key,
+			WindowSeconds: window.Seconds(),
+		},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("cannot add failure of %s, %w", key, err)
+	}
+	return int(failures), nil
+}
+
+func (s *PGStore) ResetFailures(ctx context.Context, key string) error {
+	err := s.DB.ResetRateLimitFailures(ctx, key)
+	if err != nil {
+		return fmt.Errorf("cannot reset failures of %s, %w", key, err)
+	}
+	return nil
+}
+
+func (s *PGStore) Lock(ctx context.Context, key string, until time.Time) error {
+	err := s.DB.LockRateLimitKey(
+		ctx,
+		database.LockRateLimitKeyParams{
+			Key:         key,
+			LockedUntil: &until,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("cannot lock %s, %w", key, err)
+	}
+	return nil
+}
+
+func (s *PGStore) LockedUntil(ctx context.Context, key string) (time.Time, error) {
+	lockedUntil, err := s.DB.GetRateLimitLockedUntil(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("cannot read lock of %s, %w", key, err)
+	}
+	if lockedUntil == nil {
+		return time.Time{}, nil
+	}
+	return *lockedUntil, nil
+}
+
+func (s *PGStore) Prune(ctx context.Context, olderThan time.Duration) error {
+	err := s.DB.PruneRateLimitBuckets(ctx, olderThan.Seconds())
+	if err != nil {
+		return fmt.Errorf("cannot prune buckets, %w", err)
+	}
+	err = s.DB.PruneRateLimitFailures(ctx, olderThan.Seconds())
+	if err != nil {
+		return fmt.Errorf("cannot prune failures, %w", err)
+	}
+	return nil
+}
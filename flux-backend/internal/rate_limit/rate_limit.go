@@ -0,0 +1,166 @@
+package rate_limit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+const (
+	KeyRateLimitStore = "RATE_LIMIT_STORE"
+	StoreMemory       = "memory"
+	StorePostgres     = "postgres"
+	pruneInterval     = time.Minute * 10
+	// buckets and failures untouched for this long are forgotten
+	staleAfter = time.Hour
+)
+
+// Limit is a token bucket, it holds up to Burst tokens
+// and is refilled with Rate tokens every second
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+var (
+	// requests to unauthenticated endpoints from one ip, 10 per minute
+	IPLimit = Limit{Rate: 10.0 / 60, Burst: 10}
+	// attempts on one account (login, reset mails), 5 per 5 minutes
+	AccountLimit = Limit{Rate: 1.0 / 60, Burst: 5}
+	// an account is locked for LockoutDuration after MaxLoginFailures
+	// wrong passwords within FailureWindow
+	MaxLoginFailures = 5
+	FailureWindow    = time.Minute * 15
+	LockoutDuration  = time.Minute * 15
+)
+
+/*
+	Store keeps the buckets and login failures. The in memory store is enough
+	for a single instance, multiple instances must share the postgres store
+	so that a client cannot spread its attempts across them
+*/
+
+type Store interface {
+	// Allow takes a token from the bucket of the key, if there is none
+	// it reports how long to wait for the next one
+	Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error)
+	// AddFailure counts a failure of the key and returns the failures within the window
+	AddFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	ResetFailures(ctx context.Context, key string) error
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns the zero time if the key is not locked
+	LockedUntil(ctx context.Context, key string) (time.Time, error)
+	// Prune forgets the keys untouched for longer than olderThan
+	Prune(ctx context.Context, olderThan time.Duration) error
+}
+
+// Limiter applies the limits of flux on top of a Store
+type Limiter struct {
+	Store Store
+}
+
+func NewLimiter(store Store) *Limiter {
+	l := &Limiter{Store: store}
+	go l.pruneLoop()
+	return l
+}
+
+// AllowIP takes a token from the bucket of the ip
+func (l *Limiter) AllowIP(ctx context.Context, ip string) (time.Duration, error) {
+	return l.allow(ctx, "ip:"+ip, IPLimit)
+}
+
+// AllowAccount takes a token from the bucket of the account
+// (user name, roll no or email) and rejects locked accounts
+func (l *Limiter) AllowAccount(ctx context.Context, account string) (time.Duration, error) {
+	lockedUntil, err := l.Store.LockedUntil(ctx, "lock:"+account)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot check lock of %s, %w", flux_errors.ErrInternal, account, err)
+		log.Error(err)
+		return 0, err
+	}
+	if wait := time.Until(lockedUntil); wait > 0 {
+		return wait, fmt.Errorf(
+			"%w, account is locked after repeated failed logins, try again in %v",
+			flux_errors.ErrTooManyRequests,
+			wait.Round(time.Second),
+		)
+	}
+
+	return l.allow(ctx, "account:"+account, AccountLimit)
+}
+
+// RecordLoginFailure counts a wrong password for the account
+// and locks it once there are too many of them
+func (l *Limiter) RecordLoginFailure(ctx context.Context, account string) error {
+	failures, err := l.Store.AddFailure(ctx, "lock:"+account, FailureWindow)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot record failure of %s, %w", flux_errors.ErrInternal, account, err)
+		log.Error(err)
+		return err
+	}
+	if failures < MaxLoginFailures {
+		return nil
+	}
+
+	err = l.Store.Lock(ctx, "lock:"+account, time.Now().Add(LockoutDuration))
+	if err != nil {
+		err = fmt.Errorf("%w, cannot lock %s, %w", flux_errors.ErrInternal, account, err)
+		log.Error(err)
+		return err
+	}
+	log.Warnf("account %s locked for %v after %v failed logins", account, LockoutDuration, failures)
+	return nil
+}
+
+// ResetLoginFailures forgets the failures of the account after a successful login
+func (l *Limiter) ResetLoginFailures(ctx context.Context, account string) {
+	if err := l.Store.ResetFailures(ctx, "lock:"+account); err != nil {
+		log.Errorf("cannot reset login failures of %s, %v", account, err)
+	}
+}
+
+func (l *Limiter) allow(ctx context.Context, key string, limit Limit) (time.Duration, error) {
+	allowed, wait, err := l.Store.Allow(ctx, key, limit)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot check rate limit of %s, %w", flux_errors.ErrInternal, key, err)
+		log.Error(err)
+		return 0, err
+	}
+	if !allowed {
+		log.Warnf("rate limit exceeded by %s", key)
+		return wait, fmt.Errorf(
+			"%w, try again in %v",
+			flux_errors.ErrTooManyRequests,
+			wait.Round(time.Second),
+		)
+	}
+	return 0, nil
+}
+
+func (l *Limiter) pruneLoop() {
+	ticker := time.NewTicker(pruneInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := l.Store.Prune(context.Background(), staleAfter); err != nil {
+			log.Errorf("cannot prune rate limits, %v", err)
+		}
+	}
+}
+
+// refill returns the tokens in a bucket after elapsed time
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	return math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
+
+// waitFor returns how long it takes for the bucket to have a token
+func waitFor(tokens float64, limit Limit) time.Duration {
+	if tokens >= 1 || limit.Rate <= 0 {
+		return 0
+	}
+	return time.Duration((1 - tokens) / limit.Rate * float64(time.Second))
+}
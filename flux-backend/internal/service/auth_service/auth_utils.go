@@ -4,9 +4,11 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"errors"
+	"strings"
 	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
@@ -17,10 +19,12 @@ import (
 func dbUserToUserCredClaims(
 	expirationTime time.Time,
 	user database.User,
+	sessionID uuid.UUID,
 ) service.UserCredentialClaims {
 	return service.UserCredentialClaims{
-		UserId:   user.ID,
-		UserName: user.UserName,
+		UserId:    user.ID,
+		UserName:  user.UserName,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime), // Set the expiration time
 			IssuedAt:  jwt.NewNumericDate(time.Now()),     // When the token was issued
@@ -47,8 +51,16 @@ func userRegToResponse(user_name string, userReg UserRegestration) UserRegestrat
 	}
 }
 
+// verification tokens are sent as links so they can be long,
+// the encoded token must stay below the 72 byte limit of bcrypt
+const verificationTokenBytes = 32
+
 func generateToken() (string, error) {
-	tokenBytes := make([]byte, 9)
+	return generateRandomToken(verificationTokenBytes)
+}
+
+func generateRandomToken(numBytes int) (string, error) {
+	tokenBytes := make([]byte, numBytes)
 
 	// Read random data from the cryptographically secure source.
 	_, err := rand.Read(tokenBytes)
@@ -59,9 +71,9 @@ func generateToken() (string, error) {
 		return "", errors.Join(flux_errors.ErrInternal, err)
 	}
 
-	// Encode the random bytes to a URL-safe base64 string.
+	// Encode the random bytes to a URL-safe base64 string without padding.
 	// This makes the token safe to include in URLs and emails.
-	token := base64.URLEncoding.EncodeToString(tokenBytes)
+	token := base64.RawURLEncoding.EncodeToString(tokenBytes)
 
 	return token, nil
 
@@ -76,3 +88,25 @@ func generatePasswordHash(password string) (string, error) {
 	}
 	return string(passwordHash), nil
 }
+
+// validatePassword applies the rules every new password must follow
+func validatePassword(password string) error {
+	return service.ValidateInput(
+		struct {
+			// password greater than 74 characters in length cannot be hashed
+			Password string `json:"password" validate:"required,min=7,max=74"`
+		}{
+			Password: password,
+		},
+	)
+}
+
+// rate limit keys, a user is throttled the same whether
+// they login with user_name or roll_no
+func loginAccountKey(user database.User) string {
+	return "login:" + user.ID.String()
+}
+
+func mailAccountKey(userEmail string) string {
+	return "mail:" + strings.ToLower(userEmail)
+}
@@ -0,0 +1,192 @@
+package auth_service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// tokens are looked up by email, so the payload
+// tells whose email is being changed
+type emailChangePayload struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// ChangeEmailSendMail sends a verification token to the new email
+func (a *AuthService) ChangeEmailSendMail(
+	ctx context.Context,
+	newEmail string,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the new email must be free
+	taken, err := a.DB.IsEmailTaken(ctx, newEmail)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check if email is taken, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if taken {
+		return fmt.Errorf(
+			"%w, email is already in use",
+			flux_errors.ErrUserAlreadyExists,
+		)
+	}
+
+	// send verification mail
+	payload, err := json.Marshal(emailChangePayload{UserID: claims.UserId})
+	if err != nil {
+		log.Errorf("cannot marshal email change payload, %v", err)
+		return errors.Join(flux_errors.ErrInternal, err)
+	}
+	err = a.sendVerificationEmail(ctx, newEmail, email.PurposeEmailChange, payload)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("user %s requested an email change", claims.UserName)
+	return nil
+}
+
+// ChangeEmail changes the email of the user once the token
+// sent to the new email is verified
+func (a *AuthService) ChangeEmail(
+	ctx context.Context,
+	newEmail string,
+	token string,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// verify token
+	dbToken, err := a.validateVerificationToken(
+		ctx,
+		token,
+		newEmail,
+		email.PurposeEmailChange,
+	)
+	if err != nil {
+		return err
+	}
+
+	// the token must have been requested by the same user
+	var payload emailChangePayload
+	if err = json.Unmarshal(dbToken.Payload, &payload); err != nil || payload.UserID != claims.UserId {
+		log.WithFields(log.Fields{
+			"user_name": claims.UserName,
+			"purpose":   string(email.PurposeEmailChange),
+		}).Warn("email change token was not requested by this user")
+		return fmt.Errorf(
+			"%w, please cross check your token",
+			flux_errors.ErrCorruptedVerification,
+		)
+	}
+
+	// get the old email to notify
+	user, err := a.DB.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	// the token is used up together with the change
+	if _, err = a.consumeVerificationToken(ctx, qtx, dbToken); err != nil {
+		return err
+	}
+
+	// update
+	_, err = qtx.UpdateUserEmail(
+		ctx,
+		database.UpdateUserEmailParams{
+			ID:    claims.UserId,
+			Email: newEmail,
+		},
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == flux_errors.CodeUniqueConstraintViolation {
+			return fmt.Errorf(
+				"%w, email is already in use",
+				flux_errors.ErrUserAlreadyExists,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot change email of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit email change of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// let the old email know, a failure must not undo the change
+	err = email.NewMail(
+		ctx,
+		"Flux account email changed",
+		fmt.Sprintf(
+			"the email of your flux account %s was changed to %s, if this is not you please inform.",
+			user.UserName,
+			newEmail,
+		),
+		email.KeyEmailBodyPlain,
+		email.PurposeEmailChange,
+		user.Email,
+	)
+	if err != nil {
+		log.Errorf("cannot notify %s about the email change, %v", user.UserName, err)
+	}
+
+	log.Infof("user %s changed their email", claims.UserName)
+	return nil
+}
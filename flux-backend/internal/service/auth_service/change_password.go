@@ -0,0 +1,154 @@
+package auth_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword changes the password of the logged in user after checking
+// the current one, every other session of the user is logged out
+func (a *AuthService) ChangePassword(
+	ctx context.Context,
+	currentPassword string,
+	newPassword string,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get user from db
+	user, err := a.DB.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// a stolen session must not be able to guess the password either
+	loginAccount := loginAccountKey(user)
+	if _, err = a.Limiter.AllowAccount(ctx, loginAccount); err != nil {
+		return err
+	}
+
+	// validate the current password
+	bcErr := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(currentPassword))
+	if bcErr != nil {
+		if errors.Is(bcErr, bcrypt.ErrMismatchedHashAndPassword) {
+			log.Warnf("user %s tried to change password with a wrong password", claims.UserName)
+			if err = a.Limiter.RecordLoginFailure(ctx, loginAccount); err != nil {
+				return err
+			}
+			return fmt.Errorf(
+				"%w, current password is incorrect",
+				flux_errors.ErrInvalidUserCredentials,
+			)
+		}
+		log.Errorf("failed to compare password of %s. %v", claims.UserName, bcErr)
+		return errors.Join(flux_errors.ErrInternal, bcErr)
+	}
+
+	// validate the new password
+	if err = validatePassword(newPassword); err != nil {
+		return err
+	}
+	if newPassword == currentPassword {
+		return fmt.Errorf(
+			"%w, new password must be different from the current one",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// generate password hash
+	passwordHash, err := generatePasswordHash(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	// update the password
+	if err = qtx.ResetPassword(ctx, database.ResetPasswordParams{
+		PasswordHash: passwordHash,
+		UserName:     user.UserName,
+	}); err != nil {
+		err = fmt.Errorf(
+			"%w, unable to change password of %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// whoever knew the old password is logged out
+	n, err := qtx.RevokeOtherUserSessions(ctx, database.RevokeOtherUserSessionsParams{
+		UserID: claims.UserId,
+		ID:     claims.SessionID,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot revoke other sessions of %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after changing password, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// notify, a failure must not undo the change
+	err = email.NewMail(
+		ctx,
+		"Flux account password changed",
+		fmt.Sprintf(
+			"the password of your flux account %s was changed, if this is not you please reset your password and inform.",
+			user.UserName,
+		),
+		email.KeyEmailBodyPlain,
+		email.PurposeEmailPasswordChange,
+		user.Email,
+	)
+	if err != nil {
+		log.Errorf("cannot notify %s about the password change, %v", claims.UserName, err)
+	}
+
+	log.Infof("user %s changed their password, %v other sessions revoked", claims.UserName, n)
+	return nil
+}
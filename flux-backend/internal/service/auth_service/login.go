@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"time"
 
 	jwt "github.com/golang-jwt/jwt/v4"
 	log "github.com/sirupsen/logrus"
@@ -20,17 +19,26 @@ func (a *AuthService) Login(
 	rollNo string,
 	password string,
 	rememberForMonth bool,
-) (userLoginResponse UserLoginResponse, tokenString string, tokenExpiry time.Time, err error) {
+) (userLoginResponse UserLoginResponse, tokens SessionTokens, err error) {
 	// get user from db
 	user, err := a.UserConfig.GetUserByUserNameOrRollNo(ctx, userName, rollNo)
 	if err != nil {
 		return
 	}
 
+	// throttle the attempts on the account, whichever key is used
+	loginAccount := loginAccountKey(user)
+	if _, err = a.Limiter.AllowAccount(ctx, loginAccount); err != nil {
+		return
+	}
+
 	// validate the password
 	bcErr := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password))
 	if bcErr != nil {
 		if errors.Is(bcErr, bcrypt.ErrMismatchedHashAndPassword) {
+			if err = a.Limiter.RecordLoginFailure(ctx, loginAccount); err != nil {
+				return
+			}
 			err = flux_errors.ErrInvalidUserCredentials
 			return
 		}
@@ -38,31 +46,10 @@ func (a *AuthService) Login(
 		err = errors.Join(flux_errors.ErrInternal, bcErr)
 		return
 	}
+	a.Limiter.ResetLoginFailures(ctx, loginAccount)
 
-	// fetch user roles to store in claims
-	roles, err := a.UserConfig.FetchUserRoles(ctx, user.ID)
-	if err != nil {
-		return
-	}
-
-	// generate a jwt token
-	var duration = time.Hour * 24
-	if rememberForMonth {
-		duration *= 30
-	}
-	var exp = time.Now().Add(duration)
-
-	// claims store the user data to avoid repeated logins via jwt
-	claims := dbUserToUserCredClaims(exp, user)
-	tokenString, err = GenerateJWT(claims)
-	if err != nil {
-		return
-	}
-
-	tokenExpiry = exp
-	userLoginResponse = dbUserToLoginRes(roles, user)
-
-	return
+	// start a new session, or ask for the 2fa code
+	return a.startLogin(ctx, user, rememberForMonth)
 }
 
 func GenerateJWT(claims service.UserCredentialClaims) (tokenString string, err error) {
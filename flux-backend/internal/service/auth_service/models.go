@@ -1,13 +1,19 @@
 package auth_service
 
 import (
+	"time"
+
 	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/rate_limit"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 type AuthService struct {
 	DB         *database.Queries
 	UserConfig *user_service.UserService
+	Limiter    *rate_limit.Limiter
+	// nil when oidc login is not configured
+	OIDC *OIDCProvider
 }
 
 type UserRegestration struct {
@@ -30,4 +36,27 @@ type UserLoginResponse struct {
 	FirstName string
 	LastName  string
 	Roles     []string
+	// set instead of a session when the login needs a 2fa code
+	TwoFactor *TwoFactorChallenge `json:",omitempty"`
+	// shown once, when 2fa was enabled during the login
+	RecoveryCodes []string `json:",omitempty"`
+}
+
+// TwoFactorChallenge is answered with a totp or recovery code to finish the login.
+// SetupRequired is set when the user must enroll 2fa first
+type TwoFactorChallenge struct {
+	ChallengeToken string    `json:"challenge_token"`
+	SetupRequired  bool      `json:"setup_required"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type TwoFactorEnrollment struct {
+	Secret     string `json:"secret"`
+	OtpAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
 }
@@ -0,0 +1,227 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+const oidcRandomBytes = 32
+
+// OIDCLoginRequest is kept by the client between the redirect
+// to the provider and the callback, binding the callback to the same browser
+type OIDCLoginRequest struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+}
+
+// BeginOIDCLogin returns the url of the provider's login page
+func (a *AuthService) BeginOIDCLogin(ctx context.Context) (authURL string, request OIDCLoginRequest, err error) {
+	if a.OIDC == nil {
+		err = fmt.Errorf("%w, oidc login is not configured", flux_errors.ErrNotFound)
+		return
+	}
+
+	// state protects the callback, nonce the id token and verifier the code
+	if request.State, err = generateRandomToken(oidcRandomBytes); err != nil {
+		return
+	}
+	if request.Nonce, err = generateRandomToken(oidcRandomBytes); err != nil {
+		return
+	}
+	if request.CodeVerifier, err = generateRandomToken(oidcRandomBytes); err != nil {
+		return
+	}
+	challenge := sha256.Sum256([]byte(request.CodeVerifier))
+
+	authURL, err = a.OIDC.AuthURL(
+		ctx,
+		request.State,
+		request.Nonce,
+		base64.RawURLEncoding.EncodeToString(challenge[:]),
+	)
+	return
+}
+
+// OIDCLogin completes the login after the provider redirected back with the code.
+// The user is matched by the roll_no or email claim (or created) and
+// gets the same session as a password login
+func (a *AuthService) OIDCLogin(
+	ctx context.Context,
+	code string,
+	state string,
+	request OIDCLoginRequest,
+) (userLoginResponse UserLoginResponse, tokens SessionTokens, err error) {
+	if a.OIDC == nil {
+		err = fmt.Errorf("%w, oidc login is not configured", flux_errors.ErrNotFound)
+		return
+	}
+
+	// the state must be the one this browser started with
+	if code == "" || request.State == "" ||
+		subtle.ConstantTimeCompare([]byte(state), []byte(request.State)) != 1 {
+		err = fmt.Errorf("%w, invalid oidc state", flux_errors.ErrInvalidRequestCredentials)
+		return
+	}
+
+	// exchange the code for verified claims
+	claims, err := a.OIDC.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		return
+	}
+
+	// map the claims to a user
+	user, err := a.getOrCreateOIDCUser(ctx, claims)
+	if err != nil {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"user_name": user.UserName,
+		"subject":   claims["sub"],
+	}).Info("authenticated with oidc")
+
+	// the provider does not replace 2fa, it is asked like for a password login
+	return a.startLogin(ctx, user, false)
+}
+
+func (a *AuthService) getOrCreateOIDCUser(
+	ctx context.Context,
+	claims jwt.MapClaims,
+) (database.User, error) {
+	config := a.OIDC.Config
+	rollNo, userEmail := oidcIdentity(config, claims)
+	if rollNo == "" && userEmail == "" {
+		return database.User{}, fmt.Errorf(
+			"%w, identity provider did not share a roll_no or a verified email",
+			flux_errors.ErrInvalidRequestCredentials,
+		)
+	}
+
+	// roll numbers are preferred, emails can be changed by the users
+	var user database.User
+	var err error = sql.ErrNoRows
+	if rollNo != "" {
+		user, err = a.DB.GetUserByRollNumber(ctx, rollNo)
+	}
+	if errors.Is(err, sql.ErrNoRows) && userEmail != "" {
+		user, err = a.DB.GetUserByEmail(ctx, userEmail)
+	}
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		err = fmt.Errorf("%w, cannot fetch user of oidc login, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return database.User{}, err
+	}
+
+	if !config.CreateUsers {
+		return database.User{}, fmt.Errorf(
+			"%w, no flux account is linked to this identity, please sign up first",
+			flux_errors.ErrInvalidUserCredentials,
+		)
+	}
+	return a.createOIDCUser(ctx, claims, rollNo, userEmail)
+}
+
+// createOIDCUser signs up a user with a random password,
+// they can set one later through reset password
+func (a *AuthService) createOIDCUser(
+	ctx context.Context,
+	claims jwt.MapClaims,
+	rollNo string,
+	userEmail string,
+) (database.User, error) {
+	config := a.OIDC.Config
+	password, err := generateRandomToken(oidcRandomBytes)
+	if err != nil {
+		return database.User{}, err
+	}
+	userRegestration := UserRegestration{
+		FirstName: stringClaim(claims, config.FirstNameClaim),
+		LastName:  stringClaim(claims, config.LastNameClaim),
+		RollNo:    rollNo,
+		Password:  password,
+		UserMail:  userEmail,
+	}
+
+	// the claims must satisfy the same rules as a sign up
+	if err = service.ValidateInput(userRegestration); err != nil {
+		return database.User{}, fmt.Errorf(
+			"%w, cannot create an account from the identity provider's claims",
+			err,
+		)
+	}
+
+	passwordHash, err := generatePasswordHash(password)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	user, err := a.createUserInDB(ctx, tx, userRegestration, passwordHash)
+	if err != nil {
+		return database.User{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("%w, cannot commit oidc user, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return database.User{}, err
+	}
+
+	log.WithFields(log.Fields{
+		"user_name": user.UserName,
+		"roll_no":   user.RollNo,
+	}).Info("created user from oidc login")
+	return user, nil
+}
+
+// oidcIdentity reads the claims that can identify a user
+func oidcIdentity(config OIDCConfig, claims jwt.MapClaims) (rollNo string, userEmail string) {
+	rollNo = stringClaim(claims, config.RollNoClaim)
+	userEmail = stringClaim(claims, config.EmailClaim)
+
+	// an email the provider did not say it verified cannot identify anyone
+	if verified, _ := claims["email_verified"].(bool); !verified {
+		userEmail = ""
+	}
+	return
+}
+
+// stringClaim reads a claim as string, numeric claims are formatted
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch value := claims[name].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
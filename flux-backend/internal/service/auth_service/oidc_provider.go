@@ -0,0 +1,382 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+const (
+	// discovery document of the identity provider,
+	// usually <issuer>/.well-known/openid-configuration
+	KeyOIDCDiscoveryURL = "OIDC_DISCOVERY_URL"
+	KeyOIDCClientID     = "OIDC_CLIENT_ID"
+	KeyOIDCClientSecret = "OIDC_CLIENT_SECRET"
+	KeyOIDCRedirectURL  = "OIDC_REDIRECT_URL"
+	// space separated, defaults to "openid email profile"
+	KeyOIDCScopes = "OIDC_SCOPES"
+	// names of the id token claims that are mapped to a user
+	KeyOIDCEmailClaim     = "OIDC_EMAIL_CLAIM"
+	KeyOIDCRollNoClaim    = "OIDC_ROLL_NO_CLAIM"
+	KeyOIDCFirstNameClaim = "OIDC_FIRST_NAME_CLAIM"
+	KeyOIDCLastNameClaim  = "OIDC_LAST_NAME_CLAIM"
+	// create a user when no user matches the claims, a sign up needs a roll no,
+	// so it defaults to true only when the roll no claim is configured
+	KeyOIDCCreateUsers = "OIDC_CREATE_USERS"
+
+	oidcRequestTimeout = 10 * time.Second
+)
+
+type OIDCConfig struct {
+	DiscoveryURL   string
+	ClientID       string
+	ClientSecret   string
+	RedirectURL    string
+	Scopes         []string
+	EmailClaim     string
+	RollNoClaim    string
+	FirstNameClaim string
+	LastNameClaim  string
+	CreateUsers    bool
+}
+
+// OIDCConfigFromEnv reads the provider settings,
+// ok is false when oidc login is not configured
+func OIDCConfigFromEnv() (config OIDCConfig, ok bool, err error) {
+	config = OIDCConfig{
+		DiscoveryURL:   os.Getenv(KeyOIDCDiscoveryURL),
+		ClientID:       os.Getenv(KeyOIDCClientID),
+		ClientSecret:   os.Getenv(KeyOIDCClientSecret),
+		RedirectURL:    os.Getenv(KeyOIDCRedirectURL),
+		Scopes:         strings.Fields(os.Getenv(KeyOIDCScopes)),
+		EmailClaim:     envOrDefault(KeyOIDCEmailClaim, "email"),
+		RollNoClaim:    os.Getenv(KeyOIDCRollNoClaim),
+		FirstNameClaim: envOrDefault(KeyOIDCFirstNameClaim, "given_name"),
+		LastNameClaim:  envOrDefault(KeyOIDCLastNameClaim, "family_name"),
+	}
+	config.CreateUsers = config.RollNoClaim != ""
+	if config.DiscoveryURL == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return config, false, nil
+	}
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if createUsers := os.Getenv(KeyOIDCCreateUsers); createUsers != "" {
+		config.CreateUsers, _ = strconv.ParseBool(createUsers)
+	}
+	// every created user would fail the sign up validation
+	if config.CreateUsers && config.RollNoClaim == "" {
+		return config, false, fmt.Errorf(
+			"%s requires %s to be set",
+			KeyOIDCCreateUsers,
+			KeyOIDCRollNoClaim,
+		)
+	}
+	return config, true, nil
+}
+
+func envOrDefault(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// OIDCProvider talks to the identity provider. The discovery document
+// and the signing keys are fetched lazily, so the server starts even if the provider is down
+type OIDCProvider struct {
+	Config     OIDCConfig
+	HTTPClient *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]*rsa.PublicKey
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJWKS struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+type oidcTokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+func NewOIDCProvider(config OIDCConfig) *OIDCProvider {
+	return &OIDCProvider{
+		Config:     config,
+		HTTPClient: &http.Client{Timeout: oidcRequestTimeout},
+	}
+}
+
+// AuthURL is the url of the provider's login page
+func (p *OIDCProvider) AuthURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientID)
+	query.Set("redirect_uri", p.Config.RedirectURL)
+	query.Set("scope", strings.Join(p.Config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return discovery.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades the authorization code for the verified claims of the id token
+func (p *OIDCProvider) Exchange(
+	ctx context.Context,
+	code string,
+	codeVerifier string,
+	nonce string,
+) (jwt.MapClaims, error) {
+	discovery, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// exchange the code at the token endpoint
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Config.RedirectURL)
+	form.Set("client_id", p.Config.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.Config.ClientSecret != "" {
+		form.Set("client_secret", p.Config.ClientSecret)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		discovery.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create oidc token request, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.HTTPClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot reach oidc token endpoint, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		// an invalid or reused code is rejected with 400
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		log.Errorf("oidc token endpoint responded with %d, %s", res.StatusCode, body)
+		return nil, fmt.Errorf(
+			"%w, identity provider rejected the login",
+			flux_errors.ErrInvalidRequestCredentials,
+		)
+	}
+
+	var tokenResponse oidcTokenResponse
+	if err = json.NewDecoder(res.Body).Decode(&tokenResponse); err != nil || tokenResponse.IDToken == "" {
+		err = fmt.Errorf("%w, invalid oidc token response, %v", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	return p.verifyIDToken(ctx, discovery, tokenResponse.IDToken, nonce)
+}
+
+// verifyIDToken checks the signature, issuer, audience, expiry and nonce of the id token
+func (p *OIDCProvider) verifyIDToken(
+	ctx context.Context,
+	discovery oidcDiscovery,
+	idToken string,
+	nonce string,
+) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (any, error) {
+		// only asymmetric keys published by the provider are accepted
+		if _, ok := t.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		return p.getKey(ctx, discovery, kid)
+	})
+	if err != nil {
+		log.Errorf("invalid oidc id token, %v", err)
+		return nil, fmt.Errorf("%w, invalid id token", flux_errors.ErrInvalidRequestCredentials)
+	}
+
+	if !claims.VerifyIssuer(discovery.Issuer, true) {
+		log.Errorf("oidc id token issued by %v, expected %s", claims["iss"], discovery.Issuer)
+		return nil, fmt.Errorf("%w, invalid id token issuer", flux_errors.ErrInvalidRequestCredentials)
+	}
+	if !claims.VerifyAudience(p.Config.ClientID, true) {
+		log.Errorf("oidc id token issued for %v", claims["aud"])
+		return nil, fmt.Errorf("%w, invalid id token audience", flux_errors.ErrInvalidRequestCredentials)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w, invalid id token nonce", flux_errors.ErrInvalidRequestCredentials)
+	}
+
+	return claims, nil
+}
+
+func (p *OIDCProvider) getDiscovery(ctx context.Context) (oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return *p.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	if err := p.getJson(ctx, p.Config.DiscoveryURL, &discovery); err != nil {
+		return oidcDiscovery{}, err
+	}
+	if discovery.Issuer == "" || discovery.AuthorizationEndpoint == "" ||
+		discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		err := fmt.Errorf("%w, incomplete oidc discovery document", flux_errors.ErrInternal)
+		log.Error(err)
+		return oidcDiscovery{}, err
+	}
+
+	p.discovery = &discovery
+	return discovery, nil
+}
+
+// getKey returns the signing key with the kid,
+// the keys are fetched again once when the provider rotated them
+func (p *OIDCProvider) getKey(
+	ctx context.Context,
+	discovery oidcDiscovery,
+	kid string,
+) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	var jwks oidcJWKS
+	if err := p.getJson(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := parseRSAKey(jwk.N, jwk.E)
+		if err != nil {
+			log.Errorf("skipping invalid oidc signing key %s, %v", jwk.Kid, err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	p.keys = keys
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("no oidc signing key found with kid %q", kid)
+}
+
+// lookupKey must be called with the lock held, a token without
+// kid can only be verified when the provider has a single key
+func (p *OIDCProvider) lookupKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *OIDCProvider) getJson(ctx context.Context, endpoint string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create request to %s, %w", flux_errors.ErrInternal, endpoint, err)
+		log.Error(err)
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := p.HTTPClient.Do(req)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot reach identity provider, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		err = fmt.Errorf("%w, identity provider responded with %d for %s", flux_errors.ErrInternal, res.StatusCode, endpoint)
+		log.Error(err)
+		return err
+	}
+	if err = json.NewDecoder(res.Body).Decode(v); err != nil {
+		err = fmt.Errorf("%w, invalid response from identity provider, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+func parseRSAKey(n string, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	exponent := new(big.Int).SetBytes(eBytes)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 || exponent.Int64() < 3 {
+		return nil, errors.New("invalid rsa exponent")
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(exponent.Int64()),
+	}, nil
+}
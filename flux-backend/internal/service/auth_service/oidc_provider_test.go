@@ -0,0 +1,408 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+const (
+	fakeClientID     = "flux"
+	fakeClientSecret = "secret"
+	fakeRedirectURL  = "https://flux.example/oidc/callback"
+	fakeCode         = "valid-code"
+	fakeNonce        = "nonce-1"
+)
+
+// fakeIdP is a local identity provider that issues id tokens for a single code
+type fakeIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+
+	mu            sync.Mutex
+	discoveryHits int
+	jwksHits      int
+	tokenForm     url.Values
+	// id token returned by the token endpoint, signed with key unless set
+	idToken string
+	claims  jwt.MapClaims
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	t.Helper()
+	idp := &fakeIdP{key: newTestKey(t), kid: "key-1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		idp.discoveryHits++
+		idp.mu.Unlock()
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		idp.jwksHits++
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kid": idp.kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(idp.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(idp.key.E)).Bytes()),
+			}},
+		})
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		idp.tokenForm = r.PostForm
+		if r.PostForm.Get("code") != fakeCode {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		idToken := idp.idToken
+		if idToken == "" {
+			idToken = signTestToken(t, idp.key, idp.kid, idp.claims)
+		}
+		json.NewEncoder(w).Encode(oidcTokenResponse{IDToken: idToken})
+	})
+
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	idp.claims = idp.validClaims()
+	return idp
+}
+
+func (idp *fakeIdP) validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"aud":            fakeClientID,
+		"sub":            "subject-1",
+		"exp":            time.Now().Add(time.Minute).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          fakeNonce,
+		"email":          "user@example.com",
+		"email_verified": true,
+	}
+}
+
+func (idp *fakeIdP) provider() *OIDCProvider {
+	return NewOIDCProvider(OIDCConfig{
+		DiscoveryURL: idp.server.URL + "/.well-known/openid-configuration",
+		ClientID:     fakeClientID,
+		ClientSecret: fakeClientSecret,
+		RedirectURL:  fakeRedirectURL,
+		Scopes:       []string{"openid", "email"},
+	})
+}
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("cannot generate rsa key, %v", err)
+	}
+	return key
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("cannot sign id token, %v", err)
+	}
+	return signed
+}
+
+func TestOIDCAuthURL(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := idp.provider()
+
+	authURL, err := provider.AuthURL(context.Background(), "state-1", fakeNonce, "challenge-1")
+	if err != nil {
+		t.Fatalf("auth url failed, %v", err)
+	}
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatalf("invalid auth url %s, %v", authURL, err)
+	}
+	if parsed.Path != "/authorize" {
+		t.Fatalf("expected the authorization endpoint, got %s", parsed.Path)
+	}
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             fakeClientID,
+		"redirect_uri":          fakeRedirectURL,
+		"scope":                 "openid email",
+		"state":                 "state-1",
+		"nonce":                 fakeNonce,
+		"code_challenge":        "challenge-1",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if got := parsed.Query().Get(name); got != value {
+			t.Errorf("%s: got %q, want %q", name, got, value)
+		}
+	}
+
+	// the discovery document is fetched only once
+	if _, err = provider.AuthURL(context.Background(), "state-2", fakeNonce, "challenge-2"); err != nil {
+		t.Fatalf("second auth url failed, %v", err)
+	}
+	if idp.discoveryHits != 1 {
+		t.Fatalf("expected a single discovery request, got %d", idp.discoveryHits)
+	}
+}
+
+func TestOIDCDiscoveryFailure(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := idp.provider()
+	provider.Config.DiscoveryURL = idp.server.URL + "/missing"
+
+	if _, err := provider.AuthURL(context.Background(), "state", fakeNonce, "challenge"); !errors.Is(err, flux_errors.ErrInternal) {
+		t.Fatalf("expected an internal error, got %v", err)
+	}
+}
+
+func TestOIDCExchange(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := idp.provider()
+
+	claims, err := provider.Exchange(context.Background(), fakeCode, "verifier-1", fakeNonce)
+	if err != nil {
+		t.Fatalf("exchange failed, %v", err)
+	}
+	if claims["email"] != "user@example.com" || claims["sub"] != "subject-1" {
+		t.Fatalf("unexpected claims %v", claims)
+	}
+
+	// the code is exchanged with the pkce verifier and the client credentials
+	want := map[string]string{
+		"grant_type":    "authorization_code",
+		"code":          fakeCode,
+		"code_verifier": "verifier-1",
+		"redirect_uri":  fakeRedirectURL,
+		"client_id":     fakeClientID,
+		"client_secret": fakeClientSecret,
+	}
+	for name, value := range want {
+		if got := idp.tokenForm.Get(name); got != value {
+			t.Errorf("%s: got %q, want %q", name, got, value)
+		}
+	}
+
+	// the signing keys are cached
+	if _, err = provider.Exchange(context.Background(), fakeCode, "verifier-2", fakeNonce); err != nil {
+		t.Fatalf("second exchange failed, %v", err)
+	}
+	if idp.jwksHits != 1 {
+		t.Fatalf("expected a single jwks request, got %d", idp.jwksHits)
+	}
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	idp := newFakeIdP(t)
+	provider := idp.provider()
+
+	if _, err := provider.Exchange(context.Background(), fakeCode, "verifier", fakeNonce); err != nil {
+		t.Fatalf("exchange failed, %v", err)
+	}
+
+	// the provider rotates its key, the new kid triggers a refetch
+	idp.mu.Lock()
+	idp.key = newTestKey(t)
+	idp.kid = "key-2"
+	idp.mu.Unlock()
+	if _, err := provider.Exchange(context.Background(), fakeCode, "verifier", fakeNonce); err != nil {
+		t.Fatalf("exchange after rotation failed, %v", err)
+	}
+	if idp.jwksHits != 2 {
+		t.Fatalf("expected the keys to be fetched again, got %d requests", idp.jwksHits)
+	}
+}
+
+func TestOIDCExchangeRejections(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		nonce  string
+		tamper func(t *testing.T, idp *fakeIdP)
+	}{
+		{
+			name: "rejected code",
+			code: "reused-code",
+		},
+		{
+			name: "signed with another key",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				idp.idToken = signTestToken(t, newTestKey(t), idp.kid, idp.claims)
+			},
+		},
+		{
+			name: "unknown key",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				idp.idToken = signTestToken(t, idp.key, "key-unknown", idp.claims)
+			},
+		},
+		{
+			name: "symmetric signature",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				token := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.claims)
+				token.Header["kid"] = idp.kid
+				idp.idToken, _ = token.SignedString([]byte(fakeClientSecret))
+			},
+		},
+		{
+			name: "wrong issuer",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				idp.claims["iss"] = "https://attacker.example"
+			},
+		},
+		{
+			name: "wrong audience",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				idp.claims["aud"] = "another-client"
+			},
+		},
+		{
+			name: "expired",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				idp.claims["exp"] = time.Now().Add(-time.Minute).Unix()
+			},
+		},
+		{
+			name:  "wrong nonce",
+			nonce: "nonce-2",
+		},
+		{
+			name: "missing nonce",
+			tamper: func(t *testing.T, idp *fakeIdP) {
+				delete(idp.claims, "nonce")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newFakeIdP(t)
+			if tt.tamper != nil {
+				tt.tamper(t, idp)
+			}
+			code := fakeCode
+			if tt.code != "" {
+				code = tt.code
+			}
+			nonce := fakeNonce
+			if tt.nonce != "" {
+				nonce = tt.nonce
+			}
+
+			claims, err := idp.provider().Exchange(context.Background(), code, "verifier", nonce)
+			if !errors.Is(err, flux_errors.ErrInvalidRequestCredentials) {
+				t.Fatalf("expected the login to be rejected, got claims %v and error %v", claims, err)
+			}
+		})
+	}
+}
+
+func TestOIDCConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name        string
+		rollNoClaim string
+		createUsers string
+		wantCreate  bool
+		wantErr     bool
+	}{
+		{name: "no roll no claim", wantCreate: false},
+		{name: "roll no claim", rollNoClaim: "roll_no", wantCreate: true},
+		{name: "creation turned off", rollNoClaim: "roll_no", createUsers: "false", wantCreate: false},
+		{name: "creation without roll no claim", createUsers: "true", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(KeyOIDCDiscoveryURL, "https://idp.example/.well-known/openid-configuration")
+			t.Setenv(KeyOIDCClientID, fakeClientID)
+			t.Setenv(KeyOIDCRedirectURL, fakeRedirectURL)
+			t.Setenv(KeyOIDCRollNoClaim, tt.rollNoClaim)
+			t.Setenv(KeyOIDCCreateUsers, tt.createUsers)
+
+			config, ok, err := OIDCConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected a configuration error")
+				}
+				return
+			}
+			if err != nil || !ok {
+				t.Fatalf("expected a valid configuration, got ok %v and error %v", ok, err)
+			}
+			if config.CreateUsers != tt.wantCreate {
+				t.Fatalf("create users is %v, want %v", config.CreateUsers, tt.wantCreate)
+			}
+		})
+	}
+}
+
+func TestOIDCIdentity(t *testing.T) {
+	config := OIDCConfig{EmailClaim: "email", RollNoClaim: "roll_no"}
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		wantRollNo string
+		wantEmail  string
+	}{
+		{
+			name:       "verified email",
+			claims:     jwt.MapClaims{"email": "user@example.com", "email_verified": true, "roll_no": float64(12345678)},
+			wantRollNo: "12345678",
+			wantEmail:  "user@example.com",
+		},
+		{
+			name:   "unverified email",
+			claims: jwt.MapClaims{"email": "user@example.com", "email_verified": false},
+		},
+		{
+			name:   "missing email_verified",
+			claims: jwt.MapClaims{"email": "user@example.com"},
+		},
+		{
+			name:   "email_verified is not a bool",
+			claims: jwt.MapClaims{"email": "user@example.com", "email_verified": "true"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollNo, userEmail := oidcIdentity(config, tt.claims)
+			if rollNo != tt.wantRollNo || userEmail != tt.wantEmail {
+				t.Fatalf("got %q %q, want %q %q", rollNo, userEmail, tt.wantRollNo, tt.wantEmail)
+			}
+		})
+	}
+}
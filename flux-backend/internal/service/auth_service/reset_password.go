@@ -60,12 +60,13 @@ func (a *AuthService) ResetPassword(
 	}
 
 	// verify token
-	if err = a.validateVerificationToken(
+	dbToken, err := a.validateVerificationToken(
 		ctx,
 		token,
 		user.Email,
 		email.PurposeEmailPasswordReset,
-	); err != nil {
+	)
+	if err != nil {
 		if errors.Is(err, flux_errors.ErrCorruptedVerification) {
 			resetLogger.Error(err)
 		}
@@ -73,13 +74,7 @@ func (a *AuthService) ResetPassword(
 	}
 
 	// validate password
-	if err = service.ValidateInput(
-		struct {
-			Password string `json:"password" validate:"required,min=7,max=74"`
-		}{
-			Password: password,
-		},
-	); err != nil {
+	if err = validatePassword(password); err != nil {
 		return err
 	}
 
@@ -89,8 +84,25 @@ func (a *AuthService) ResetPassword(
 		return err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	// the token is used up together with the reset
+	if _, err = a.consumeVerificationToken(ctx, qtx, dbToken); err != nil {
+		return err
+	}
+
 	// insert into db
-	if err = a.DB.ResetPassword(ctx, database.ResetPasswordParams{
+	if err = qtx.ResetPassword(ctx, database.ResetPasswordParams{
 		PasswordHash: passwordHash,
 		UserName:     user.UserName,
 	}); err != nil {
@@ -99,13 +111,11 @@ func (a *AuthService) ResetPassword(
 		return err
 	}
 
-	// invalidate token
-	a.invalidateVerificationToken(
-		ctx,
-		token,
-		user.Email,
-		email.PurposeEmailPasswordReset,
-	)
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		resetLogger.Errorf("cannot commit password reset, %v", err)
+		return fmt.Errorf("%w, unable to reset password", flux_errors.ErrInternal)
+	}
 
 	return nil
 }
@@ -0,0 +1,368 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+const (
+	AccessTokenExpiryMinutes = 15
+	// refresh tokens are long lived, so they are longer than verification tokens
+	refreshTokenBytes = 32
+	// a concurrent refresh may still present the token replaced this long ago
+	refreshReuseGracePeriod = 30 * time.Second
+)
+
+// SessionTokens are handed over to the client after login or refresh.
+// The access token authenticates every request and the refresh token
+// (formatted as <session_id>.<secret>) is exchanged for a new pair
+type SessionTokens struct {
+	AccessToken   string
+	AccessExpiry  time.Time
+	RefreshToken  string
+	RefreshExpiry time.Time
+}
+
+// createSession starts a session lasting a day (or a month) for the user,
+// twoFactorVerified tells if the login answered a 2fa challenge
+func (a *AuthService) createSession(
+	ctx context.Context,
+	user database.User,
+	rememberForMonth bool,
+	twoFactorVerified bool,
+) (SessionTokens, error) {
+	var duration = time.Hour * 24
+	if rememberForMonth {
+		duration *= 30
+	}
+	sessionExpiry := time.Now().Add(duration)
+
+	// create a refresh secret
+	secret, err := generateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+
+	// store the session
+	session, err := a.DB.CreateSession(
+		ctx,
+		database.CreateSessionParams{
+			UserID:             user.ID,
+			HashedRefreshToken: hashRefreshSecret(secret),
+			ExpiresAt:          sessionExpiry,
+			TwoFactorVerified:  twoFactorVerified,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot create session for user %s, %w",
+			flux_errors.ErrInternal,
+			user.UserName,
+			err,
+		)
+		log.Error(err)
+		return SessionTokens{}, err
+	}
+
+	return a.issueSessionTokens(user, session, secret)
+}
+
+// RefreshSession exchanges a refresh token for a new pair of tokens.
+// A refresh token can be used only once, presenting an already used
+// one means it was stolen, so the whole session is revoked. The token
+// replaced by the latest rotation is only refused for a short while,
+// it is most likely a concurrent refresh that lost the race
+func (a *AuthService) RefreshSession(
+	ctx context.Context,
+	refreshToken string,
+) (SessionTokens, error) {
+	// get the session
+	sessionID, secret, err := parseRefreshToken(refreshToken)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+	session, err := a.getActiveSession(ctx, sessionID)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+
+	// check the secret
+	oldHash := hashRefreshSecret(secret)
+	if subtle.ConstantTimeCompare([]byte(oldHash), []byte(session.HashedRefreshToken)) != 1 {
+		if isJustRotatedToken(session, oldHash) {
+			return SessionTokens{}, fmt.Errorf(
+				"%w, refresh token was just rotated, use the latest one",
+				flux_errors.ErrUnAuthorized,
+			)
+		}
+		log.Warnf("reuse of a rotated refresh token of session %v, revoking it", session.ID)
+		if _, err = a.DB.RevokeSession(ctx, session.ID); err != nil {
+			log.Errorf("cannot revoke session %v, %v", session.ID, err)
+		}
+		return SessionTokens{}, fmt.Errorf(
+			"%w, refresh token was already used, please login again",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// sessions started without 2fa end once the user holds a role that requires it
+	roles, err := a.UserConfig.FetchUserRoles(ctx, session.UserID)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+	if requiresTwoFactor(roles) && !session.TwoFactorVerified {
+		log.Warnf("session %v was started without 2fa, revoking it", session.ID)
+		if _, err = a.DB.RevokeSession(ctx, session.ID); err != nil {
+			log.Errorf("cannot revoke session %v, %v", session.ID, err)
+		}
+		return SessionTokens{}, fmt.Errorf(
+			"%w, your role requires 2fa, please login again",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// rotate the secret
+	newSecret, err := generateRandomToken(refreshTokenBytes)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+	n, err := a.DB.RotateSessionToken(
+		ctx,
+		database.RotateSessionTokenParams{
+			ID:                    session.ID,
+			OldHashedRefreshToken: oldHash,
+			NewHashedRefreshToken: hashRefreshSecret(newSecret),
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot rotate refresh token of session %v, %w",
+			flux_errors.ErrInternal,
+			session.ID,
+			err,
+		)
+		log.Error(err)
+		return SessionTokens{}, err
+	}
+	if n == 0 {
+		// another refresh with the same token won the race
+		return SessionTokens{}, fmt.Errorf(
+			"%w, refresh token was already used",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// get the latest user details for the claims
+	user, err := a.DB.GetUserById(ctx, session.UserID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user %v of session %v, %w",
+			flux_errors.ErrInternal,
+			session.UserID,
+			session.ID,
+			err,
+		)
+		log.Error(err)
+		return SessionTokens{}, err
+	}
+
+	return a.issueSessionTokens(user, session, newSecret)
+}
+
+// isJustRotatedToken tells if hash is of the token replaced by
+// the latest rotation of the session, within the grace period
+func isJustRotatedToken(session database.Session, hash string) bool {
+	if session.PreviousHashedRefreshToken == nil {
+		return false
+	}
+	if time.Since(session.LastRefreshedAt) > refreshReuseGracePeriod {
+		return false
+	}
+	return subtle.ConstantTimeCompare(
+		[]byte(hash),
+		[]byte(*session.PreviousHashedRefreshToken),
+	) == 1
+}
+
+// Logout revokes the session of the refresh token
+func (a *AuthService) Logout(
+	ctx context.Context,
+	refreshToken string,
+) error {
+	// get the session
+	sessionID, secret, err := parseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+	session, err := a.getActiveSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// only the holder of the latest token can logout
+	if subtle.ConstantTimeCompare(
+		[]byte(hashRefreshSecret(secret)),
+		[]byte(session.HashedRefreshToken),
+	) != 1 {
+		return fmt.Errorf(
+			"%w, invalid refresh token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// revoke
+	_, err = a.DB.RevokeSession(ctx, session.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot revoke session %v, %w",
+			flux_errors.ErrInternal,
+			session.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("session %v of user %v logged out", session.ID, session.UserID)
+	return nil
+}
+
+// LogoutAllSessions revokes every session of the user including the current one
+func (a *AuthService) LogoutAllSessions(ctx context.Context) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// revoke
+	n, err := a.DB.RevokeUserSessions(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot revoke sessions of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("user %s logged out of %v sessions", claims.UserName, n)
+	return nil
+}
+
+// IsSessionActive reports if the session is neither revoked nor expired
+func (a *AuthService) IsSessionActive(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) (bool, error) {
+	active, err := a.DB.IsSessionActive(ctx, sessionID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check if session %v is active, %w",
+			flux_errors.ErrInternal,
+			sessionID,
+			err,
+		)
+		log.Error(err)
+		return false, err
+	}
+	return active, nil
+}
+
+func (a *AuthService) getActiveSession(
+	ctx context.Context,
+	sessionID uuid.UUID,
+) (database.Session, error) {
+	session, err := a.DB.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Session{}, fmt.Errorf(
+				"%w, invalid refresh token",
+				flux_errors.ErrUnAuthorized,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch session %v, %w",
+			flux_errors.ErrInternal,
+			sessionID,
+			err,
+		)
+		log.Error(err)
+		return database.Session{}, err
+	}
+
+	if session.RevokedAt != nil || time.Now().After(session.ExpiresAt) {
+		return database.Session{}, fmt.Errorf(
+			"%w, session has expired, please login again",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	return session, nil
+}
+
+// issueSessionTokens creates an access token for the session, it never outlives the session
+func (a *AuthService) issueSessionTokens(
+	user database.User,
+	session database.Session,
+	secret string,
+) (SessionTokens, error) {
+	accessExpiry := time.Now().Add(AccessTokenExpiryMinutes * time.Minute)
+	if accessExpiry.After(session.ExpiresAt) {
+		accessExpiry = session.ExpiresAt
+	}
+
+	// claims store the user data to avoid repeated logins via jwt
+	claims := dbUserToUserCredClaims(accessExpiry, user, session.ID)
+	accessToken, err := GenerateJWT(claims)
+	if err != nil {
+		return SessionTokens{}, err
+	}
+
+	return SessionTokens{
+		AccessToken:   accessToken,
+		AccessExpiry:  accessExpiry,
+		RefreshToken:  fmt.Sprintf("%s.%s", session.ID, secret),
+		RefreshExpiry: session.ExpiresAt,
+	}, nil
+}
+
+func parseRefreshToken(refreshToken string) (uuid.UUID, string, error) {
+	sessionIDStr, secret, found := strings.Cut(refreshToken, ".")
+	if !found || secret == "" {
+		return uuid.Nil, "", fmt.Errorf(
+			"%w, malformed refresh token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+	sessionID, err := uuid.Parse(sessionIDStr)
+	if err != nil {
+		return uuid.Nil, "", fmt.Errorf(
+			"%w, malformed refresh token",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+	return sessionID, secret, nil
+}
+
+// refresh secrets are long and random, so a fast hash is enough
+// and keeps every refresh cheap unlike bcrypt
+func hashRefreshSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
@@ -21,12 +22,13 @@ func (a *AuthService) SignUp(
 	verificationToken string,
 ) (userResponse UserRegestrationResponse, err error) {
 	// verify the token
-	if err = a.validateVerificationToken(
+	dbToken, err := a.validateVerificationToken(
 		ctx,
 		verificationToken,
 		userRegestration.UserMail,
 		email.PurposeEmailSignUp,
-	); err != nil {
+	)
+	if err != nil {
 		if errors.Is(err, flux_errors.ErrCorruptedVerification) {
 			log.WithFields(log.Fields{
 				"roll_no": userRegestration.RollNo,
@@ -48,19 +50,30 @@ func (a *AuthService) SignUp(
 		return
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// the token is used up together with the sign up
+	if _, err = a.consumeVerificationToken(ctx, a.DB.WithTx(tx), dbToken); err != nil {
+		return
+	}
+
 	// Create the user in the database and handle DB-specific errors.
-	dbUser, err := a.createUserInDB(ctx, userRegestration, passwordHash)
+	dbUser, err := a.createUserInDB(ctx, tx, userRegestration, passwordHash)
 	if err != nil {
 		return
 	}
 
-	// invalidate verification token
-	if err = a.invalidateVerificationToken(
-		ctx,
-		verificationToken,
-		userRegestration.UserMail,
-		email.PurposeEmailSignUp,
-	); err != nil {
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("%w, cannot commit sign up, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
 		return
 	}
 
@@ -77,8 +90,11 @@ func (a *AuthService) SignUp(
 
 // --- Helper Functions Below ---
 // createUserInDB handles the database interaction and error-specific logic.
+// Every attempt runs in a savepoint of tx, a duplicate user name must not
+// abort the whole transaction
 func (a *AuthService) createUserInDB(
 	ctx context.Context,
+	tx pgx.Tx,
 	userRegestration UserRegestration,
 	passwordHash string,
 ) (database.User, error) {
@@ -105,7 +121,12 @@ func (a *AuthService) createUserInDB(
 			if err != nil {
 				return database.User{}, err
 			}
-			user, dbErr := a.DB.CreateUser(
+			savepoint, err := tx.Begin(ctx)
+			if err != nil {
+				attemptLogger.Errorf("cannot create savepoint, %v", err)
+				return database.User{}, errors.Join(flux_errors.ErrInternal, err)
+			}
+			user, dbErr := a.DB.WithTx(savepoint).CreateUser(
 				ctx,
 				database.CreateUserParams{
 					UserName:     userName,
@@ -117,6 +138,7 @@ func (a *AuthService) createUserInDB(
 				},
 			)
 			if dbErr != nil {
+				savepoint.Rollback(ctx)
 				var pgErr *pgconn.PgError
 				if errors.As(dbErr, &pgErr) && pgErr.Code == flux_errors.CodeUniqueConstraintViolation {
 					if strings.Contains(pgErr.ConstraintName, "user_name") {
@@ -128,6 +150,10 @@ func (a *AuthService) createUserInDB(
 				attemptLogger.Errorf("failed to insert user into database: %v", dbErr)
 				return database.User{}, errors.Join(flux_errors.ErrInternal, dbErr)
 			}
+			if err = savepoint.Commit(ctx); err != nil {
+				attemptLogger.Errorf("cannot release savepoint, %v", err)
+				return database.User{}, errors.Join(flux_errors.ErrInternal, err)
+			}
 			return user, nil
 		}
 	}
@@ -0,0 +1,47 @@
+package auth_service
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultTokenJanitorInterval = 10 * time.Minute
+)
+
+// StartTokenJanitor periodically deletes the verification tokens and login challenges
+// that have expired, they are otherwise only removed when someone tries to use them
+func (a *AuthService) StartTokenJanitor(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			a.deleteExpiredTokens(context.Background())
+			a.deleteExpiredLoginChallenges(context.Background())
+		}
+	}()
+}
+
+func (a *AuthService) deleteExpiredTokens(ctx context.Context) {
+	count, err := a.DB.DeleteExpiredTokens(ctx)
+	if err != nil {
+		log.Errorf("cannot delete expired tokens, %v", err)
+		return
+	}
+	if count > 0 {
+		log.Infof("deleted %d expired tokens", count)
+	}
+}
+
+func (a *AuthService) deleteExpiredLoginChallenges(ctx context.Context) {
+	count, err := a.DB.DeleteExpiredLoginChallenges(ctx)
+	if err != nil {
+		log.Errorf("cannot delete expired login challenges, %v", err)
+		return
+	}
+	if count > 0 {
+		log.Infof("deleted %d expired login challenges", count)
+	}
+}
@@ -0,0 +1,120 @@
+package auth_service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+// totp codes follow rfc 6238 with the defaults every authenticator app supports
+const (
+	totpIssuer      = "Flux"
+	totpDigits      = 6
+	totpPeriod      = 30
+	totpSecretBytes = 20
+	// codes of the previous and next step are accepted for clock drift
+	totpSkew = 1
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 10
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func generateTotpSecret() (string, error) {
+	secret := make([]byte, totpSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		log.Errorf("failed to generate totp secret: %v", err)
+		return "", errors.Join(flux_errors.ErrInternal, err)
+	}
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// totpURI is shown as a qr code by the frontend and scanned by the authenticator app
+func totpURI(secret string, accountName string) string {
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", totpIssuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(totpIssuer + ":" + accountName)
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// matchTotp returns the time step of the code if it is valid at the given time
+func matchTotp(secret string, code string, now time.Time) (int64, bool) {
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		log.Errorf("invalid totp secret, %v", err)
+		return 0, false
+	}
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := now.Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+func totpCode(key []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, value%uint32(math.Pow10(totpDigits)))
+}
+
+// generateRecoveryCodes returns the codes shown to the user once and their hashes
+func generateRecoveryCodes() (codes []string, hashes []string, err error) {
+	for range recoveryCodeCount {
+		raw := make([]byte, recoveryCodeBytes)
+		if _, err = rand.Read(raw); err != nil {
+			log.Errorf("failed to generate recovery code: %v", err)
+			return nil, nil, errors.Join(flux_errors.ErrInternal, err)
+		}
+		code := strings.ToLower(totpEncoding.EncodeToString(raw))
+		codes = append(codes, formatRecoveryCode(code))
+		hashes = append(hashes, hashRefreshSecret(code))
+	}
+	return
+}
+
+// recovery codes are shown in groups of four characters
+func formatRecoveryCode(code string) string {
+	groups := make([]string, 0, len(code)/4+1)
+	for len(code) > 4 {
+		groups = append(groups, code[:4])
+		code = code[4:]
+	}
+	groups = append(groups, code)
+	return strings.Join(groups, "-")
+}
+
+// normalizeSecondFactorCode strips the separators users tend to type
+func normalizeSecondFactorCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer(" ", "", "-", "").Replace(code)
+}
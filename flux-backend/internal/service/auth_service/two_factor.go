@@ -0,0 +1,643 @@
+package auth_service
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+const (
+	loginChallengeExpiry      = 5 * time.Minute
+	maxLoginChallengeAttempts = 5
+	loginChallengeBytes       = 32
+)
+
+// these roles can see locked problems and edit contests, so 2fa is mandatory for them
+var twoFactorRequiredRoles = []user_service.UserRole{
+	user_service.RoleManager,
+	user_service.RoleHC,
+}
+
+// startLogin creates a session for the authenticated user, or a 2fa challenge
+// when the user has enabled 2fa or holds a role that requires it
+func (a *AuthService) startLogin(
+	ctx context.Context,
+	user database.User,
+	rememberForMonth bool,
+) (userLoginResponse UserLoginResponse, tokens SessionTokens, err error) {
+	// fetch user roles to store in claims
+	roles, err := a.UserConfig.FetchUserRoles(ctx, user.ID)
+	if err != nil {
+		return
+	}
+
+	totp, found, err := a.getUserTotp(ctx, user.ID)
+	if err != nil {
+		return
+	}
+	enabled := found && totp.ConfirmedAt != nil
+
+	if enabled || requiresTwoFactor(roles) {
+		var challenge TwoFactorChallenge
+		challenge, err = a.createLoginChallenge(ctx, user, rememberForMonth, !enabled)
+		if err != nil {
+			return
+		}
+		userLoginResponse = UserLoginResponse{
+			UserName:  user.UserName,
+			TwoFactor: &challenge,
+		}
+		return
+	}
+
+	// start a new session
+	tokens, err = a.createSession(ctx, user, rememberForMonth, false)
+	if err != nil {
+		return
+	}
+
+	userLoginResponse = dbUserToLoginRes(roles, user)
+	return
+}
+
+// CompleteTwoFactorLogin answers the challenge of a login with a totp or recovery code.
+// A user who had to enroll confirms the secret with the code and receives recovery codes
+func (a *AuthService) CompleteTwoFactorLogin(
+	ctx context.Context,
+	challengeToken string,
+	code string,
+) (userLoginResponse UserLoginResponse, tokens SessionTokens, err error) {
+	challenge, err := a.getLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return
+	}
+	user, err := a.getUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return
+	}
+
+	totp, found, err := a.getUserTotp(ctx, user.ID)
+	if err != nil {
+		return
+	}
+	if !found {
+		err = fmt.Errorf("%w, 2fa is not set up yet, please enroll first", flux_errors.ErrInvalidRequest)
+		return
+	}
+
+	// check the code, a secret still being enrolled is confirmed by it
+	var recoveryCodes []string
+	if totp.ConfirmedAt == nil {
+		recoveryCodes, err = a.confirmTotp(ctx, user, totp, code)
+	} else {
+		err = a.checkSecondFactor(ctx, user, totp, code)
+	}
+	if err != nil {
+		if errors.Is(err, flux_errors.ErrInvalidUserCredentials) {
+			a.failLoginChallenge(ctx, challenge)
+		}
+		return
+	}
+
+	// the challenge is answered
+	if err = a.DB.DeleteLoginChallenge(ctx, challenge.ID); err != nil {
+		err = fmt.Errorf("%w, cannot delete login challenge, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return
+	}
+
+	roles, err := a.UserConfig.FetchUserRoles(ctx, user.ID)
+	if err != nil {
+		return
+	}
+
+	// start a new session
+	tokens, err = a.createSession(ctx, user, challenge.RememberForMonth, true)
+	if err != nil {
+		return
+	}
+
+	userLoginResponse = dbUserToLoginRes(roles, user)
+	userLoginResponse.RecoveryCodes = recoveryCodes
+	return
+}
+
+// EnrollTwoFactorForChallenge lets a user who must use 2fa enroll during the login
+func (a *AuthService) EnrollTwoFactorForChallenge(
+	ctx context.Context,
+	challengeToken string,
+) (TwoFactorEnrollment, error) {
+	challenge, err := a.getLoginChallenge(ctx, challengeToken)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	user, err := a.getUserByID(ctx, challenge.UserID)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	return a.enrollTotp(ctx, user)
+}
+
+func (a *AuthService) GetTwoFactorStatus(ctx context.Context) (TwoFactorStatus, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	roles, err := a.UserConfig.FetchUserRoles(ctx, claims.UserId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+	totp, found, err := a.getUserTotp(ctx, claims.UserId)
+	if err != nil {
+		return TwoFactorStatus{}, err
+	}
+
+	status := TwoFactorStatus{
+		Enabled:  found && totp.ConfirmedAt != nil,
+		Required: requiresTwoFactor(roles),
+	}
+	if status.Enabled {
+		status.RecoveryCodesLeft, err = a.DB.CountUnusedRecoveryCodes(ctx, claims.UserId)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot count recovery codes of %s, %w",
+				flux_errors.ErrInternal,
+				claims.UserName,
+				err,
+			)
+			log.Error(err)
+			return TwoFactorStatus{}, err
+		}
+	}
+	return status, nil
+}
+
+// EnrollTwoFactor creates a new secret, 2fa is enabled once it is confirmed with a code
+func (a *AuthService) EnrollTwoFactor(ctx context.Context) (TwoFactorEnrollment, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	user, err := a.getUserByID(ctx, claims.UserId)
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+	return a.enrollTotp(ctx, user)
+}
+
+// ConfirmTwoFactor enables 2fa and returns the recovery codes
+func (a *AuthService) ConfirmTwoFactor(ctx context.Context, code string) ([]string, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := a.getUserByID(ctx, claims.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	totp, found, err := a.getUserTotp(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%w, please enroll 2fa first", flux_errors.ErrInvalidRequest)
+	}
+	if totp.ConfirmedAt != nil {
+		return nil, fmt.Errorf("%w, 2fa is already enabled", flux_errors.ErrInvalidRequest)
+	}
+
+	return a.confirmTotp(ctx, user, totp, code)
+}
+
+// DisableTwoFactor removes the secret and the recovery codes,
+// users whose role requires 2fa cannot disable it
+func (a *AuthService) DisableTwoFactor(ctx context.Context, code string) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	roles, err := a.UserConfig.FetchUserRoles(ctx, claims.UserId)
+	if err != nil {
+		return err
+	}
+	if requiresTwoFactor(roles) {
+		return fmt.Errorf("%w, 2fa is mandatory for your roles", flux_errors.ErrUnAuthorized)
+	}
+
+	user, totp, err := a.getEnabledTotp(ctx, claims.UserId)
+	if err != nil {
+		return err
+	}
+	if err = a.checkSecondFactor(ctx, user, totp, code); err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	if _, err = qtx.DeleteUserTotp(ctx, user.ID); err != nil {
+		err = fmt.Errorf("%w, cannot delete totp of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return err
+	}
+	if err = qtx.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		err = fmt.Errorf("%w, cannot delete recovery codes of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("%w, cannot commit transaction after disabling 2fa, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("user %s disabled 2fa", user.UserName)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes, the old ones stop working
+func (a *AuthService) RegenerateRecoveryCodes(ctx context.Context, code string) ([]string, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	user, totp, err := a.getEnabledTotp(ctx, claims.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if err = a.checkSecondFactor(ctx, user, totp, code); err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	codes, err := replaceRecoveryCodes(ctx, qtx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("%w, cannot commit transaction after replacing recovery codes, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	log.Infof("user %s regenerated recovery codes", user.UserName)
+	return codes, nil
+}
+
+func (a *AuthService) enrollTotp(ctx context.Context, user database.User) (TwoFactorEnrollment, error) {
+	secret, err := generateTotpSecret()
+	if err != nil {
+		return TwoFactorEnrollment{}, err
+	}
+
+	// a pending enrollment is replaced, a confirmed one is not
+	_, err = a.DB.UpsertUserTotp(ctx, database.UpsertUserTotpParams{
+		UserID: user.ID,
+		Secret: secret,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TwoFactorEnrollment{}, fmt.Errorf("%w, 2fa is already enabled", flux_errors.ErrInvalidRequest)
+		}
+		err = fmt.Errorf("%w, cannot store totp secret of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return TwoFactorEnrollment{}, err
+	}
+
+	return TwoFactorEnrollment{
+		Secret:     secret,
+		OtpAuthURI: totpURI(secret, user.UserName),
+	}, nil
+}
+
+// confirmTotp enables the pending secret if the code matches and creates the recovery codes
+func (a *AuthService) confirmTotp(
+	ctx context.Context,
+	user database.User,
+	totp database.UserTotp,
+	code string,
+) ([]string, error) {
+	// a stolen password or session must not be able to guess the code
+	loginAccount := loginAccountKey(user)
+	if _, err := a.Limiter.AllowAccount(ctx, loginAccount); err != nil {
+		return nil, err
+	}
+
+	step, ok := matchTotp(totp.Secret, normalizeSecondFactorCode(code), time.Now())
+	if !ok {
+		if err := a.Limiter.RecordLoginFailure(ctx, loginAccount); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w, invalid 2fa code", flux_errors.ErrInvalidUserCredentials)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := a.DB.WithTx(tx)
+
+	if _, err = qtx.UseTotpStep(ctx, database.UseTotpStepParams{
+		Step:   step,
+		UserID: user.ID,
+	}); err != nil {
+		err = fmt.Errorf("%w, cannot store used totp step of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return nil, err
+	}
+	n, err := qtx.ConfirmUserTotp(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot confirm totp of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return nil, err
+	}
+	if n == 0 {
+		// a concurrent request confirmed it
+		return nil, fmt.Errorf("%w, 2fa is already enabled", flux_errors.ErrInvalidRequest)
+	}
+	codes, err := replaceRecoveryCodes(ctx, qtx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf("%w, cannot commit transaction after confirming 2fa, %w", flux_errors.ErrInternal, err)
+		log.Error(err)
+		return nil, err
+	}
+
+	a.Limiter.ResetLoginFailures(ctx, loginAccount)
+	log.Infof("user %s enabled 2fa", user.UserName)
+	return codes, nil
+}
+
+// checkSecondFactor accepts a totp code of the enabled secret or an unused recovery code.
+// Failures count towards the lockout of the account like wrong passwords
+func (a *AuthService) checkSecondFactor(
+	ctx context.Context,
+	user database.User,
+	totp database.UserTotp,
+	code string,
+) error {
+	loginAccount := loginAccountKey(user)
+	if _, err := a.Limiter.AllowAccount(ctx, loginAccount); err != nil {
+		return err
+	}
+
+	code = normalizeSecondFactorCode(code)
+	var n int64
+	var err error
+	if step, ok := matchTotp(totp.Secret, code, time.Now()); ok {
+		// a code that was already used is rejected
+		n, err = a.DB.UseTotpStep(ctx, database.UseTotpStepParams{
+			Step:   step,
+			UserID: user.ID,
+		})
+	} else {
+		n, err = a.DB.UseRecoveryCode(ctx, database.UseRecoveryCodeParams{
+			UserID:     user.ID,
+			HashedCode: hashRefreshSecret(code),
+		})
+		if err == nil && n > 0 {
+			log.Warnf("user %s used a recovery code", user.UserName)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("%w, cannot check 2fa code of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return err
+	}
+
+	if n == 0 {
+		if err = a.Limiter.RecordLoginFailure(ctx, loginAccount); err != nil {
+			return err
+		}
+		return fmt.Errorf("%w, invalid 2fa code", flux_errors.ErrInvalidUserCredentials)
+	}
+
+	a.Limiter.ResetLoginFailures(ctx, loginAccount)
+	return nil
+}
+
+func replaceRecoveryCodes(
+	ctx context.Context,
+	qtx *database.Queries,
+	user database.User,
+) ([]string, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err = qtx.DeleteRecoveryCodes(ctx, user.ID); err != nil {
+		err = fmt.Errorf("%w, cannot delete recovery codes of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return nil, err
+	}
+	if err = qtx.CreateRecoveryCodes(ctx, database.CreateRecoveryCodesParams{
+		UserID:      user.ID,
+		HashedCodes: hashes,
+	}); err != nil {
+		err = fmt.Errorf("%w, cannot create recovery codes of %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+func (a *AuthService) createLoginChallenge(
+	ctx context.Context,
+	user database.User,
+	rememberForMonth bool,
+	setupRequired bool,
+) (TwoFactorChallenge, error) {
+	secret, err := generateRandomToken(loginChallengeBytes)
+	if err != nil {
+		return TwoFactorChallenge{}, err
+	}
+
+	challenge, err := a.DB.CreateLoginChallenge(ctx, database.CreateLoginChallengeParams{
+		UserID:           user.ID,
+		HashedToken:      hashRefreshSecret(secret),
+		RememberForMonth: rememberForMonth,
+		ExpiresAt:        time.Now().Add(loginChallengeExpiry),
+	})
+	if err != nil {
+		err = fmt.Errorf("%w, cannot create login challenge for %s, %w", flux_errors.ErrInternal, user.UserName, err)
+		log.Error(err)
+		return TwoFactorChallenge{}, err
+	}
+
+	return TwoFactorChallenge{
+		ChallengeToken: fmt.Sprintf("%s.%s", challenge.ID, secret),
+		SetupRequired:  setupRequired,
+		ExpiresAt:      challenge.ExpiresAt,
+	}, nil
+}
+
+// getLoginChallenge returns the pending challenge of the token,
+// expired or exhausted challenges are deleted
+func (a *AuthService) getLoginChallenge(
+	ctx context.Context,
+	challengeToken string,
+) (database.LoginChallenge, error) {
+	invalidErr := fmt.Errorf("%w, invalid or expired login challenge, please login again", flux_errors.ErrUnAuthorized)
+
+	challengeIDStr, secret, found := strings.Cut(challengeToken, ".")
+	if !found || secret == "" {
+		return database.LoginChallenge{}, invalidErr
+	}
+	challengeID, err := uuid.Parse(challengeIDStr)
+	if err != nil {
+		return database.LoginChallenge{}, invalidErr
+	}
+
+	challenge, err := a.DB.GetLoginChallenge(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.LoginChallenge{}, invalidErr
+		}
+		err = fmt.Errorf("%w, cannot fetch login challenge %v, %w", flux_errors.ErrInternal, challengeID, err)
+		log.Error(err)
+		return database.LoginChallenge{}, err
+	}
+
+	if subtle.ConstantTimeCompare(
+		[]byte(hashRefreshSecret(secret)),
+		[]byte(challenge.HashedToken),
+	) != 1 {
+		return database.LoginChallenge{}, invalidErr
+	}
+
+	if time.Now().After(challenge.ExpiresAt) || challenge.Attempts >= maxLoginChallengeAttempts {
+		a.deleteLoginChallenge(ctx, challenge.ID)
+		return database.LoginChallenge{}, invalidErr
+	}
+
+	return challenge, nil
+}
+
+// failLoginChallenge counts a wrong code, the challenge is dropped after too many
+func (a *AuthService) failLoginChallenge(ctx context.Context, challenge database.LoginChallenge) {
+	attempts, err := a.DB.IncrementLoginChallengeAttempts(ctx, challenge.ID)
+	if err != nil {
+		log.Errorf("cannot increment attempts of login challenge %v, %v", challenge.ID, err)
+		return
+	}
+	if attempts >= maxLoginChallengeAttempts {
+		a.deleteLoginChallenge(ctx, challenge.ID)
+	}
+}
+
+func (a *AuthService) deleteLoginChallenge(ctx context.Context, challengeID uuid.UUID) {
+	if err := a.DB.DeleteLoginChallenge(ctx, challengeID); err != nil {
+		log.Errorf("cannot delete login challenge %v, %v", challengeID, err)
+	}
+}
+
+func (a *AuthService) getUserTotp(
+	ctx context.Context,
+	userID uuid.UUID,
+) (totp database.UserTotp, found bool, err error) {
+	totp, err = a.DB.GetUserTotp(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.UserTotp{}, false, nil
+		}
+		err = fmt.Errorf("%w, cannot fetch totp of user %v, %w", flux_errors.ErrInternal, userID, err)
+		log.Error(err)
+		return
+	}
+	return totp, true, nil
+}
+
+func (a *AuthService) getEnabledTotp(
+	ctx context.Context,
+	userID uuid.UUID,
+) (database.User, database.UserTotp, error) {
+	user, err := a.getUserByID(ctx, userID)
+	if err != nil {
+		return database.User{}, database.UserTotp{}, err
+	}
+	totp, found, err := a.getUserTotp(ctx, userID)
+	if err != nil {
+		return database.User{}, database.UserTotp{}, err
+	}
+	if !found || totp.ConfirmedAt == nil {
+		return database.User{}, database.UserTotp{}, fmt.Errorf(
+			"%w, 2fa is not enabled",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	return user, totp, nil
+}
+
+func (a *AuthService) getUserByID(ctx context.Context, userID uuid.UUID) (database.User, error) {
+	user, err := a.DB.GetUserById(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("%w, cannot fetch user %v, %w", flux_errors.ErrInternal, userID, err)
+		log.Error(err)
+		return database.User{}, err
+	}
+	return user, nil
+}
+
+func requiresTwoFactor(roles []string) bool {
+	for _, role := range twoFactorRequiredRoles {
+		if slices.Contains(roles, string(role)) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -18,12 +20,28 @@ import (
 
 const (
 	DefaultTokenExpiryMinutes = 15
+	// a token is deleted once it is guessed wrong this many times
+	maxTokenAttempts = 5
+	// base url of the frontend page that completes a verification,
+	// the purpose, email and token are appended as query parameters
+	KeyVerificationURL = "VERIFICATION_URL"
 )
 
 func (a *AuthService) SendVerificationEmail(
 	ctx context.Context,
 	userEmail string,
 	verifyPurpose email.EmailPurpose,
+) error {
+	return a.sendVerificationEmail(ctx, userEmail, verifyPurpose, json.RawMessage("{}"))
+}
+
+// sendVerificationEmail stores the payload with the token,
+// it can be read back once the token is validated
+func (a *AuthService) sendVerificationEmail(
+	ctx context.Context,
+	userEmail string,
+	verifyPurpose email.EmailPurpose,
+	payload json.RawMessage,
 ) error {
 	// validate the email
 	if err := service.ValidateInput(
@@ -37,6 +55,11 @@ func (a *AuthService) SendVerificationEmail(
 		return err
 	}
 
+	// mails are throttled per recipient so that the smtp queue cannot be flooded
+	if _, err := a.Limiter.AllowAccount(ctx, mailAccountKey(userEmail)); err != nil {
+		return err
+	}
+
 	// create a new token
 	plainToken, err := generateToken()
 	if err != nil {
@@ -51,7 +74,7 @@ func (a *AuthService) SendVerificationEmail(
 	}
 
 	// create a new token in db
-	err = a.createTokenInDb(ctx, userEmail, verifyPurpose, string(hashToken))
+	err = a.createTokenInDb(ctx, userEmail, verifyPurpose, string(hashToken), payload)
 	if err != nil {
 		return err
 	}
@@ -62,7 +85,7 @@ func (a *AuthService) SendVerificationEmail(
 		err = email.NewMail(
 			ctx,
 			"Verify your flux user account",
-			fmt.Sprintf("token to verify your flux accout, %v", plainToken),
+			verificationMessage("verify your flux account", userEmail, verifyPurpose, plainToken),
 			email.KeyEmailBodyPlain,
 			verifyPurpose,
 			userEmail,
@@ -71,8 +94,18 @@ func (a *AuthService) SendVerificationEmail(
 		err = email.NewMail(
 			ctx,
 			"Flux account password reset",
+			verificationMessage("reset your password", userEmail, verifyPurpose, plainToken)+
+				", if this is not you please inform.",
+			email.KeyEmailBodyPlain,
+			verifyPurpose,
+			userEmail,
+		)
+	case email.PurposeEmailChange:
+		err = email.NewMail(
+			ctx,
+			"Verify your new flux account email",
 			fmt.Sprintf(
-				"token to reset your password: %s, if this is not you please inform.",
+				"token to change the email of your flux account to this one: %s",
 				plainToken,
 			),
 			email.KeyEmailBodyPlain,
@@ -89,6 +122,7 @@ func (a *AuthService) createTokenInDb(
 	userEmail string,
 	purpose email.EmailPurpose,
 	hashedToken string,
+	payload json.RawMessage,
 ) error {
 	// create expiry field
 	expiry := time.Now().Add(DefaultTokenExpiryMinutes * time.Minute)
@@ -100,7 +134,7 @@ func (a *AuthService) createTokenInDb(
 			Purpose:     string(purpose),
 			ExpiresAt:   expiry,
 			Email:       userEmail,
-			Payload:     json.RawMessage("{}"),
+			Payload:     payload,
 		},
 	)
 
@@ -112,16 +146,17 @@ func (a *AuthService) createTokenInDb(
 	return nil
 }
 
-// validate the verification token sent by user to verify email
+// validate the verification token sent by user to verify email,
+// the token is returned to read its payload
 func (a *AuthService) validateVerificationToken(
 	ctx context.Context,
 	token string,
 	userMail string,
 	purpose email.EmailPurpose,
-) error {
+) (database.Token, error) {
 	dbToken, err := a.verifyToken(ctx, token, userMail, purpose)
 	if err != nil {
-		return err
+		return database.Token{}, err
 	}
 
 	// --- any extra verification ---
@@ -131,7 +166,7 @@ func (a *AuthService) validateVerificationToken(
 		// create a logging helper
 		invLogger := log.WithFields(
 			log.Fields{
-				"purpose": string(email.PurposeEmailSignUp),
+				"purpose": string(purpose),
 				"token":   token,
 			},
 		)
@@ -141,7 +176,7 @@ func (a *AuthService) validateVerificationToken(
 			ctx,
 			token,
 			userMail,
-			email.PurposeEmailSignUp,
+			purpose,
 		); invErr != nil {
 			// failed to invalidate. log the error
 			invLogger.Errorf("failed to invalidate expired token, %v", invErr)
@@ -150,14 +185,14 @@ func (a *AuthService) validateVerificationToken(
 		}
 
 		// return expiration error
-		return fmt.Errorf(
+		return database.Token{}, fmt.Errorf(
 			"%w, %w",
 			flux_errors.ErrInvalidRequest,
 			flux_errors.ErrVerificationTokenExpired,
 		)
 	}
 
-	return nil
+	return dbToken, nil
 }
 
 func (a *AuthService) invalidateVerificationToken(
@@ -188,6 +223,50 @@ func (a *AuthService) invalidateVerificationToken(
 	return err
 }
 
+// consumeVerificationToken uses up a validated token within the transaction
+// of the action it allows, of two concurrent uses only one finds the token
+func (a *AuthService) consumeVerificationToken(
+	ctx context.Context,
+	qtx *database.Queries,
+	dbToken database.Token,
+) (database.Token, error) {
+	consumed, err := qtx.ConsumeToken(ctx, dbToken.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Token{}, fmt.Errorf(
+				"%w, token is already used, please request a new token",
+				flux_errors.ErrCorruptedVerification,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot consume token %v, %w",
+			flux_errors.ErrInternal,
+			dbToken.ID,
+			err,
+		)
+		log.Error(err)
+		return database.Token{}, err
+	}
+
+	// older tokens sent for the same purpose cannot be used anymore either
+	err = qtx.DeleteByEmailAndPurpose(ctx, database.DeleteByEmailAndPurposeParams{
+		Email:   consumed.Email,
+		Purpose: consumed.Purpose,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot invalidate tokens of %s, %w",
+			flux_errors.ErrInternal,
+			consumed.Email,
+			err,
+		)
+		log.Error(err)
+		return database.Token{}, err
+	}
+
+	return consumed, nil
+}
+
 /*
 Check if the latest token inserted in db for a specific email and purpose matches the token
 */
@@ -224,13 +303,67 @@ func (a *AuthService) verifyToken(
 		return
 	}
 
+	// a token that was guessed too many times cannot be used anymore
+	if dbToken.Attempts >= maxTokenAttempts {
+		a.deleteToken(ctx, dbToken)
+		err = fmt.Errorf(
+			"%w, too many invalid attempts, please request a new token",
+			flux_errors.ErrCorruptedVerification,
+		)
+		return
+	}
+
 	// check if token is correct
 	err = bcrypt.CompareHashAndPassword([]byte(dbToken.HashedToken), []byte(token))
 	if err != nil {
 		log.Infof("invalid token. failed to match token hash and token, %v", err)
 		err = fmt.Errorf("%w, please cross check your token", flux_errors.ErrCorruptedVerification)
+
+		// count the failed attempt
+		attempts, incErr := a.DB.IncrementTokenAttempts(ctx, dbToken.ID)
+		if incErr != nil {
+			log.Errorf("cannot increment attempts of token %v, %v", dbToken.ID, incErr)
+			return
+		}
+		if attempts >= maxTokenAttempts {
+			a.deleteToken(ctx, dbToken)
+			err = fmt.Errorf(
+				"%w, too many invalid attempts, please request a new token",
+				flux_errors.ErrCorruptedVerification,
+			)
+		}
 		return
 	}
 
 	return
 }
+
+func (a *AuthService) deleteToken(ctx context.Context, dbToken database.Token) {
+	if err := a.DB.DeleteToken(ctx, dbToken.ID); err != nil {
+		log.Errorf("cannot delete token %v, %v", dbToken.ID, err)
+		return
+	}
+	log.WithFields(log.Fields{
+		"email":   dbToken.Email,
+		"purpose": dbToken.Purpose,
+	}).Info("deleted token after too many invalid attempts")
+}
+
+// verificationMessage builds the one click link sent in the mail,
+// the plain token is sent when no frontend url is configured
+func verificationMessage(
+	action string,
+	userEmail string,
+	purpose email.EmailPurpose,
+	plainToken string,
+) string {
+	baseURL := os.Getenv(KeyVerificationURL)
+	if baseURL == "" {
+		return fmt.Sprintf("token to %s: %s", action, plainToken)
+	}
+	query := url.Values{}
+	query.Set("purpose", string(purpose))
+	query.Set("email", userEmail)
+	query.Set("token", plainToken)
+	return fmt.Sprintf("open this link to %s: %s?%s", action, baseURL, query.Encode())
+}
@@ -2,6 +2,7 @@ package contest_service
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"time"
@@ -71,7 +72,7 @@ func (c *ContestService) validatePrivateContest(
 		return err
 	}
 
-	return nil
+	return validateContestRegistration(contest, *contest.StartTime)
 }
 
 func (c *ContestService) validatePublicContest(
@@ -128,6 +129,65 @@ func (c *ContestService) validatePublicContest(
 		)
 	}
 
+	return validateContestRegistration(request.ContestDetails, *lock.Timeout)
+}
+
+// validateContestRegistration validates the self registration settings
+// of a contest which starts at startTime
+func validateContestRegistration(
+	contest Contest,
+	startTime time.Time,
+) error {
+	// anyone can participate in a published contest
+	if contest.IsPublished &&
+		(contest.SelfRegistration || contest.MaxParticipants != nil) {
+		return fmt.Errorf(
+			"%w, published contests cannot have self registration or a participant cap",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// teams are registered before the contest, so it can't be open to everyone
+	if contest.TeamContest && contest.IsPublished {
+		return fmt.Errorf(
+			"%w, team contests cannot be published",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// window is meaningful only if users can register themselves
+	if !contest.SelfRegistration &&
+		(contest.RegistrationStart != nil || contest.RegistrationEnd != nil) {
+		return fmt.Errorf(
+			"%w, registration window requires self registration",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// !Before ensures start != end
+	if contest.RegistrationStart != nil && contest.RegistrationEnd != nil &&
+		!contest.RegistrationStart.Before(*contest.RegistrationEnd) {
+		return fmt.Errorf(
+			"%w, registration start must be less than registration end",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// registrations close once the contest starts
+	if contest.RegistrationStart != nil &&
+		!contest.RegistrationStart.Before(startTime) {
+		return fmt.Errorf(
+			"%w, registration must start before the contest starts",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if contest.RegistrationEnd != nil && contest.RegistrationEnd.After(startTime) {
+		return fmt.Errorf(
+			"%w, registration must end before the contest starts",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	return nil
 }
 
@@ -309,6 +369,91 @@ func (c *ContestService) addUsersToContest(
 		return nil
 	}
 
+	// lock the contest so concurrent registrations are counted correctly
+	maxParticipants, err := c.lockContest(ctx, qtx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// fetch users
+	usersSet, err := c.getUsersByUserNames(ctx, userNames)
+	if err != nil {
+		return err
+	}
+
+	// insert users into db (already registered users are skipped)
+	for _, user := range usersSet {
+		err := qtx.RegisterUserToContest(
+			ctx, database.RegisterUserToContestParams{
+				ContestID: contestID,
+				UserID:    user.UserID,
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot register user %s to contest %v, %w",
+				flux_errors.ErrInternal,
+				user.UserName,
+				contestID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+	}
+
+	return c.validateContestCapacity(ctx, qtx, contestID, maxParticipants)
+}
+
+func (c *ContestService) removeUsersFromContest(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	userNames []string,
+) error {
+	if qtx == nil {
+		return fmt.Errorf(
+			"%w, transaction query tool is nil, cannot remove users from contest with id %v",
+			flux_errors.ErrInternal,
+			contestID,
+		)
+	}
+
+	// fetch users
+	usersSet, err := c.getUsersByUserNames(ctx, userNames)
+	if err != nil {
+		return err
+	}
+
+	// delete users from db (users who are not registered are skipped)
+	for _, user := range usersSet {
+		_, err := qtx.UnregisterUserFromContest(
+			ctx, database.UnregisterUserFromContestParams{
+				ContestID: contestID,
+				UserID:    user.UserID,
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot unregister user %s from contest %v, %w",
+				flux_errors.ErrInternal,
+				user.UserName,
+				contestID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getUsersByUserNames fetches the users and fails if any of them doesn't exist
+func (c *ContestService) getUsersByUserNames(
+	ctx context.Context,
+	userNames []string,
+) (map[string]user_service.UserMetaData, error) {
 	// fetch users by filters
 	users, err := c.UserServiceConfig.GetUsersByFilters(
 		ctx,
@@ -318,6 +463,9 @@ func (c *ContestService) addUsersToContest(
 			PageSize:   int32(len(userNames)),
 		},
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	usersSet := make(map[string]user_service.UserMetaData)
 
@@ -330,7 +478,7 @@ func (c *ContestService) addUsersToContest(
 	for _, userName := range userNames {
 		_, ok := usersSet[userName]
 		if !ok {
-			return fmt.Errorf(
+			return nil, fmt.Errorf(
 				"%w, user %s does not exist",
 				flux_errors.ErrInvalidRequest,
 				userName,
@@ -338,25 +486,69 @@ func (c *ContestService) addUsersToContest(
 		}
 	}
 
-	// insert users into db
-	for _, user := range usersSet {
-		_, err := qtx.RegisterUserToContest(
-			ctx, database.RegisterUserToContestParams{
-				ContestID: contestID,
-				UserID:    user.UserID,
-			},
-		)
-		if err != nil {
-			err = fmt.Errorf(
-				"%w, cannot register user %s to contest %v, %w",
-				flux_errors.ErrInternal,
-				user.UserName,
+	return usersSet, nil
+}
+
+// lockContest locks the contest row till the end of the transaction and
+// returns its participant cap
+func (c *ContestService) lockContest(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+) (*int32, error) {
+	maxParticipants, err := qtx.LockContestForUpdate(ctx, contestID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf(
+				"%w, contest with id %v does not exist",
+				flux_errors.ErrInvalidRequest,
 				contestID,
-				err,
 			)
-			log.Error(err)
-			return err
 		}
+		err = fmt.Errorf(
+			"%w, cannot lock contest with id %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	return maxParticipants, nil
+}
+
+// validateContestCapacity must be called within the transaction
+// which locked the contest
+func (c *ContestService) validateContestCapacity(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	maxParticipants *int32,
+) error {
+	// no cap
+	if maxParticipants == nil {
+		return nil
+	}
+
+	count, err := qtx.CountContestUsers(ctx, contestID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot count users of contest with id %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	if count > int64(*maxParticipants) {
+		return fmt.Errorf(
+			"%w, contest can have atmost %d participants",
+			flux_errors.ErrInvalidRequest,
+			*maxParticipants,
+		)
 	}
 
 	return nil
@@ -409,11 +601,26 @@ func dbContestToServiceContest(
 		EndTime:     dbContest.EndTime.UTC(),
 		IsPublished: dbContest.IsPublished,
 		CreatedBy:   dbContest.CreatedBy,
+
+		SelfRegistration:  dbContest.SelfRegistration,
+		RegistrationStart: utcTime(dbContest.RegistrationStart),
+		RegistrationEnd:   utcTime(dbContest.RegistrationEnd),
+		MaxParticipants:   dbContest.MaxParticipants,
+		TeamContest:       dbContest.TeamContest,
+
 		LockAccess:  lockAccess,
 		LockTimeout: dbContest.LockTimeout,
 	}, nil
 }
 
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	ut := t.UTC()
+	return &ut
+}
+
 func (c *ContestService) authorizeContestUpdate(
 	ctx context.Context,
 	contest Contest,
@@ -424,11 +631,11 @@ func (c *ContestService) authorizeContestUpdate(
 		return err
 	}
 
-	// any manager can update a public contest
+	// public contests can be updated by anyone managing them
 	if contest.LockId != nil {
-		err = c.UserServiceConfig.AuthorizeUserRole(
+		err = c.UserServiceConfig.AuthorizePermission(
 			ctx,
-			user_service.RoleManager,
+			user_service.PermContestManagePublic,
 			fmt.Sprintf(
 				"user %s tried to update unauthorized public contest with id %v",
 				claims.UserName,
@@ -441,6 +648,7 @@ func (c *ContestService) authorizeContestUpdate(
 		err = c.UserServiceConfig.AuthorizeCreatorAccess(
 			ctx,
 			contest.CreatedBy,
+			user_service.PermContestEditAny,
 			fmt.Sprintf(
 				"user %s tried to update unauthorized private contest with id %v",
 				claims.UserName,
@@ -47,6 +47,14 @@ func (c *ContestService) CreateContest(
 		startTime = lock.Timeout
 	}
 
+	// team contests register teams once created
+	if request.ContestDetails.TeamContest && len(request.RegisteredUsers) > 0 {
+		return Contest{}, fmt.Errorf(
+			"%w, team contests cannot have registered users",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	// get claims
 	claims, err := service.GetClaimsFromContext(ctx)
 	if err != nil {
@@ -85,6 +93,12 @@ func (c *ContestService) CreateContest(
 			EndTime:     request.ContestDetails.EndTime,
 			IsPublished: request.ContestDetails.IsPublished,
 			LockID:      request.ContestDetails.LockId,
+
+			SelfRegistration:  request.ContestDetails.SelfRegistration,
+			RegistrationStart: request.ContestDetails.RegistrationStart,
+			RegistrationEnd:   request.ContestDetails.RegistrationEnd,
+			MaxParticipants:   request.ContestDetails.MaxParticipants,
+			TeamContest:       request.ContestDetails.TeamContest,
 		},
 	)
 	if err != nil {
@@ -136,5 +150,11 @@ func (c *ContestService) CreateContest(
 		EndTime:     dbContest.EndTime.UTC(),
 		IsPublished: dbContest.IsPublished,
 		CreatedBy:   dbContest.CreatedBy,
+
+		SelfRegistration:  dbContest.SelfRegistration,
+		RegistrationStart: utcTime(dbContest.RegistrationStart),
+		RegistrationEnd:   utcTime(dbContest.RegistrationEnd),
+		MaxParticipants:   dbContest.MaxParticipants,
+		TeamContest:       dbContest.TeamContest,
 	}, nil
 }
@@ -59,6 +59,19 @@ func (c *ContestService) DeleteContest(
 		return err
 	}
 
+	// unregister teams
+	err = qtx.UnRegisterContestTeams(ctx, id)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot unregister teams of contest %v, %w",
+			flux_errors.ErrInternal,
+			id,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
 	// delete contest problems
 	err = qtx.DeleteProblemsByContestId(ctx, id)
 	if err != nil {
@@ -9,6 +9,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (c *ContestService) GetContestProblems(
@@ -22,7 +23,7 @@ func (c *ContestService) GetContestProblems(
 	}
 
 	// authorize
-	err = c.authorizeProblemView(ctx, contest)
+	err = c.AuthorizeProblemView(ctx, contest)
 	if err != nil {
 		return nil, err
 	}
@@ -80,7 +81,9 @@ func (c *ContestService) GetContestProblems(
 	return res, nil
 }
 
-func (c *ContestService) authorizeProblemView(
+// AuthorizeProblemView authorizes the user to see the problems of the contest
+// and everything that reveals them, like its leaderboard and submissions
+func (c *ContestService) AuthorizeProblemView(
 	ctx context.Context,
 	contest Contest,
 ) error {
@@ -124,8 +127,12 @@ func (c *ContestService) authorizeProblemView(
 			return err
 		}
 
-		err := c.UserServiceConfig.AuthorizeUserRole(
+		// the contest has not started, so only the access and allowed users of the lock matter
+		err := c.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*contest.LockId,
+			nil,
+			nil,
 			*contest.LockAccess,
 			"",
 		)
@@ -137,6 +144,7 @@ func (c *ContestService) authorizeProblemView(
 		err := c.UserServiceConfig.AuthorizeCreatorAccess(
 			ctx,
 			contest.CreatedBy,
+			user_service.PermContestEditAny,
 			"",
 		)
 		if err != nil {
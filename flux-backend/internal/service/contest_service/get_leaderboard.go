@@ -0,0 +1,120 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (c *ContestService) GetContestLeaderboard(
+	ctx context.Context,
+	request GetLeaderboardRequest,
+) ([]LeaderboardEntry, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// leaderboard is visible to those who can see the problems
+	err = c.AuthorizeProblemView(ctx, contest)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := (request.PageNumber - 1) * request.PageSize
+
+	// teams are ranked instead of users in team contests
+	if contest.TeamContest {
+		return c.getContestTeamLeaderboard(ctx, contest, request.PageSize, offset)
+	}
+
+	// get the leaderboard
+	// AuthorizeProblemView ensures start time is not nil
+	dbEntries, err := c.DB.GetContestLeaderboard(
+		ctx,
+		database.GetContestLeaderboardParams{
+			ContestID: contest.ID,
+			StartTime: *contest.StartTime,
+			Limit:     request.PageSize,
+			Offset:    offset,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch leaderboard of contest %v, %w",
+			flux_errors.ErrInternal,
+			contest.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]LeaderboardEntry, 0, len(dbEntries))
+	for _, dbEntry := range dbEntries {
+		res = append(res, LeaderboardEntry{
+			Rank:        dbEntry.Rank,
+			UserName:    dbEntry.UserName,
+			RollNo:      dbEntry.RollNo,
+			TotalScore:  dbEntry.TotalScore,
+			Penalty:     dbEntry.Penalty,
+			SolvedCount: dbEntry.SolvedCount,
+		})
+	}
+
+	return res, nil
+}
+
+func (c *ContestService) getContestTeamLeaderboard(
+	ctx context.Context,
+	contest Contest,
+	limit int32,
+	offset int32,
+) ([]LeaderboardEntry, error) {
+	// AuthorizeProblemView ensures start time is not nil
+	dbEntries, err := c.DB.GetContestTeamLeaderboard(
+		ctx,
+		database.GetContestTeamLeaderboardParams{
+			ContestID: contest.ID,
+			StartTime: *contest.StartTime,
+			Limit:     limit,
+			Offset:    offset,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch team leaderboard of contest %v, %w",
+			flux_errors.ErrInternal,
+			contest.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]LeaderboardEntry, 0, len(dbEntries))
+	for _, dbEntry := range dbEntries {
+		res = append(res, LeaderboardEntry{
+			Rank:        dbEntry.Rank,
+			TeamName:    dbEntry.TeamName,
+			TotalScore:  dbEntry.TotalScore,
+			Penalty:     dbEntry.Penalty,
+			SolvedCount: dbEntry.SolvedCount,
+		})
+	}
+
+	return res, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
@@ -15,6 +16,7 @@ type ContestService struct {
 	UserServiceConfig    *user_service.UserService
 	LockServiceConfig    *lock_service.LockService
 	ProblemServiceConfig *problem_service.ProblemService
+	TeamServiceConfig    *team_service.TeamService
 }
 
 type ContestProblem struct {
@@ -37,6 +39,15 @@ type Contest struct {
 	IsPublished bool       `json:"is_published"`
 	CreatedBy   uuid.UUID  `json:"created_by"`
 
+	// self registration, window and cap are optional
+	SelfRegistration  bool       `json:"self_registration"`
+	RegistrationStart *time.Time `json:"registration_start"`
+	RegistrationEnd   *time.Time `json:"registration_end"`
+	MaxParticipants   *int32     `json:"max_participants" validate:"omitempty,min=1"`
+
+	// team contests register teams instead of users
+	TeamContest bool `json:"team_contest"`
+
 	// fields used only for internal purpose
 	LockAccess  *user_service.UserRole `json:"-"`
 	LockTimeout *time.Time             `json:"-"`
@@ -48,6 +59,21 @@ type CreateContestRequest struct {
 	ContestProblems []ContestProblem `json:"problems"`
 }
 
+type ChangeContestUsersRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	UserNames []string  `json:"user_names" validate:"required,min=1,max=10000"`
+}
+
+type TeamRegistrationRequest struct {
+	ContestID uuid.UUID `json:"contest_id"`
+	TeamID    uuid.UUID `json:"team_id"`
+}
+
+type ContestTeam struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Name   string    `json:"name"`
+}
+
 type GetContestRequest struct {
 	ContestIDs  []uuid.UUID `json:"contest_ids"`
 	IsPublished *bool       `json:"is_published"`
@@ -56,3 +82,19 @@ type GetContestRequest struct {
 	PageNumber  int32       `json:"page_number" validate:"min=1,max=10000"`
 	PageSize    int32       `json:"page_size" validate:"min=0,max=10000"`
 }
+
+type GetLeaderboardRequest struct {
+	ContestID  uuid.UUID `json:"contest_id"`
+	PageNumber int32     `json:"page_number" validate:"min=1,max=10000"`
+	PageSize   int32     `json:"page_size" validate:"min=0,max=10000"`
+}
+
+type LeaderboardEntry struct {
+	Rank        int32  `json:"rank"`
+	UserName    string `json:"user_name,omitempty"`
+	RollNo      string `json:"roll_no,omitempty"`
+	TeamName    string `json:"team_name,omitempty"`
+	TotalScore  int32  `json:"total_score"`
+	Penalty     int32  `json:"penalty"`
+	SolvedCount int32  `json:"solved_count"`
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
 )
@@ -41,6 +42,14 @@ func (c *ContestService) RegisterUsersToContest(
 		)
 	}
 
+	// team contest register teams
+	if contest.TeamContest {
+		return fmt.Errorf(
+			"%w, team contests cannot have registered users",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	// create a new transaction
 	tx, err := service.GetNewTransaction(ctx)
 	if err != nil {
@@ -53,6 +62,12 @@ func (c *ContestService) RegisterUsersToContest(
 	// get a new query tool with this transaction
 	qtx := c.DB.WithTx(tx)
 
+	// lock the contest so concurrent registrations don't interleave
+	_, err = c.lockContest(ctx, qtx, contestID)
+	if err != nil {
+		return err
+	}
+
 	// unregister previous users
 	err = qtx.UnRegisterContestUsers(ctx, contestID)
 	if err != nil {
@@ -93,3 +108,115 @@ func (c *ContestService) RegisterUsersToContest(
 
 	return nil
 }
+
+// AddUsersToContestWithTx registers the users on top of the existing ones
+// as a part of the caller's transaction, the caller authorizes the change
+func (c *ContestService) AddUsersToContestWithTx(
+	ctx context.Context,
+	qtx *database.Queries,
+	contestID uuid.UUID,
+	userNames []string,
+) error {
+	return c.addUsersToContest(ctx, qtx, contestID, userNames)
+}
+
+// AddUsersToContest registers the users on top of the existing ones
+func (c *ContestService) AddUsersToContest(
+	ctx context.Context,
+	request ChangeContestUsersRequest,
+) error {
+	return c.changeContestUsers(ctx, request, c.addUsersToContest, "added")
+}
+
+// RemoveUsersFromContest unregisters only the given users
+func (c *ContestService) RemoveUsersFromContest(
+	ctx context.Context,
+	request ChangeContestUsersRequest,
+) error {
+	return c.changeContestUsers(ctx, request, c.removeUsersFromContest, "removed")
+}
+
+func (c *ContestService) changeContestUsers(
+	ctx context.Context,
+	request ChangeContestUsersRequest,
+	change func(context.Context, *database.Queries, uuid.UUID, []string) error,
+	action string,
+) error {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	err = c.authorizeContestUpdate(ctx, contest)
+	if err != nil {
+		return err
+	}
+
+	// published contest cannot have any registered users
+	if contest.IsPublished {
+		return fmt.Errorf(
+			"%w, published contests cannot have any registered users",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// team contest register teams
+	if contest.TeamContest {
+		return fmt.Errorf(
+			"%w, team contests cannot have registered users",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// add or remove the users
+	err = change(ctx, qtx, request.ContestID, request.UserNames)
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after changing users of contest, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		return err
+	}
+
+	log.Infof(
+		"user %s %s %d users in contest with id %v",
+		claims.UserName,
+		action,
+		len(request.UserNames),
+		request.ContestID,
+	)
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package contest_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// RegisterForContest registers the requesting user to a contest
+// which allows self registration
+func (c *ContestService) RegisterForContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// validate the registration
+	if err = validateIndividualContest(contest); err != nil {
+		return err
+	}
+	if err = validateSelfRegistration(contest); err != nil {
+		return err
+	}
+	if err = validateRegistrationWindow(contest); err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the contest so the cap is not exceeded by concurrent registrations
+	maxParticipants, err := c.lockContest(ctx, qtx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// register (registering again has no effect)
+	err = qtx.RegisterUserToContest(ctx, database.RegisterUserToContestParams{
+		ContestID: contestID,
+		UserID:    claims.UserId,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot register user %s to contest %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// validate the cap
+	err = c.validateContestCapacity(ctx, qtx, contestID, maxParticipants)
+	if err != nil {
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after registering user, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		return err
+	}
+
+	return nil
+}
+
+// UnregisterFromContest unregisters the requesting user from a contest
+// which allows self registration
+func (c *ContestService) UnregisterFromContest(
+	ctx context.Context,
+	contestID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get contest
+	contest, err := c.GetContestByID(ctx, contestID)
+	if err != nil {
+		return err
+	}
+
+	// validate, users can unregister only while the registration is open
+	if err = validateIndividualContest(contest); err != nil {
+		return err
+	}
+	if err = validateSelfRegistration(contest); err != nil {
+		return err
+	}
+	if err = validateRegistrationWindow(contest); err != nil {
+		return err
+	}
+
+	// unregister
+	rows, err := c.DB.UnregisterUserFromContest(
+		ctx,
+		database.UnregisterUserFromContestParams{
+			ContestID: contestID,
+			UserID:    claims.UserId,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot unregister user %s from contest %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf(
+			"%w, you are not registered to this contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func validateSelfRegistration(contest Contest) error {
+	// anyone can participate in a published contest
+	if contest.IsPublished {
+		return fmt.Errorf(
+			"%w, published contests do not need registration",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	if !contest.SelfRegistration {
+		return fmt.Errorf(
+			"%w, contest does not allow self registration",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// dbContestToServiceContest ensures start time is not nil
+	if !time.Now().Before(*contest.StartTime) {
+		return fmt.Errorf(
+			"%w, cannot change registration once the contest has started",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func validateRegistrationWindow(contest Contest) error {
+	now := time.Now()
+	if contest.RegistrationStart != nil && now.Before(*contest.RegistrationStart) {
+		return fmt.Errorf(
+			"%w, registration for this contest has not started yet",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if contest.RegistrationEnd != nil && now.After(*contest.RegistrationEnd) {
+		return fmt.Errorf(
+			"%w, registration for this contest has ended",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func validateIndividualContest(contest Contest) error {
+	if contest.TeamContest {
+		return fmt.Errorf(
+			"%w, this is a team contest, register your team instead",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,281 @@
+package contest_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// RegisterTeamForContest registers a team to a team contest. Members of the
+// team can do it if the contest allows self registration, otherwise only
+// those who can update the contest can.
+func (c *ContestService) RegisterTeamForContest(
+	ctx context.Context,
+	request TeamRegistrationRequest,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	selfRegistration, err := c.authorizeTeamRegistration(ctx, contest, request.TeamID)
+	if err != nil {
+		return err
+	}
+	if selfRegistration {
+		if err = validateRegistrationWindow(contest); err != nil {
+			return err
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the contest so the cap is not exceeded by concurrent registrations
+	maxParticipants, err := c.lockContest(ctx, qtx, request.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// lock the team so its members don't change meanwhile
+	_, err = qtx.LockTeamForUpdate(ctx, request.TeamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, team with id %v does not exist",
+				flux_errors.ErrInvalidRequest,
+				request.TeamID,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot lock team %v, %w",
+			flux_errors.ErrInternal,
+			request.TeamID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// register (registering again has no effect)
+	err = qtx.RegisterTeamToContest(ctx, database.RegisterTeamToContestParams{
+		TeamID:    request.TeamID,
+		ContestID: request.ContestID,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot register team %v to contest %v, %w",
+			flux_errors.ErrInternal,
+			request.TeamID,
+			request.ContestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// a user can participate through only one team
+	conflict, err := qtx.HasTeamMemberInOtherContestTeam(
+		ctx,
+		database.HasTeamMemberInOtherContestTeamParams{
+			ContestID: request.ContestID,
+			TeamID:    request.TeamID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check members of team %v in contest %v, %w",
+			flux_errors.ErrInternal,
+			request.TeamID,
+			request.ContestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if conflict {
+		return fmt.Errorf(
+			"%w, a member of the team is already registered through another team",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// validate the cap
+	if maxParticipants != nil {
+		count, err := qtx.CountContestTeams(ctx, request.ContestID)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot count teams of contest with id %v, %w",
+				flux_errors.ErrInternal,
+				request.ContestID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+		if count > int64(*maxParticipants) {
+			return fmt.Errorf(
+				"%w, contest can have atmost %d teams",
+				flux_errors.ErrInvalidRequest,
+				*maxParticipants,
+			)
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after registering team, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		return err
+	}
+
+	log.Infof(
+		"user %s registered team %v to contest %v",
+		claims.UserName,
+		request.TeamID,
+		request.ContestID,
+	)
+
+	return nil
+}
+
+func (c *ContestService) UnregisterTeamFromContest(
+	ctx context.Context,
+	request TeamRegistrationRequest,
+) error {
+	// get contest
+	contest, err := c.GetContestByID(ctx, request.ContestID)
+	if err != nil {
+		return err
+	}
+
+	// authorize, self registered teams leave only while the registration is open
+	selfRegistration, err := c.authorizeTeamRegistration(ctx, contest, request.TeamID)
+	if err != nil {
+		return err
+	}
+	if selfRegistration {
+		if err = validateRegistrationWindow(contest); err != nil {
+			return err
+		}
+	}
+
+	// unregister
+	rows, err := c.DB.UnregisterTeamFromContest(
+		ctx,
+		database.UnregisterTeamFromContestParams{
+			ContestID: request.ContestID,
+			TeamID:    request.TeamID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot unregister team %v from contest %v, %w",
+			flux_errors.ErrInternal,
+			request.TeamID,
+			request.ContestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf(
+			"%w, team is not registered to this contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func (c *ContestService) GetContestRegisteredTeams(
+	ctx context.Context,
+	contestID uuid.UUID,
+) ([]ContestTeam, error) {
+	// fetch teams from db
+	dbTeams, err := c.DB.GetContestTeams(ctx, contestID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch teams of contest %v, %w",
+			flux_errors.ErrInternal,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]ContestTeam, 0, len(dbTeams))
+	for _, dbTeam := range dbTeams {
+		res = append(res, ContestTeam{
+			TeamID: dbTeam.ID,
+			Name:   dbTeam.Name,
+		})
+	}
+
+	return res, nil
+}
+
+// authorizeTeamRegistration reports if the registration is done by a
+// member of the team through self registration
+func (c *ContestService) authorizeTeamRegistration(
+	ctx context.Context,
+	contest Contest,
+	teamID uuid.UUID,
+) (bool, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if !contest.TeamContest {
+		return false, fmt.Errorf(
+			"%w, contest is not a team contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// members register their own team
+	if contest.SelfRegistration {
+		member, err := c.TeamServiceConfig.IsTeamMember(ctx, teamID, claims.UserId)
+		if err != nil {
+			return false, err
+		}
+		if member {
+			return true, validateSelfRegistration(contest)
+		}
+	}
+
+	// others must be able to update the contest
+	return false, c.authorizeContestUpdate(ctx, contest)
+}
@@ -7,6 +7,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
 )
 
 func (c *ContestService) UpdateContest(
@@ -28,22 +29,48 @@ func (c *ContestService) UpdateContest(
 	}
 
 	// authorize
-	if err = c.authorizeContestUpdate(ctx, contest); err != nil {
+	if err = c.authorizeContestUpdate(ctx, prevContest); err != nil {
 		return Contest{}, err
 	}
 
+	// team contest cannot be changed once created
+	contest.TeamContest = prevContest.TeamContest
+
 	// validate the new contest
 	if err = c.validatePrivateContest(contest); err != nil {
 		return Contest{}, err
 	}
 
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := c.DB.WithTx(tx)
+
+	// lock the contest so no one registers while the cap is changed
+	_, err = c.lockContest(ctx, qtx, contest.ID)
+	if err != nil {
+		return Contest{}, err
+	}
+
 	// update the contest
-	dbContest, err := c.DB.UpdateContest(
+	dbContest, err := qtx.UpdateContest(
 		ctx,
 		database.UpdateContestParams{
-			Title:     contest.Title,
-			StartTime: contest.StartTime,
-			EndTime:   contest.EndTime,
+			ID:                contest.ID,
+			Title:             contest.Title,
+			StartTime:         contest.StartTime,
+			EndTime:           contest.EndTime,
+			SelfRegistration:  contest.SelfRegistration,
+			RegistrationStart: contest.RegistrationStart,
+			RegistrationEnd:   contest.RegistrationEnd,
+			MaxParticipants:   contest.MaxParticipants,
 		},
 	)
 	if err != nil {
@@ -56,6 +83,22 @@ func (c *ContestService) UpdateContest(
 		return Contest{}, err
 	}
 
+	// already registered users must fit in the new cap
+	err = c.validateContestCapacity(ctx, qtx, contest.ID, dbContest.MaxParticipants)
+	if err != nil {
+		return Contest{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after updating contest, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		return Contest{}, err
+	}
+
 	// add support to return the contest in case
 	//  we might allow updating public contests
 	return Contest{
@@ -65,5 +108,11 @@ func (c *ContestService) UpdateContest(
 		EndTime:     dbContest.EndTime,
 		CreatedBy:   dbContest.CreatedBy,
 		IsPublished: dbContest.IsPublished,
+
+		SelfRegistration:  dbContest.SelfRegistration,
+		RegistrationStart: utcTime(dbContest.RegistrationStart),
+		RegistrationEnd:   utcTime(dbContest.RegistrationEnd),
+		MaxParticipants:   dbContest.MaxParticipants,
+		TeamContest:       dbContest.TeamContest,
 	}, nil
 }
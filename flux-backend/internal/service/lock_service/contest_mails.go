@@ -0,0 +1,95 @@
+package lock_service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+)
+
+const contestMailTimeout = time.Minute
+
+// NotifyContestStart mails the users registered to the contests of a timer
+// lock once it is released, it is meant to be subscribed to the scheduler
+func (l *LockService) NotifyContestStart(ctx context.Context, event LockReleasedEvent) {
+	// manual locks do not start contests and a late start is not news anymore
+	if event.Timeout.IsZero() || event.Late {
+		return
+	}
+
+	recipients, err := l.DB.GetLockContestRecipients(ctx, &event.LockID)
+	if err != nil {
+		log.Errorf(
+			"cannot fetch registered users of contests of lock with id %v, %v",
+			event.LockID,
+			err,
+		)
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	// the handler must not block, the mails are queued in the background
+	go func() {
+		failed := mailLockContests(
+			recipients,
+			email.PurposeContestStarted,
+			func(title string) (string, string) {
+				return fmt.Sprintf("Contest %s has started", title),
+					fmt.Sprintf("contest %s has started, good luck!", title)
+			},
+		)
+		if failed > 0 {
+			log.Errorf(
+				"cannot notify %d of %d users about a contest start",
+				failed,
+				len(recipients),
+			)
+		}
+	}()
+}
+
+// mailLockContests queues a mail about its contest for the recipients of each
+// contest, in batches, and returns the number of recipients that were missed
+func mailLockContests(
+	recipients []database.GetLockContestRecipientsRow,
+	purpose email.EmailPurpose,
+	compose func(title string) (subject string, body string),
+) int {
+	ctx, cancel := context.WithTimeout(context.Background(), contestMailTimeout)
+	defer cancel()
+
+	// the same mail goes to every user of a contest
+	var contestIDs []uuid.UUID
+	titles := make(map[uuid.UUID]string)
+	emails := make(map[uuid.UUID][]string)
+	for _, recipient := range recipients {
+		if _, ok := titles[recipient.ContestID]; !ok {
+			contestIDs = append(contestIDs, recipient.ContestID)
+			titles[recipient.ContestID] = recipient.Title
+		}
+		emails[recipient.ContestID] = append(emails[recipient.ContestID], recipient.Email)
+	}
+
+	// users are put in bcc, so they do not see each other's address
+	failed := 0
+	for _, contestID := range contestIDs {
+		subject, body := compose(titles[contestID])
+		queued, _ := email.NewBulkMail(
+			ctx,
+			subject,
+			body,
+			email.KeyEmailBodyPlain,
+			purpose,
+			emails[contestID]...,
+		)
+		failed += len(emails[contestID]) - queued
+	}
+
+	return failed
+}
@@ -22,9 +22,9 @@ func (l *LockService) CreateLock(
 	}
 
 	// authorize user
-	err = l.UserServiceConfig.AuthorizeUserRole(
+	err = l.UserServiceConfig.AuthorizePermission(
 		ctx,
-		user_service.RoleManager,
+		user_service.PermLockCreate,
 		fmt.Sprintf("user %s tried to create a lock", claims.UserName),
 	)
 	if err != nil {
@@ -35,14 +35,30 @@ func (l *LockService) CreateLock(
 	if err = validateLock(lock); err != nil {
 		return FluxLock{}, err
 	}
+	if err = l.validateLockAccess(ctx, &lock.Access); err != nil {
+		return FluxLock{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
 
 	// create the lock
-	dbLock, err := l.DB.CreateLock(ctx, database.CreateLockParams{
+	dbLock, err := qtx.CreateLock(ctx, database.CreateLockParams{
 		Timeout:     lock.Timeout,
 		LockType:    lock.Type,
 		Name:        lock.Name,
 		CreatedBy:   claims.UserId,
 		Description: lock.Description,
+		Access:      string(lock.Access),
 	})
 	if err != nil {
 		err = fmt.Errorf(
@@ -54,5 +70,30 @@ func (l *LockService) CreateLock(
 		return FluxLock{}, err
 	}
 
-	return dbLockToServiceLock(dbLock), nil
+	// add the allowed users
+	allowedUsers, err := setLockAllowedUsers(ctx, qtx, dbLock.ID, lock.AllowedUsers)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit creation of lock, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	res := dbLockToServiceLock(dbLock)
+	res.AllowedUsers = allowedUsers
+
+	// announce the release once the timer expires
+	if l.Scheduler != nil {
+		l.Scheduler.Schedule(res)
+	}
+
+	return res, nil
 }
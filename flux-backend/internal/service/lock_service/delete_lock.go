@@ -10,6 +10,7 @@ import (
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
@@ -29,6 +30,7 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 	err = l.UserServiceConfig.AuthorizeCreatorAccess(
 		ctx,
 		lock.CreatedBy,
+		user_service.PermLockEditAny,
 		fmt.Sprintf(
 			"user %s tried to delete lock with id %v",
 			claims.UserName,
@@ -51,10 +53,10 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
 			if pgErr.Code == flux_errors.CodeForeignKeyConstraint {
+				// problems and rounds are unlocked, contests must be moved first
 				return fmt.Errorf(
-					"%w, %w",
+					"%w, lock is still used by contests, reassign them to another lock first",
 					flux_errors.ErrInvalidRequest,
-					pgErr,
 				)
 			}
 		}
@@ -63,5 +65,10 @@ func (l *LockService) DeleteLock(ctx context.Context, lockId uuid.UUID) error {
 		)
 	}
 
+	// a deleted lock has nothing left to release
+	if l.Scheduler != nil {
+		l.Scheduler.Unschedule(lockId)
+	}
+
 	return nil
 }
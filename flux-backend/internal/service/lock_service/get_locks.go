@@ -46,7 +46,9 @@ func (l *LockService) GetLockById(
 	// authorize
 	err = l.AuthorizeLock(
 		ctx,
+		dbLock.ID,
 		dbLock.Timeout,
+		dbLock.ReleasedAt,
 		user_service.UserRole(dbLock.Access),
 		fmt.Sprintf(
 			"user %s tried to view lock with id %v",
@@ -84,10 +86,10 @@ func (l *LockService) GetLocksByFilters(
 	}
 
 	// authorize
-	// only manager or above can view locks
-	err = l.UserServiceConfig.AuthorizeUserRole(
+	// only users with lock.view can search locks
+	err = l.UserServiceConfig.AuthorizePermission(
 		ctx,
-		user_service.RoleManager,
+		user_service.PermLockView,
 		fmt.Sprintf(
 			"user %s tried to view lock with filters",
 			claims.UserName,
@@ -135,13 +137,14 @@ func (l *LockService) GetLocksByFilters(
 	}
 
 	// convert the locks to service locks
+	authorizer := l.NewLockAuthorizer(ctx)
 	locks := make([]FluxLock, 0, len(dbLocks))
 	for _, dbLock := range dbLocks {
-		err = l.AuthorizeLock(
-			ctx,
+		err = authorizer.AuthorizeLock(
+			dbLock.ID,
 			dbLock.Timeout,
+			dbLock.ReleasedAt,
 			user_service.UserRole(dbLock.Access),
-			"",
 		)
 		if err != nil {
 			log.Debug(err)
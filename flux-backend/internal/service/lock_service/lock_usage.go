@@ -0,0 +1,350 @@
+package lock_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// GetLockUsage lists the problems, contests and tournament rounds of the lock
+func (l *LockService) GetLockUsage(
+	ctx context.Context,
+	lockID uuid.UUID,
+) (LockUsage, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, lockID)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		lock.CreatedBy,
+		user_service.PermLockEditAny,
+		fmt.Sprintf(
+			"user %s tried to view usage of lock with id %v",
+			claims.UserName,
+			lock.ID,
+		),
+	)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	return getLockUsage(ctx, l.DB, lock.ID)
+}
+
+// ReassignLock moves everything protected by a lock to another one in a single
+// transaction, the target must satisfy the same rules as when they were created
+func (l *LockService) ReassignLock(
+	ctx context.Context,
+	request ReassignLockRequest,
+) (LockUsage, error) {
+	// validate request
+	err := service.ValidateInput(request)
+	if err != nil {
+		return LockUsage{}, err
+	}
+	if request.LockID == request.TargetLockID {
+		return LockUsage{}, fmt.Errorf(
+			"%w, lock and target lock must be different",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// get the locks
+	lock, err := l.GetLockById(ctx, request.LockID)
+	if err != nil {
+		return LockUsage{}, err
+	}
+	targetLock, err := l.GetLockById(ctx, request.TargetLockID)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// authorize on both the locks
+	for _, fluxLock := range []FluxLock{lock, targetLock} {
+		err = l.UserServiceConfig.AuthorizeCreatorAccess(
+			ctx,
+			fluxLock.CreatedBy,
+			user_service.PermLockEditAny,
+			fmt.Sprintf(
+				"user %s tried to reassign lock %v to %v",
+				claims.UserName,
+				lock.ID,
+				targetLock.ID,
+			),
+		)
+		if err != nil {
+			return LockUsage{}, err
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	// fetch what has to be moved
+	usage, err := getLockUsage(ctx, qtx, lock.ID)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// validate the target for each kind
+	err = l.validateLockReassign(ctx, qtx, claims.UserName, lock, targetLock, usage)
+	if err != nil {
+		return LockUsage{}, err
+	}
+
+	// move
+	source, target := &lock.ID, &targetLock.ID
+	_, err = qtx.ReassignLockProblems(ctx, database.ReassignLockProblemsParams{
+		LockID:       source,
+		TargetLockID: target,
+	})
+	if err != nil {
+		return LockUsage{}, lockReassignError(lock.ID, targetLock.ID, "problems", err)
+	}
+	_, err = qtx.ReassignLockContests(ctx, database.ReassignLockContestsParams{
+		LockID:       source,
+		TargetLockID: target,
+	})
+	if err != nil {
+		return LockUsage{}, lockReassignError(lock.ID, targetLock.ID, "contests", err)
+	}
+	_, err = qtx.ReassignLockTournamentRounds(ctx, database.ReassignLockTournamentRoundsParams{
+		LockID:       source,
+		TargetLockID: target,
+	})
+	if err != nil {
+		return LockUsage{}, lockReassignError(lock.ID, targetLock.ID, "tournament rounds", err)
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit reassignment of lock %v to %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			targetLock.ID,
+			err,
+		)
+		log.Error(err)
+		return LockUsage{}, err
+	}
+
+	log.WithFields(log.Fields{
+		"lock_id":           lock.ID,
+		"target_lock_id":    targetLock.ID,
+		"user_name":         claims.UserName,
+		"problems":          len(usage.Problems),
+		"contests":          len(usage.Contests),
+		"tournament_rounds": len(usage.TournamentRounds),
+	}).Info("reassigned lock")
+
+	return usage, nil
+}
+
+func (l *LockService) validateLockReassign(
+	ctx context.Context,
+	qtx *database.Queries,
+	userName string,
+	lock FluxLock,
+	targetLock FluxLock,
+	usage LockUsage,
+) error {
+	// problems must stay hidden for a while, like when they are added
+	if len(usage.Problems) > 0 {
+		expired, err := l.IsLockExpired(targetLock, 5)
+		if err != nil {
+			return err
+		}
+		if expired {
+			return fmt.Errorf(
+				"%w, target lock's expiry must be atleast 5 mins from now to hold problems",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+	}
+
+	// public contests start with the timeout of their lock
+	if len(usage.Contests) > 0 {
+		err := l.UserServiceConfig.AuthorizePermission(
+			ctx,
+			user_service.PermContestManagePublic,
+			fmt.Sprintf(
+				"user %s tried to reassign public contests of lock %v",
+				userName,
+				lock.ID,
+			),
+		)
+		if err != nil {
+			return err
+		}
+		if targetLock.Type != database.LockTypeTimer {
+			return fmt.Errorf(
+				"%w, contests can only be reassigned to a timer lock",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+
+		// started contests cannot be moved
+		started, err := l.IsLockExpired(lock, 0)
+		if err != nil {
+			return err
+		}
+		if started {
+			return fmt.Errorf(
+				"%w, contests of the lock have already started",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+
+		expired, err := l.IsLockExpired(targetLock, 60*24)
+		if err != nil {
+			return err
+		}
+		if expired {
+			return fmt.Errorf(
+				"%w, target lock must have atleast one day of expiry to hold contests",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+
+		// public contests start with the target timeout, their registrations must fit it too
+		contests, err := qtx.GetLockContestsForUpdate(ctx, &lock.ID)
+		if err != nil {
+			return lockUsageError(lock.ID, "contests", err)
+		}
+		err = validateLockContestTimes(contests, *targetLock.Timeout, "the expiry of the target lock")
+		if err != nil {
+			return err
+		}
+	}
+
+	// rounds are released by hand
+	if len(usage.TournamentRounds) > 0 {
+		err := l.UserServiceConfig.AuthorizePermission(
+			ctx,
+			user_service.PermTournamentManage,
+			fmt.Sprintf(
+				"user %s tried to reassign tournament rounds of lock %v",
+				userName,
+				lock.ID,
+			),
+		)
+		if err != nil {
+			return err
+		}
+		if targetLock.Type != database.LockTypeManual {
+			return fmt.Errorf(
+				"%w, tournament rounds can only be reassigned to a manual lock",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+	}
+
+	return nil
+}
+
+func getLockUsage(
+	ctx context.Context,
+	db *database.Queries,
+	lockID uuid.UUID,
+) (LockUsage, error) {
+	usage := LockUsage{
+		Problems:         make([]LockedProblem, 0),
+		Contests:         make([]LockedContest, 0),
+		TournamentRounds: make([]LockedTournamentRound, 0),
+	}
+
+	problems, err := db.GetLockProblems(ctx, &lockID)
+	if err != nil {
+		return LockUsage{}, lockUsageError(lockID, "problems", err)
+	}
+	for _, problem := range problems {
+		usage.Problems = append(usage.Problems, LockedProblem{
+			ID:    problem.ID,
+			Title: problem.Title,
+		})
+	}
+
+	contests, err := db.GetLockContests(ctx, &lockID)
+	if err != nil {
+		return LockUsage{}, lockUsageError(lockID, "contests", err)
+	}
+	for _, contest := range contests {
+		usage.Contests = append(usage.Contests, LockedContest{
+			ID:      contest.ID,
+			Title:   contest.Title,
+			EndTime: contest.EndTime.UTC(),
+		})
+	}
+
+	rounds, err := db.GetLockTournamentRounds(ctx, &lockID)
+	if err != nil {
+		return LockUsage{}, lockUsageError(lockID, "tournament rounds", err)
+	}
+	for _, round := range rounds {
+		usage.TournamentRounds = append(usage.TournamentRounds, LockedTournamentRound{
+			ID:           round.ID,
+			TournamentID: round.TournamentID,
+			RoundNumber:  round.RoundNumber,
+			Title:        round.Title,
+		})
+	}
+
+	return usage, nil
+}
+
+func lockUsageError(lockID uuid.UUID, entities string, err error) error {
+	err = fmt.Errorf(
+		"%w, cannot fetch %s of lock with id %v, %w",
+		flux_errors.ErrInternal,
+		entities,
+		lockID,
+		err,
+	)
+	log.Error(err)
+	return err
+}
+
+func lockReassignError(lockID, targetLockID uuid.UUID, entities string, err error) error {
+	err = fmt.Errorf(
+		"%w, cannot reassign %s of lock %v to %v, %w",
+		flux_errors.ErrInternal,
+		entities,
+		lockID,
+		targetLockID,
+		err,
+	)
+	log.Error(err)
+	return err
+}
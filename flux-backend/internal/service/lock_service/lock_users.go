@@ -0,0 +1,137 @@
+package lock_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// GetLockAllowedUsers lists the users who can see the entities
+// of the lock without holding its access role
+func (l *LockService) GetLockAllowedUsers(
+	ctx context.Context,
+	lockID uuid.UUID,
+) ([]user_service.UserMetaData, error) {
+	// get the lock and authorize
+	lock, err := l.authorizeLockUsers(ctx, lockID, "view")
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch the users
+	dbUsers, err := l.DB.GetLockAllowedUsers(ctx, lock.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch allowed users of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	users := make([]user_service.UserMetaData, 0, len(dbUsers))
+	for _, dbUser := range dbUsers {
+		users = append(users, user_service.UserMetaData{
+			UserID:   dbUser.ID,
+			UserName: dbUser.UserName,
+			RollNo:   dbUser.RollNo,
+		})
+	}
+
+	return users, nil
+}
+
+// SetLockAllowedUsers replaces the allowed users of the lock.
+// Unlike UpdateLock it works for timer locks too, so the
+// setters of a contest can be added after its lock is created
+func (l *LockService) SetLockAllowedUsers(
+	ctx context.Context,
+	request LockUsersRequest,
+) ([]string, error) {
+	// validate request
+	err := service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the lock and authorize
+	lock, err := l.authorizeLockUsers(ctx, request.LockID, "update")
+	if err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	// replace the users
+	allowedUsers, err := setLockAllowedUsers(ctx, qtx, lock.ID, request.UserNames)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit allowed users of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	return allowedUsers, nil
+}
+
+func (l *LockService) authorizeLockUsers(
+	ctx context.Context,
+	lockID uuid.UUID,
+	action string,
+) (FluxLock, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, lockID)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		lock.CreatedBy,
+		user_service.PermLockEditAny,
+		fmt.Sprintf(
+			"user %s tried to %s allowed users of lock with id %v",
+			claims.UserName,
+			action,
+			lock.ID,
+		),
+	)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	return lock, nil
+}
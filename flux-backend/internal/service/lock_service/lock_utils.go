@@ -2,9 +2,13 @@ package lock_service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
@@ -75,8 +79,14 @@ func dbLockToServiceLock(dbLock database.Lock) FluxLock {
 		utc := (*dbLock.Timeout).UTC()
 		timeout = &utc
 	}
+	var releasedAt *time.Time
+	if dbLock.ReleasedAt != nil {
+		utc := (*dbLock.ReleasedAt).UTC()
+		releasedAt = &utc
+	}
 	return FluxLock{
 		Timeout:     timeout,
+		ReleasedAt:  releasedAt,
 		CreatedBy:   dbLock.CreatedBy,
 		CreatedAt:   dbLock.CreatedAt,
 		Name:        dbLock.Name,
@@ -91,8 +101,9 @@ func (l *LockService) IsLockExpired(
 	lock FluxLock,
 	delayMinutes int32,
 ) (bool, error) {
+	// a manual lock expires only when it is released
 	if lock.Type == database.LockTypeManual {
-		return false, nil
+		return lock.ReleasedAt != nil, nil
 	}
 
 	// very rare, but for safety purpose
@@ -111,11 +122,83 @@ func (l *LockService) IsLockExpired(
 	return false, nil
 }
 
+// AuthorizeLock allows everyone once the lock is expired, before that
+// only the holders of its access role and its allowed users
 func (l *LockService) AuthorizeLock(
 	ctx context.Context,
+	lockID uuid.UUID,
 	timeout *time.Time,
+	releasedAt *time.Time,
 	access user_service.UserRole,
 	warnMessage string,
+) error {
+	return l.authorizeLock(
+		ctx,
+		lockID,
+		timeout,
+		releasedAt,
+		access,
+		warnMessage,
+		func(userID uuid.UUID) (bool, error) {
+			return l.DB.IsLockUserAllowed(ctx, database.IsLockUserAllowedParams{
+				LockID: lockID,
+				UserID: userID,
+			})
+		},
+	)
+}
+
+// LockAuthorizer authorizes many locks for the user of one request,
+// the locks the user is allowed in are fetched once when first needed
+type LockAuthorizer struct {
+	l   *LockService
+	ctx context.Context
+	// nil until fetched
+	allowedLocks map[uuid.UUID]bool
+}
+
+func (l *LockService) NewLockAuthorizer(ctx context.Context) *LockAuthorizer {
+	return &LockAuthorizer{l: l, ctx: ctx}
+}
+
+// AuthorizeLock is the same as LockService.AuthorizeLock without the warning
+func (a *LockAuthorizer) AuthorizeLock(
+	lockID uuid.UUID,
+	timeout *time.Time,
+	releasedAt *time.Time,
+	access user_service.UserRole,
+) error {
+	return a.l.authorizeLock(
+		a.ctx,
+		lockID,
+		timeout,
+		releasedAt,
+		access,
+		"",
+		func(userID uuid.UUID) (bool, error) {
+			if a.allowedLocks == nil {
+				lockIDs, err := a.l.DB.GetUserAllowedLockIDs(a.ctx, userID)
+				if err != nil {
+					return false, err
+				}
+				a.allowedLocks = make(map[uuid.UUID]bool, len(lockIDs))
+				for _, id := range lockIDs {
+					a.allowedLocks[id] = true
+				}
+			}
+			return a.allowedLocks[lockID], nil
+		},
+	)
+}
+
+func (l *LockService) authorizeLock(
+	ctx context.Context,
+	lockID uuid.UUID,
+	timeout *time.Time,
+	releasedAt *time.Time,
+	access user_service.UserRole,
+	warnMessage string,
+	isUserAllowed func(userID uuid.UUID) (bool, error),
 ) error {
 	// timer lock expired
 	if timeout != nil {
@@ -124,12 +207,130 @@ func (l *LockService) AuthorizeLock(
 		}
 	}
 
+	// manual lock released
+	if releasedAt != nil {
+		return nil
+	}
+
 	// authorize
-	err := l.UserServiceConfig.AuthorizeUserRole(
-		ctx,
-		access,
-		warnMessage,
-	)
+	err := l.UserServiceConfig.AuthorizeUserRole(ctx, access, "")
+	if !errors.Is(err, flux_errors.ErrUnAuthorized) {
+		return err
+	}
+
+	// the user might be allowed without the role
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	allowed, err := isUserAllowed(claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check allowed users of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lockID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if allowed {
+		return nil
+	}
+
+	// warn
+	if warnMessage != "" {
+		log.Warn(warnMessage)
+	}
+
+	return flux_errors.ErrUnAuthorized
+}
+
+// validateLockAccess defaults the access of the lock and
+// checks that its role exists
+func (l *LockService) validateLockAccess(
+	ctx context.Context,
+	access *user_service.UserRole,
+) error {
+	if *access == "" {
+		*access = user_service.RoleManager
+		return nil
+	}
+
+	exists, err := l.DB.RoleExists(ctx, string(*access))
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check if role %s exists, %w",
+			flux_errors.ErrInternal,
+			*access,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if !exists {
+		return fmt.Errorf(
+			"%w, role %s does not exist",
+			flux_errors.ErrInvalidRequest,
+			*access,
+		)
+	}
+
+	return nil
+}
+
+// setLockAllowedUsers replaces the allowed users of the lock,
+// returns their user names without duplicates
+func setLockAllowedUsers(
+	ctx context.Context,
+	qtx *database.Queries,
+	lockID uuid.UUID,
+	userNames []string,
+) ([]string, error) {
+	uniqueNames := make([]string, 0, len(userNames))
+	for _, userName := range userNames {
+		if !slices.Contains(uniqueNames, userName) {
+			uniqueNames = append(uniqueNames, userName)
+		}
+	}
+
+	err := qtx.DeleteLockAllowedUsers(ctx, lockID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot delete allowed users of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lockID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+	if len(uniqueNames) == 0 {
+		return uniqueNames, nil
+	}
+
+	n, err := qtx.AddLockAllowedUsers(ctx, database.AddLockAllowedUsersParams{
+		LockID:    lockID,
+		UserNames: uniqueNames,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot add allowed users of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lockID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// users are matched by name, a missing one is simply not inserted
+	if n != int64(len(uniqueNames)) {
+		return nil, fmt.Errorf(
+			"%w, some of the allowed users do not exist",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
 
-	return err
+	return uniqueNames, nil
 }
@@ -11,17 +11,36 @@ import (
 type LockService struct {
 	DB                *database.Queries
 	UserServiceConfig *user_service.UserService
+	// nil when no scheduler is running
+	Scheduler *LockScheduler
 }
 
+// Access is the role that can see the locked entities before the expiry
+// (role_manager by default), AllowedUsers can see them without it
 type FluxLock struct {
-	ID          uuid.UUID             `json:"lock_id"`
-	Name        string                `json:"name" validate:"min=4"`
-	CreatedBy   uuid.UUID             `json:"created_by"`
-	Type        database.LockType     `json:"lock_type" validate:"oneof=timer manual"`
-	CreatedAt   time.Time             `json:"created_at"`
-	Timeout     *time.Time            `json:"timeout"`
-	Description string                `json:"description"`
-	Access      user_service.UserRole `json:"-"`
+	ID           uuid.UUID             `json:"lock_id"`
+	Name         string                `json:"name" validate:"min=4"`
+	CreatedBy    uuid.UUID             `json:"created_by"`
+	Type         database.LockType     `json:"lock_type" validate:"oneof=timer manual"`
+	CreatedAt    time.Time             `json:"created_at"`
+	Timeout      *time.Time            `json:"timeout"`
+	ReleasedAt   *time.Time            `json:"released_at"`
+	Description  string                `json:"description"`
+	Access       user_service.UserRole `json:"access" validate:"max=50"`
+	AllowedUsers []string              `json:"allowed_users,omitempty" validate:"max=100"`
+}
+
+// UpdateLockRequest changes the details of a lock, the access and the
+// allowed users are kept unless they are sent. The timeout of a timer
+// lock is moved with a reschedule
+type UpdateLockRequest struct {
+	ID           uuid.UUID              `json:"lock_id" validate:"required"`
+	Name         string                 `json:"name" validate:"min=4"`
+	Type         database.LockType      `json:"lock_type" validate:"oneof=timer manual"`
+	Timeout      *time.Time             `json:"timeout"`
+	Description  string                 `json:"description"`
+	Access       *user_service.UserRole `json:"access" validate:"omitempty,max=50"`
+	AllowedUsers *[]string              `json:"allowed_users" validate:"omitempty,max=100"`
 }
 
 type GetLocksRequest struct {
@@ -31,3 +50,58 @@ type GetLocksRequest struct {
 	PageNumber      int32   `json:"page_number" validate:"min=1,numeric"`
 	PageSize        int32   `json:"page_size" validate:"min=1,max=100,numeric"`
 }
+
+type LockActionRequest struct {
+	LockID uuid.UUID `json:"lock_id" validate:"required"`
+	Note   string    `json:"note" validate:"max=500"`
+}
+
+type LockAuditLog struct {
+	ID          uuid.UUID           `json:"id"`
+	Action      database.LockAction `json:"action"`
+	PerformedBy string              `json:"performed_by"`
+	PerformedAt time.Time           `json:"performed_at"`
+	Note        string              `json:"note"`
+}
+
+// replaces the allowed users of the lock
+type LockUsersRequest struct {
+	LockID    uuid.UUID `json:"lock_id" validate:"required"`
+	UserNames []string  `json:"user_names" validate:"max=100"`
+}
+
+// everything protected by a lock
+type LockUsage struct {
+	Problems         []LockedProblem         `json:"problems"`
+	Contests         []LockedContest         `json:"contests"`
+	TournamentRounds []LockedTournamentRound `json:"tournament_rounds"`
+}
+
+type LockedProblem struct {
+	ID    int32  `json:"problem_id"`
+	Title string `json:"title"`
+}
+
+type LockedContest struct {
+	ID      uuid.UUID `json:"contest_id"`
+	Title   string    `json:"title"`
+	EndTime time.Time `json:"end_time"`
+}
+
+type LockedTournamentRound struct {
+	ID           uuid.UUID `json:"round_id"`
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_number"`
+	Title        string    `json:"title"`
+}
+
+// moves everything protected by lock_id to target_lock_id
+type ReassignLockRequest struct {
+	LockID       uuid.UUID `json:"lock_id" validate:"required"`
+	TargetLockID uuid.UUID `json:"target_lock_id" validate:"required"`
+}
+
+type RescheduleLockRequest struct {
+	LockID  uuid.UUID  `json:"lock_id" validate:"required"`
+	Timeout *time.Time `json:"timeout" validate:"required"`
+}
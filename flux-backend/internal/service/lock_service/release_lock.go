@@ -0,0 +1,235 @@
+package lock_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// ReleaseLock opens a manual lock to everyone, until it is engaged again
+func (l *LockService) ReleaseLock(
+	ctx context.Context,
+	request LockActionRequest,
+) (FluxLock, error) {
+	lock, err := l.changeManualLock(ctx, request, database.LockActionRelease)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// manual releases reach the same subscribers as the timer ones
+	if l.Scheduler != nil {
+		l.Scheduler.publish(ctx, LockReleasedEvent{
+			LockID:     lock.ID,
+			Name:       lock.Name,
+			ReleasedAt: *lock.ReleasedAt,
+		})
+	}
+
+	return lock, nil
+}
+
+// EngageLock restricts a released manual lock to its access role again
+func (l *LockService) EngageLock(
+	ctx context.Context,
+	request LockActionRequest,
+) (FluxLock, error) {
+	return l.changeManualLock(ctx, request, database.LockActionEngage)
+}
+
+func (l *LockService) GetLockAuditLogs(
+	ctx context.Context,
+	lockID uuid.UUID,
+) ([]LockAuditLog, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, lockID)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		lock.CreatedBy,
+		user_service.PermLockEditAny,
+		fmt.Sprintf(
+			"user %s tried to view audit logs of lock with id %v",
+			claims.UserName,
+			lock.ID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch the logs
+	dbLogs, err := l.DB.GetLockAuditLogs(ctx, &lockID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch audit logs of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lockID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	logs := make([]LockAuditLog, 0, len(dbLogs))
+	for _, dbLog := range dbLogs {
+		logs = append(logs, LockAuditLog{
+			ID:          dbLog.ID,
+			Action:      dbLog.Action,
+			PerformedBy: dbLog.PerformedBy,
+			PerformedAt: dbLog.PerformedAt.UTC(),
+			Note:        dbLog.Note,
+		})
+	}
+
+	return logs, nil
+}
+
+// changeManualLock releases or engages the lock and records who did it
+func (l *LockService) changeManualLock(
+	ctx context.Context,
+	request LockActionRequest,
+	action database.LockAction,
+) (FluxLock, error) {
+	// validate request
+	err := service.ValidateInput(request)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, request.LockID)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		lock.CreatedBy,
+		user_service.PermLockEditAny,
+		fmt.Sprintf(
+			"user %s tried to %s lock with id %v",
+			claims.UserName,
+			action,
+			lock.ID,
+		),
+	)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// timer locks are released only by their timeout
+	if lock.Type != database.LockTypeManual {
+		return FluxLock{}, fmt.Errorf(
+			"%w, only manual locks can be released or engaged",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	// change the state, it matches only if the lock is not in it already
+	var dbLock database.Lock
+	if action == database.LockActionRelease {
+		dbLock, err = qtx.ReleaseManualLock(ctx, lock.ID)
+	} else {
+		dbLock, err = qtx.EngageManualLock(ctx, lock.ID)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			state := "released"
+			if action == database.LockActionEngage {
+				state = "engaged"
+			}
+			return FluxLock{}, fmt.Errorf(
+				"%w, lock is already %s",
+				flux_errors.ErrInvalidRequest,
+				state,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot %s lock with id %v, %w",
+			flux_errors.ErrInternal,
+			action,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	// record the action
+	_, err = qtx.CreateLockAuditLog(ctx, database.CreateLockAuditLogParams{
+		LockID:      &lock.ID,
+		LockName:    lock.Name,
+		Action:      action,
+		PerformedBy: claims.UserId,
+		Note:        request.Note,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot record %s of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			action,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit %s of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			action,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	log.WithFields(log.Fields{
+		"lock_id":   lock.ID,
+		"user_name": claims.UserName,
+		"action":    action,
+	}).Info("manual lock changed")
+
+	return dbLockToServiceLock(dbLock), nil
+}
@@ -0,0 +1,250 @@
+package lock_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/email"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+const (
+	// same margin as the creation of a public contest
+	lockContestEndMargin = 5 * time.Minute
+)
+
+// RescheduleLock moves the timeout of a timer lock that has not expired.
+// Public contests start with the timeout of their lock, so each of them must
+// still end after the new timeout and close its registrations before it,
+// and their registered users are notified
+func (l *LockService) RescheduleLock(
+	ctx context.Context,
+	request RescheduleLockRequest,
+) (FluxLock, error) {
+	// validate request
+	err := service.ValidateInput(request)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// authorize
+	err = l.UserServiceConfig.AuthorizePermission(
+		ctx,
+		user_service.PermLockReschedule,
+		fmt.Sprintf(
+			"user %s tried to reschedule lock with id %v",
+			claims.UserName,
+			request.LockID,
+		),
+	)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// get the lock
+	lock, err := l.GetLockById(ctx, request.LockID)
+	if err != nil {
+		return FluxLock{}, err
+	}
+	if lock.Type != database.LockTypeTimer {
+		return FluxLock{}, fmt.Errorf(
+			"%w, only timer locks can be rescheduled",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// validate the new timeout
+	rescheduledLock := lock
+	rescheduledLock.Timeout = request.Timeout
+	if err = validateTimerLockTimeout(rescheduledLock); err != nil {
+		return FluxLock{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
+
+	// reschedule, it matches only if the lock has not expired meanwhile
+	dbLock, err := qtx.RescheduleTimerLock(ctx, database.RescheduleTimerLockParams{
+		ID:      lock.ID,
+		Timeout: request.Timeout,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FluxLock{}, fmt.Errorf(
+				"%w, lock has already expired",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot reschedule lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	// the contests are locked until commit, so their times cannot change meanwhile
+	contests, err := qtx.GetLockContestsForUpdate(ctx, &lock.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch contests of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+	err = validateLockContestTimes(contests, *request.Timeout, "the new timeout of the lock")
+	if err != nil {
+		return FluxLock{}, err
+	}
+
+	// the release must be published again at the new timeout
+	err = qtx.DeleteLockReleaseEvent(ctx, lock.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot reset release of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	// fetch whom to notify
+	recipients, err := qtx.GetLockContestRecipients(ctx, &lock.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch registered users of contests of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit reschedule of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return FluxLock{}, err
+	}
+
+	res := dbLockToServiceLock(dbLock)
+	log.WithFields(log.Fields{
+		"lock_id":     lock.ID,
+		"user_name":   claims.UserName,
+		"old_timeout": lock.Timeout,
+		"new_timeout": res.Timeout,
+		"contests":    len(contests),
+	}).Info("rescheduled lock")
+
+	// the old timer must not fire
+	if l.Scheduler != nil {
+		l.Scheduler.Schedule(res)
+	}
+
+	// the mails are queued in the background, the reschedule is already done
+	go notifyContestReschedule(*lock.Timeout, *res.Timeout, recipients)
+
+	return res, nil
+}
+
+func notifyContestReschedule(
+	oldTimeout time.Time,
+	newTimeout time.Time,
+	recipients []database.GetLockContestRecipientsRow,
+) {
+	failed := mailLockContests(
+		recipients,
+		email.PurposeContestRescheduled,
+		func(title string) (string, string) {
+			return fmt.Sprintf("Contest %s has been rescheduled", title),
+				fmt.Sprintf(
+					"contest %s will now start at %s instead of %s.",
+					title,
+					newTimeout.UTC().Format(time.RFC1123),
+					oldTimeout.UTC().Format(time.RFC1123),
+				)
+		},
+	)
+
+	if failed > 0 {
+		log.Errorf(
+			"cannot notify %d of %d users about a contest reschedule",
+			failed,
+			len(recipients),
+		)
+	}
+}
+
+// validateLockContestTimes checks that the public contests of a lock still
+// fit once they start at timeout, timeoutName names it in the errors
+func validateLockContestTimes(
+	contests []database.GetLockContestsForUpdateRow,
+	timeout time.Time,
+	timeoutName string,
+) error {
+	for _, contest := range contests {
+		if timeout.Add(lockContestEndMargin).After(contest.EndTime) {
+			return fmt.Errorf(
+				"%w, contest %s must end atleast 5 minutes after %s",
+				flux_errors.ErrInvalidRequest,
+				contest.Title,
+				timeoutName,
+			)
+		}
+
+		// registrations close once the contest starts
+		if contest.RegistrationStart != nil && !contest.RegistrationStart.Before(timeout) {
+			return fmt.Errorf(
+				"%w, registration of contest %s must start before %s",
+				flux_errors.ErrInvalidRequest,
+				contest.Title,
+				timeoutName,
+			)
+		}
+		if contest.RegistrationEnd != nil && contest.RegistrationEnd.After(timeout) {
+			return fmt.Errorf(
+				"%w, registration of contest %s must end before %s",
+				flux_errors.ErrInvalidRequest,
+				contest.Title,
+				timeoutName,
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,219 @@
+package lock_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+)
+
+const (
+	// pending locks are read again periodically to pick up
+	// the locks created or changed by other instances
+	DefaultSchedulerResyncInterval = 5 * time.Minute
+	lateReleaseThreshold           = time.Minute
+	// a claim not published within this time is taken as lost with its
+	// instance, it is longer than the resync so a live claim is not taken
+	releaseClaimTimeout = 2 * DefaultSchedulerResyncInterval
+)
+
+// LockReleasedEvent is published when the timeout of a timer lock passes
+// or when a manual lock is released
+type LockReleasedEvent struct {
+	LockID uuid.UUID
+	Name   string
+	// zero for manual locks
+	Timeout    time.Time
+	ReleasedAt time.Time
+	// set when the event is published well after the timeout,
+	// e.g. the lock expired while no scheduler was running
+	Late bool
+}
+
+// LockReleasedHandler must not block for long, every handler of an event
+// runs one after the other in the goroutine of the event.
+// A timer release is delivered at least once, so a handler must tolerate
+// seeing the same event again after an instance crashed while publishing it
+type LockReleasedHandler func(ctx context.Context, event LockReleasedEvent)
+
+// LockScheduler watches the timeouts of the timer locks and publishes
+// an in-process event to the subscribers once a lock is released.
+// An event is claimed in the db before publishing, so with several
+// instances it is published only by the instance that claims it.
+// It is marked published after the handlers ran, until then the
+// resync schedules it again and a stale claim is taken over.
+// Manual releases are published by the request releasing the lock
+// and are not recorded, so they are delivered at most once
+type LockScheduler struct {
+	DB             *database.Queries
+	ResyncInterval time.Duration
+
+	mu          sync.Mutex
+	subscribers []LockReleasedHandler
+	timers      map[uuid.UUID]*time.Timer
+}
+
+func NewLockScheduler(db *database.Queries) *LockScheduler {
+	return &LockScheduler{
+		DB:             db,
+		ResyncInterval: DefaultSchedulerResyncInterval,
+		timers:         make(map[uuid.UUID]*time.Timer),
+	}
+}
+
+// Subscribe registers a handler for every lock released after it
+func (s *LockScheduler) Subscribe(handler LockReleasedHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, handler)
+}
+
+// Start schedules the pending locks of the db and keeps them in sync until ctx is done
+func (s *LockScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.ResyncInterval)
+		defer ticker.Stop()
+		for {
+			s.resync(ctx)
+			select {
+			case <-ctx.Done():
+				s.stopTimers()
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Schedule sets the timer of a timer lock, a lock scheduled earlier is replaced
+func (s *LockScheduler) Schedule(lock FluxLock) {
+	if lock.Type != database.LockTypeTimer || lock.Timeout == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timer, ok := s.timers[lock.ID]; ok {
+		timer.Stop()
+	}
+	lockID := lock.ID
+	s.timers[lockID] = time.AfterFunc(time.Until(*lock.Timeout), func() {
+		s.release(lockID)
+	})
+}
+
+// Unschedule stops the timer of the lock
+func (s *LockScheduler) Unschedule(lockID uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if timer, ok := s.timers[lockID]; ok {
+		timer.Stop()
+		delete(s.timers, lockID)
+	}
+}
+
+func (s *LockScheduler) resync(ctx context.Context) {
+	dbLocks, err := s.DB.GetPendingTimerLocks(ctx)
+	if err != nil {
+		log.Errorf("cannot fetch pending timer locks, %v", err)
+		return
+	}
+
+	// locks released by other instances are skipped when their timer fires
+	for _, dbLock := range dbLocks {
+		s.Schedule(dbLockToServiceLock(dbLock))
+	}
+
+	log.Debugf("lock scheduler is watching %d timer locks", len(dbLocks))
+}
+
+// release claims the event of the lock and publishes it
+func (s *LockScheduler) release(lockID uuid.UUID) {
+	ctx := context.Background()
+	s.mu.Lock()
+	delete(s.timers, lockID)
+	s.mu.Unlock()
+
+	// the timeout might have been changed since it was scheduled
+	dbLock, err := s.DB.GetLockById(ctx, lockID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Errorf("cannot fetch released lock %v, %v", lockID, err)
+		}
+		return
+	}
+	lock := dbLockToServiceLock(dbLock)
+	if lock.Timeout == nil {
+		return
+	}
+	if time.Now().Before(*lock.Timeout) {
+		s.Schedule(lock)
+		return
+	}
+
+	n, err := s.DB.ClaimLockRelease(ctx, database.ClaimLockReleaseParams{
+		ID:          lockID,
+		StaleBefore: time.Now().Add(-releaseClaimTimeout),
+	})
+	if err != nil {
+		log.Errorf("cannot claim release of lock %v, %v", lockID, err)
+		return
+	}
+	if n == 0 {
+		// published or being published by another instance
+		return
+	}
+
+	event := LockReleasedEvent{
+		LockID:     lock.ID,
+		Name:       lock.Name,
+		Timeout:    *lock.Timeout,
+		ReleasedAt: time.Now(),
+	}
+	event.Late = event.ReleasedAt.Sub(event.Timeout) > lateReleaseThreshold
+	s.publish(ctx, event)
+
+	// a crash before this point leaves the claim to be taken again
+	err = s.DB.MarkLockReleasePublished(ctx, lockID)
+	if err != nil {
+		log.Errorf("cannot mark release of lock %v as published, %v", lockID, err)
+	}
+}
+
+func (s *LockScheduler) publish(ctx context.Context, event LockReleasedEvent) {
+	s.mu.Lock()
+	subscribers := append([]LockReleasedHandler(nil), s.subscribers...)
+	s.mu.Unlock()
+
+	log.WithFields(log.Fields{
+		"lock_id": event.LockID,
+		"name":    event.Name,
+		"late":    event.Late,
+	}).Info("lock released")
+
+	for _, handler := range subscribers {
+		func() {
+			// a failing subscriber must not stop the others
+			defer func() {
+				if r := recover(); r != nil {
+					log.Errorf("lock released handler panicked for lock %v, %v", event.LockID, r)
+				}
+			}()
+			handler(ctx, event)
+		}()
+	}
+}
+
+func (s *LockScheduler) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for lockID, timer := range s.timers {
+		timer.Stop()
+		delete(s.timers, lockID)
+	}
+}
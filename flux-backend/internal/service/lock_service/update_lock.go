@@ -4,14 +4,16 @@ import (
 	"context"
 	"fmt"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (l *LockService) UpdateLock(
 	ctx context.Context,
-	lock FluxLock,
+	lock UpdateLockRequest,
 ) (res FluxLock, err error) {
 	// get the user details from claims
 	claims, err := service.GetClaimsFromContext(ctx)
@@ -29,6 +31,7 @@ func (l *LockService) UpdateLock(
 	err = l.UserServiceConfig.AuthorizeCreatorAccess(
 		ctx,
 		previousLock.CreatedBy,
+		user_service.PermLockEditAny,
 		fmt.Sprintf(
 			"user %s tried to update lock with id %v",
 			claims.UserName,
@@ -44,15 +47,33 @@ func (l *LockService) UpdateLock(
 	if err != nil {
 		return
 	}
+	if lock.Access != nil {
+		err = l.validateLockAccess(ctx, lock.Access)
+		if err != nil {
+			return
+		}
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := l.DB.WithTx(tx)
 
 	// update the lock
-	dbLock, err := l.DB.UpdateLockDetails(
+	dbLock, err := qtx.UpdateLockDetails(
 		ctx,
 		database.UpdateLockDetailsParams{
-			Timeout:     lock.Timeout,
 			Description: lock.Description,
 			Name:        lock.Name,
 			ID:          lock.ID,
+			Access:      (*string)(lock.Access),
 		},
 	)
 	if err != nil {
@@ -65,26 +86,67 @@ func (l *LockService) UpdateLock(
 		return
 	}
 
-	return dbLockToServiceLock(dbLock), nil
+	// the allowed users are replaced only when they are sent
+	var allowedUsers []string
+	if lock.AllowedUsers != nil {
+		allowedUsers, err = setLockAllowedUsers(ctx, qtx, lock.ID, *lock.AllowedUsers)
+		if err != nil {
+			return
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit update of lock with id %v, %w",
+			flux_errors.ErrInternal,
+			lock.ID,
+			err,
+		)
+		log.Error(err)
+		return
+	}
+
+	res = dbLockToServiceLock(dbLock)
+	res.AllowedUsers = allowedUsers
+	return res, nil
 }
 
 func (l *LockService) validateLockUpdate(
 	previousLock FluxLock,
-	newLock FluxLock,
+	request UpdateLockRequest,
 ) error {
-	if previousLock.Type != newLock.Type {
+	// raw validation
+	err := service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+
+	if previousLock.Type != request.Type {
 		return fmt.Errorf(
 			"%w, cannot change lock's type once created",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
 
-	if previousLock.Type == database.LockTypeTimer {
+	if request.Timeout == nil {
+		return nil
+	}
+
+	if previousLock.Type == database.LockTypeManual {
+		return fmt.Errorf(
+			"%w, manual lock cannot have a timer",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// the other details of a timer lock can still be changed
+	if previousLock.Timeout == nil || !request.Timeout.Equal(*previousLock.Timeout) {
 		return fmt.Errorf(
-			"%w, cannot update a timer lock",
+			"%w, cannot change the timeout of a timer lock, use reschedule to move it",
 			flux_errors.ErrInvalidRequest,
 		)
 	}
 
-	return validateManualLock(newLock)
+	return nil
 }
@@ -6,7 +6,8 @@ import (
 )
 
 type UserCredentialClaims struct {
-	UserId   uuid.UUID `json:"user_id"`
-	UserName string    `json:"user_name"`
+	UserId    uuid.UUID `json:"user_id"`
+	UserName  string    `json:"user_name"`
+	SessionID uuid.UUID `json:"session_id"`
 	jwt.RegisteredClaims
 }
@@ -25,8 +25,8 @@ func (p *ProblemService) AddProblem(
 	}
 
 	// authorize (only managers can add problems)
-	err = p.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
+	err = p.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermProblemCreate,
 		fmt.Sprintf(
 			"user %s tried for manager access to add a problem",
 			claims.UserName,
@@ -130,5 +130,9 @@ func getAddProblemParams(
 		SubmissionLink:   problem.SubmissionLink,
 		Platform:         dbProblemData.platformType,
 		LockID:           problem.LockId,
+		CheckerMode:      dbProblemData.checkerMode,
+		CheckerEpsilon:   dbProblemData.checkerEpsilon,
+		CheckerLanguage:  dbProblemData.checkerLanguage,
+		CheckerSource:    dbProblemData.checkerSource,
 	}, nil
 }
@@ -46,7 +46,9 @@ func (p *ProblemService) GetProblemById(
 	if dbProblem.LockAccess != nil {
 		err = p.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*dbProblem.LockID,
 			dbProblem.LockTimeout,
+			dbProblem.LockReleasedAt,
 			user_service.UserRole(*dbProblem.LockAccess),
 			fmt.Sprintf(
 				"user %s tried to access unauthorized problem with id %v",
@@ -75,7 +77,7 @@ func (p *ProblemService) GetProblemById(
 		return Problem{}, err
 	}
 
-	return Problem{
+	problem := Problem{
 		ID:             dbProblem.ID,
 		Title:          dbProblem.Title,
 		Statement:      dbProblem.Statement,
@@ -91,7 +93,26 @@ func (p *ProblemService) GetProblemById(
 		ExampleTCs:     serviceProbData.exampleTestCases,
 		Platform:       serviceProbData.platformType,
 		LockId:         dbProblem.LockID,
-	}, nil
+		Checker: dbCheckerToServiceChecker(
+			dbProblem.CheckerMode,
+			dbProblem.CheckerEpsilon,
+			dbProblem.CheckerLanguage,
+			dbProblem.CheckerSource,
+		),
+	}
+
+	// a custom checker may reveal the answers, only its author (or hc) sees it
+	err = p.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		problem.CreatedBy,
+		user_service.PermProblemEditAny,
+		"",
+	)
+	if err != nil {
+		problem.Checker.Source = nil
+	}
+
+	return problem, nil
 }
 
 func (p *ProblemService) GetProblemsByFilters(
@@ -142,19 +163,21 @@ func (p *ProblemService) GetProblemsByFilters(
 	}
 
 	// convert to meta data
+	authorizer := p.LockServiceConfig.NewLockAuthorizer(ctx)
 	res := make(map[int32]ProblemMetaData)
 	for _, row := range rows {
 		// authorize user for the problem
 		// only a handful of people are assigned roles and
-		// once fetched they are stored in cache, so better loop and authorize
-		// instead of filtering them in the complex sql query
+		// once fetched they are stored in cache, the allowed locks
+		// of the user are fetched once by the authorizer, so better loop
+		// and authorize instead of filtering them in the complex sql query
 		var lockAccess *user_service.UserRole
 		if row.LockAccess != nil {
-			err := p.LockServiceConfig.AuthorizeLock(
-				ctx,
+			err := authorizer.AuthorizeLock(
+				*row.LockID,
 				row.LockTimeout,
+				row.LockReleasedAt,
 				user_service.UserRole(*row.LockAccess),
-				"",
 			)
 			if err != nil {
 				continue
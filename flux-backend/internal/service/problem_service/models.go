@@ -5,17 +5,27 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/blob_store"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 type Platform string
+type CheckerMode string
+
+const (
+	CheckerExact  CheckerMode = "exact"
+	CheckerToken  CheckerMode = "token"
+	CheckerFloat  CheckerMode = "float"
+	CheckerCustom CheckerMode = "custom"
+)
 
 type ProblemService struct {
 	DB                *database.Queries
 	UserServiceConfig *user_service.UserService
 	LockServiceConfig *lock_service.LockService
+	TestCaseStore     blob_store.Store
 }
 
 type ExampleTestCase struct {
@@ -28,6 +38,15 @@ type ExampleTestCases struct {
 	Examples     []ExampleTestCase `json:"examples"`
 }
 
+// how the judge compares outputs, a nil checker means exact
+// when creating and keeps the current checker when updating
+type Checker struct {
+	Mode     CheckerMode `json:"mode" validate:"required,oneof=exact token float custom"`
+	Epsilon  *float64    `json:"epsilon" validate:"omitempty,gt=0,lt=1"`
+	Language *string     `json:"language" validate:"omitempty,oneof=c cpp java python3 go"`
+	Source   *string     `json:"source" validate:"omitempty,max=65536"`
+}
+
 type Problem struct {
 	ID             int32             `json:"id"`
 	Title          string            `json:"title" validate:"required,max=100"`
@@ -44,12 +63,17 @@ type Problem struct {
 	CreatedBy      uuid.UUID         `json:"created_by"`
 	LastUpdatedBy  uuid.UUID         `json:"last_updated_by"`
 	LockId         *uuid.UUID        `json:"lock_id"`
+	Checker        *Checker          `json:"checker"`
 }
 
 // helper struct for converting service problem data to db problem data
 type dbProblemData struct {
 	exampleTestCases *json.RawMessage
 	platformType     database.NullPlatform
+	checkerMode      database.CheckerMode
+	checkerEpsilon   *float64
+	checkerLanguage  *string
+	checkerSource    *string
 }
 
 // helper struct for converting db problem data to service problem data
@@ -89,3 +113,42 @@ type ProblemMetaData struct {
 	LockTimeout *time.Time             `json:"-"`
 	LockAccess  *user_service.UserRole `json:"-"`
 }
+
+// hidden test case of a problem, the data itself lives in the blob store
+type TestCase struct {
+	ID           uuid.UUID `json:"test_case_id"`
+	ProblemID    int32     `json:"problem_id"`
+	Position     int32     `json:"position"`
+	Group        *string   `json:"group"`
+	Points       int32     `json:"points"`
+	InputSize    int64     `json:"input_size"`
+	OutputSize   int64     `json:"output_size"`
+	InputSha256  string    `json:"input_sha256"`
+	OutputSha256 string    `json:"output_sha256"`
+	CreatedBy    uuid.UUID `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// dto for uploading a test case, input and output are read from the multipart form
+type UploadTestCaseRequest struct {
+	ProblemID int32   `json:"problem_id" validate:"required"`
+	Group     *string `json:"group" validate:"omitempty,min=1,max=50"`
+	Points    int32   `json:"points" validate:"min=0"`
+	Input     []byte  `json:"-"`
+	Output    []byte  `json:"-"`
+}
+
+// dto for reordering, must list every test case of the problem exactly once
+type ReorderTestCasesRequest struct {
+	ProblemID   int32       `json:"problem_id" validate:"required"`
+	TestCaseIDs []uuid.UUID `json:"test_case_ids" validate:"required,min=1"`
+}
+
+// test data handed to the judge
+type TestCaseData struct {
+	Position int32
+	Group    *string
+	Points   int32
+	Input    []byte
+	Output   []byte
+}
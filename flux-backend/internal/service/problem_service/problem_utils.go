@@ -68,6 +68,14 @@ func (p *ProblemService) validateProblem(
 		return fmt.Errorf("%w, submission link is provided but platform is not provided", flux_errors.ErrInvalidRequest)
 	}
 
+	// validate checker
+	if problem.Checker != nil {
+		err = validateChecker(problem)
+		if err != nil {
+			return err
+		}
+	}
+
 	// lock has different validations for different purposes
 
 	return nil
@@ -99,10 +107,77 @@ func getDBProblemDataFromProblem(problem Problem) (dbProblemData, error) {
 		platformType.Platform = database.Platform(*problem.Platform)
 	}
 
-	return dbProblemData{
+	// default to exact
+	data := dbProblemData{
 		exampleTestCases: exampleTestCases,
 		platformType:     platformType,
-	}, nil
+		checkerMode:      database.CheckerModeExact,
+	}
+	if problem.Checker != nil {
+		data.checkerMode = database.CheckerMode(problem.Checker.Mode)
+		data.checkerEpsilon = problem.Checker.Epsilon
+		data.checkerLanguage = problem.Checker.Language
+		data.checkerSource = problem.Checker.Source
+	}
+
+	return data, nil
+}
+
+func validateChecker(problem Problem) error {
+	checker := problem.Checker
+
+	// external platforms judge with their own checker
+	if problem.Platform != nil && checker.Mode != CheckerExact {
+		return fmt.Errorf(
+			"%w, checker cannot be set for problems judged on %s",
+			flux_errors.ErrInvalidRequest,
+			*problem.Platform,
+		)
+	}
+
+	// epsilon only for float
+	if checker.Mode == CheckerFloat && checker.Epsilon == nil {
+		return fmt.Errorf(
+			"%w, float checker requires epsilon",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if checker.Mode != CheckerFloat && checker.Epsilon != nil {
+		return fmt.Errorf(
+			"%w, epsilon is only used by the float checker",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// program only for custom
+	if checker.Mode == CheckerCustom && (checker.Source == nil || checker.Language == nil) {
+		return fmt.Errorf(
+			"%w, custom checker requires source and language",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if checker.Mode != CheckerCustom && (checker.Source != nil || checker.Language != nil) {
+		return fmt.Errorf(
+			"%w, source and language are only used by the custom checker",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func dbCheckerToServiceChecker(
+	mode database.CheckerMode,
+	epsilon *float64,
+	language *string,
+	source *string,
+) *Checker {
+	return &Checker{
+		Mode:     CheckerMode(mode),
+		Epsilon:  epsilon,
+		Language: language,
+		Source:   source,
+	}
 }
 
 func getServiceProblemData(
@@ -166,6 +241,12 @@ func dbProblemToServiceProblem(
 		ExampleTCs:     serviceProbData.exampleTestCases,
 		Platform:       serviceProbData.platformType,
 		LockId:         dbProblem.LockID,
+		Checker: dbCheckerToServiceChecker(
+			dbProblem.CheckerMode,
+			dbProblem.CheckerEpsilon,
+			dbProblem.CheckerLanguage,
+			dbProblem.CheckerSource,
+		),
 	}, nil
 }
 
@@ -199,7 +280,9 @@ func (p *ProblemService) AuthorizeProblem(
 		}
 		err = p.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*auth.ID,
 			auth.Timeout,
+			auth.ReleasedAt,
 			user_service.UserRole(*auth.Access),
 			warnMessage,
 		)
@@ -0,0 +1,220 @@
+package problem_service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+// authorizeTestCaseAccess allows the creator of a visible problem (or hc)
+func (p *ProblemService) authorizeTestCaseAccess(
+	ctx context.Context,
+	problemID int32,
+	warnMessage string,
+) (Problem, error) {
+	// the problem's lock is checked while fetching it
+	problem, err := p.GetProblemById(ctx, problemID)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	err = p.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		problem.CreatedBy,
+		user_service.PermProblemEditAny,
+		warnMessage,
+	)
+	if err != nil {
+		return Problem{}, err
+	}
+
+	return problem, nil
+}
+
+func (p *ProblemService) addTestCase(
+	ctx context.Context,
+	params database.AddProblemTestCaseParams,
+) (database.ProblemTestCase, error) {
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return database.ProblemTestCase{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
+	// concurrent uploads must not get the same position
+	if err = lockProblem(ctx, qtx, params.ProblemID); err != nil {
+		return database.ProblemTestCase{}, err
+	}
+
+	dbTestCase, err := qtx.AddProblemTestCase(ctx, params)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot add test case to problem %v, %w",
+			flux_errors.ErrInternal,
+			params.ProblemID,
+			err,
+		)
+		log.Error(err)
+		return database.ProblemTestCase{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after adding test case, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return database.ProblemTestCase{}, err
+	}
+
+	return dbTestCase, nil
+}
+
+func (p *ProblemService) getTestCases(
+	ctx context.Context,
+	q *database.Queries,
+	problemID int32,
+) ([]TestCase, error) {
+	dbTestCases, err := q.GetProblemTestCases(ctx, problemID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch test cases of problem %v, %w",
+			flux_errors.ErrInternal,
+			problemID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	res := make([]TestCase, 0, len(dbTestCases))
+	for _, dbTestCase := range dbTestCases {
+		res = append(res, dbTestCaseToServiceTestCase(dbTestCase))
+	}
+
+	return res, nil
+}
+
+func lockProblem(
+	ctx context.Context,
+	qtx *database.Queries,
+	problemID int32,
+) error {
+	_, err := qtx.LockProblemForUpdate(ctx, problemID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, no problem exist with the given id",
+				flux_errors.ErrNotFound,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot lock problem %v, %w",
+			flux_errors.ErrInternal,
+			problemID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (p *ProblemService) putTestCaseBlob(
+	ctx context.Context,
+	data []byte,
+) (string, error) {
+	key, err := p.TestCaseStore.Put(ctx, data)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot store test case data, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return "", err
+	}
+	return key, nil
+}
+
+func (p *ProblemService) getTestCaseBlob(
+	ctx context.Context,
+	testCaseID uuid.UUID,
+	key string,
+	sha256Hex string,
+) ([]byte, error) {
+	data, err := p.TestCaseStore.Get(ctx, key)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot read data of test case %v, %w",
+			flux_errors.ErrInternal,
+			testCaseID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	if checksum(data) != sha256Hex {
+		err = fmt.Errorf(
+			"%w, checksum mismatch in data of test case %v",
+			flux_errors.ErrInternal,
+			testCaseID,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// deleteTestCaseBlobs is best effort, failures are only logged
+func (p *ProblemService) deleteTestCaseBlobs(
+	ctx context.Context,
+	keys ...string,
+) {
+	for _, key := range keys {
+		if err := p.TestCaseStore.Delete(ctx, key); err != nil {
+			log.Errorf("cannot delete test case blob %s, %v", key, err)
+		}
+	}
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func dbTestCaseToServiceTestCase(dbTestCase database.ProblemTestCase) TestCase {
+	return TestCase{
+		ID:           dbTestCase.ID,
+		ProblemID:    dbTestCase.ProblemID,
+		Position:     dbTestCase.Position,
+		Group:        dbTestCase.TestGroup,
+		Points:       dbTestCase.Points,
+		InputSize:    dbTestCase.InputSize,
+		OutputSize:   dbTestCase.OutputSize,
+		InputSha256:  dbTestCase.InputSha256,
+		OutputSha256: dbTestCase.OutputSha256,
+		CreatedBy:    dbTestCase.CreatedBy,
+		CreatedAt:    dbTestCase.CreatedAt,
+	}
+}
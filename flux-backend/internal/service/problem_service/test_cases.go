@@ -0,0 +1,433 @@
+package problem_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (p *ProblemService) UploadTestCase(
+	ctx context.Context,
+	request UploadTestCaseRequest,
+) (TestCase, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return TestCase{}, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return TestCase{}, err
+	}
+
+	// authorize
+	problem, err := p.authorizeTestCaseAccess(
+		ctx,
+		request.ProblemID,
+		fmt.Sprintf(
+			"user %s tried to upload a test case to problem %v",
+			claims.UserName,
+			request.ProblemID,
+		),
+	)
+	if err != nil {
+		return TestCase{}, err
+	}
+
+	// external problems are judged by their platform
+	if problem.Platform != nil {
+		return TestCase{}, fmt.Errorf(
+			"%w, problem %v is judged on %s, it cannot have test cases",
+			flux_errors.ErrInvalidRequest,
+			problem.ID,
+			*problem.Platform,
+		)
+	}
+
+	// store the data
+	inputKey, err := p.putTestCaseBlob(ctx, request.Input)
+	if err != nil {
+		return TestCase{}, err
+	}
+	outputKey, err := p.putTestCaseBlob(ctx, request.Output)
+	if err != nil {
+		p.deleteTestCaseBlobs(ctx, inputKey)
+		return TestCase{}, err
+	}
+
+	// store the metadata
+	dbTestCase, err := p.addTestCase(
+		ctx,
+		database.AddProblemTestCaseParams{
+			ProblemID:    request.ProblemID,
+			TestGroup:    request.Group,
+			Points:       request.Points,
+			Storage:      p.TestCaseStore.Name(),
+			InputKey:     inputKey,
+			OutputKey:    outputKey,
+			InputSize:    int64(len(request.Input)),
+			OutputSize:   int64(len(request.Output)),
+			InputSha256:  checksum(request.Input),
+			OutputSha256: checksum(request.Output),
+			CreatedBy:    claims.UserId,
+		},
+	)
+	if err != nil {
+		p.deleteTestCaseBlobs(ctx, inputKey, outputKey)
+		return TestCase{}, err
+	}
+
+	log.Infof(
+		"user %s uploaded test case %v to problem %v",
+		claims.UserName,
+		dbTestCase.ID,
+		dbTestCase.ProblemID,
+	)
+
+	return dbTestCaseToServiceTestCase(dbTestCase), nil
+}
+
+func (p *ProblemService) GetTestCases(
+	ctx context.Context,
+	problemID int32,
+) ([]TestCase, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	_, err = p.authorizeTestCaseAccess(
+		ctx,
+		problemID,
+		fmt.Sprintf(
+			"user %s tried to view test cases of problem %v",
+			claims.UserName,
+			problemID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.getTestCases(ctx, p.DB, problemID)
+}
+
+func (p *ProblemService) ReorderTestCases(
+	ctx context.Context,
+	request ReorderTestCasesRequest,
+) ([]TestCase, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	_, err = p.authorizeTestCaseAccess(
+		ctx,
+		request.ProblemID,
+		fmt.Sprintf(
+			"user %s tried to reorder test cases of problem %v",
+			claims.UserName,
+			request.ProblemID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
+	// no other change to the tests of this problem until commit
+	if err = lockProblem(ctx, qtx, request.ProblemID); err != nil {
+		return nil, err
+	}
+
+	// the new order must be a permutation of the current tests
+	current, err := p.getTestCases(ctx, qtx, request.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) != len(request.TestCaseIDs) {
+		return nil, fmt.Errorf(
+			"%w, problem has %d test cases but %d were given",
+			flux_errors.ErrInvalidRequest,
+			len(current),
+			len(request.TestCaseIDs),
+		)
+	}
+	seen := make(map[uuid.UUID]bool, len(request.TestCaseIDs))
+	for _, id := range request.TestCaseIDs {
+		if seen[id] {
+			return nil, fmt.Errorf(
+				"%w, test case %v is repeated",
+				flux_errors.ErrInvalidRequest,
+				id,
+			)
+		}
+		seen[id] = true
+	}
+
+	// update positions, uniqueness is checked at commit
+	for i, id := range request.TestCaseIDs {
+		updated, err := qtx.SetProblemTestCasePosition(
+			ctx,
+			database.SetProblemTestCasePositionParams{
+				ID:        id,
+				ProblemID: request.ProblemID,
+				Position:  int32(i + 1),
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot set position of test case %v, %w",
+				flux_errors.ErrInternal,
+				id,
+				err,
+			)
+			log.Error(err)
+			return nil, err
+		}
+		if updated == 0 {
+			return nil, fmt.Errorf(
+				"%w, test case %v does not belong to problem %v",
+				flux_errors.ErrInvalidRequest,
+				id,
+				request.ProblemID,
+			)
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after reordering test cases, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	log.Infof(
+		"user %s reordered test cases of problem %v",
+		claims.UserName,
+		request.ProblemID,
+	)
+
+	return p.getTestCases(ctx, p.DB, request.ProblemID)
+}
+
+func (p *ProblemService) DeleteTestCase(
+	ctx context.Context,
+	testCaseID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the test case
+	dbTestCase, err := p.DB.GetProblemTestCaseByID(ctx, testCaseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, test case with id %v does not exist",
+				flux_errors.ErrNotFound,
+				testCaseID,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch test case with id %v, %w",
+			flux_errors.ErrInternal,
+			testCaseID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// authorize
+	_, err = p.authorizeTestCaseAccess(
+		ctx,
+		dbTestCase.ProblemID,
+		fmt.Sprintf(
+			"user %s tried to delete test case %v",
+			claims.UserName,
+			testCaseID,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := p.DB.WithTx(tx)
+
+	// no other change to the tests of this problem until commit
+	if err = lockProblem(ctx, qtx, dbTestCase.ProblemID); err != nil {
+		return err
+	}
+
+	// re-read the position, it might have changed before the lock
+	dbTestCase, err = qtx.GetProblemTestCaseByID(ctx, testCaseID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, test case with id %v does not exist",
+				flux_errors.ErrNotFound,
+				testCaseID,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch test case with id %v, %w",
+			flux_errors.ErrInternal,
+			testCaseID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// delete and close the gap
+	if err = qtx.DeleteProblemTestCase(ctx, testCaseID); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot delete test case %v, %w",
+			flux_errors.ErrInternal,
+			testCaseID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	err = qtx.ShiftProblemTestCasePositions(
+		ctx,
+		database.ShiftProblemTestCasePositionsParams{
+			ProblemID: dbTestCase.ProblemID,
+			Position:  dbTestCase.Position,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot shift test cases of problem %v, %w",
+			flux_errors.ErrInternal,
+			dbTestCase.ProblemID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after deleting test case, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// the row is gone, a leftover blob only wastes space
+	p.deleteTestCaseBlobs(ctx, dbTestCase.InputKey, dbTestCase.OutputKey)
+
+	log.Infof(
+		"user %s deleted test case %v of problem %v",
+		claims.UserName,
+		testCaseID,
+		dbTestCase.ProblemID,
+	)
+
+	return nil
+}
+
+// GetTestCaseData loads the hidden tests of a problem in order for the judge.
+// It performs no authorization and verifies the checksum of every blob
+func (p *ProblemService) GetTestCaseData(
+	ctx context.Context,
+	problemID int32,
+) ([]TestCaseData, error) {
+	dbTestCases, err := p.DB.GetProblemTestCases(ctx, problemID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch test cases of problem %v, %w",
+			flux_errors.ErrInternal,
+			problemID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	res := make([]TestCaseData, 0, len(dbTestCases))
+	for _, dbTestCase := range dbTestCases {
+		if dbTestCase.Storage != p.TestCaseStore.Name() {
+			err = fmt.Errorf(
+				"%w, test case %v is stored in %s but the configured store is %s",
+				flux_errors.ErrInternal,
+				dbTestCase.ID,
+				dbTestCase.Storage,
+				p.TestCaseStore.Name(),
+			)
+			log.Error(err)
+			return nil, err
+		}
+
+		input, err := p.getTestCaseBlob(ctx, dbTestCase.ID, dbTestCase.InputKey, dbTestCase.InputSha256)
+		if err != nil {
+			return nil, err
+		}
+		output, err := p.getTestCaseBlob(ctx, dbTestCase.ID, dbTestCase.OutputKey, dbTestCase.OutputSha256)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, TestCaseData{
+			Position: dbTestCase.Position,
+			Group:    dbTestCase.TestGroup,
+			Points:   dbTestCase.Points,
+			Input:    input,
+			Output:   output,
+		})
+	}
+
+	return res, nil
+}
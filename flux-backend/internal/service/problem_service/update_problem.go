@@ -12,6 +12,7 @@ import (
 	"github.com/tcp_snm/flux/internal/flux_errors"
 	"github.com/tcp_snm/flux/internal/service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
 func (p *ProblemService) UpdateProblem(
@@ -37,6 +38,7 @@ func (p *ProblemService) UpdateProblem(
 	authErr := p.UserServiceConfig.AuthorizeCreatorAccess(
 		ctx,
 		oldProblem.CreatedBy,
+		user_service.PermProblemEditAny,
 		fmt.Sprintf(
 			"user %s tried to update the problem with id %v",
 			claims.UserName,
@@ -49,6 +51,11 @@ func (p *ProblemService) UpdateProblem(
 		return Problem{}, authErr
 	}
 
+	// a problem without a checker keeps its current one
+	if problem.Checker == nil {
+		problem.Checker = oldProblem.Checker
+	}
+
 	// validate the new problem
 	valErr := p.validateProblem(ctx, problem)
 	if valErr != nil {
@@ -145,6 +152,10 @@ func getUpdateProblemParams(
 		SubmissionLink:   problem.SubmissionLink,
 		Platform:         dbProblemData.platformType,
 		LockID:           problem.LockId,
+		CheckerMode:      dbProblemData.checkerMode,
+		CheckerEpsilon:   dbProblemData.checkerEpsilon,
+		CheckerLanguage:  dbProblemData.checkerLanguage,
+		CheckerSource:    dbProblemData.checkerSource,
 		LastUpdatedBy:    updatingUserId,
 	}, nil
 }
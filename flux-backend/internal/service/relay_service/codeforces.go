@@ -0,0 +1,380 @@
+package relay_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+const (
+	codeforcesPathEnter      = "/enter"
+	codeforcesPathSubmit     = "/problemset/submit"
+	codeforcesPathUserStatus = "/api/user.status"
+	codeforcesStatusCount    = 20
+	codeforcesRequestTimeout = 30 * time.Second
+)
+
+var (
+	// programTypeId of the compiler used for each supported language
+	codeforcesLanguages = map[string]string{
+		"c":       "43", // GNU GCC C11
+		"cpp":     "89", // GNU G++20 (64 bit)
+		"java":    "87", // Java 21
+		"python3": "31", // Python 3
+		"go":      "32", // Go
+	}
+	codeforcesCSRFRegex    = regexp.MustCompile(`name=["']csrf_token["']\s+value=["']([0-9a-fA-F]+)["']`)
+	codeforcesProblemRegex = regexp.MustCompile(`^/(?:problemset/problem|contest|gym)/(\d+)/(?:problem/)?([A-Za-z][A-Za-z0-9]*)/?$`)
+)
+
+/*
+	CodeforcesClient talks to codeforces (or any server at BaseURL speaking the same protocol)
+	Every bot gets its own cookie jar so that a login is reused across submissions
+*/
+
+type CodeforcesClient struct {
+	BaseURL    string
+	HTTPClient *http.Client // used as a template for the per bot clients
+
+	mu       sync.Mutex
+	sessions map[string]*http.Client
+}
+
+type codeforcesStatusResponse struct {
+	Status  string                 `json:"status"`
+	Comment string                 `json:"comment"`
+	Result  []codeforcesSubmission `json:"result"`
+}
+
+type codeforcesSubmission struct {
+	ID      int64  `json:"id"`
+	Verdict string `json:"verdict"`
+	Problem struct {
+		ContestID int64  `json:"contestId"`
+		Index     string `json:"index"`
+	} `json:"problem"`
+}
+
+func NewCodeforcesClient(baseURL string) *CodeforcesClient {
+	if baseURL == "" {
+		baseURL = defaultCodeforcesBaseURL
+	}
+	return &CodeforcesClient{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: codeforcesRequestTimeout},
+		sessions:   make(map[string]*http.Client),
+	}
+}
+
+func (c *CodeforcesClient) Submit(
+	ctx context.Context,
+	bot BotAccount,
+	submission RelaySubmission,
+) (string, error) {
+	// resolve the problem and the compiler
+	contestID, index, err := parseCodeforcesProblem(submission.SubmissionLink)
+	if err != nil {
+		return "", err
+	}
+	programTypeID, ok := codeforcesLanguages[submission.Language]
+	if !ok {
+		return "", fmt.Errorf("language %s is not supported on codeforces", submission.Language)
+	}
+
+	client := c.session(bot.Handle)
+
+	// fetch the submit page, logging in if the session is missing or expired
+	csrf, loggedIn, err := c.fetchCSRF(ctx, client, codeforcesPathSubmit)
+	if err != nil {
+		return "", err
+	}
+	if !loggedIn {
+		if err = c.login(ctx, client, bot); err != nil {
+			return "", err
+		}
+		csrf, loggedIn, err = c.fetchCSRF(ctx, client, codeforcesPathSubmit)
+		if err != nil {
+			return "", err
+		}
+		if !loggedIn {
+			return "", fmt.Errorf("bot %s is not logged in after a successful login", bot.Handle)
+		}
+	}
+
+	// submit the solution
+	form := url.Values{
+		"csrf_token":           {csrf},
+		"action":               {"submitSolutionFormSubmitted"},
+		"submittedProblemCode": {contestID + index},
+		"programTypeId":        {programTypeID},
+		"source":               {submission.Solution},
+		"tabSize":              {"4"},
+		"sourceFile":           {""},
+	}
+	resp, err := c.postForm(
+		ctx, client,
+		codeforcesPathSubmit+"?csrf_token="+url.QueryEscape(csrf),
+		form,
+	)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	// codeforces redirects to the status page on success and
+	// renders the submit page again with an error otherwise
+	if resp.Request.URL.Path == codeforcesPathSubmit {
+		return "", fmt.Errorf(
+			"codeforces rejected submission %v of bot %s",
+			submission.ID,
+			bot.Handle,
+		)
+	}
+
+	// the bot is leased to this submission, so its latest submission to the problem is ours
+	submissions, err := c.fetchStatus(ctx, bot.Handle)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range submissions {
+		if strconv.FormatInt(s.Problem.ContestID, 10) == contestID &&
+			strings.EqualFold(s.Problem.Index, index) {
+			return strconv.FormatInt(s.ID, 10), nil
+		}
+	}
+
+	return "", fmt.Errorf(
+		"cannot find submission %v in the status of bot %s",
+		submission.ID,
+		bot.Handle,
+	)
+}
+
+func (c *CodeforcesClient) GetVerdict(
+	ctx context.Context,
+	bot BotAccount,
+	externalID string,
+) (submission_service.SubmissionStatus, bool, error) {
+	submissions, err := c.fetchStatus(ctx, bot.Handle)
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, s := range submissions {
+		if strconv.FormatInt(s.ID, 10) != externalID {
+			continue
+		}
+		status, done := codeforcesVerdictToStatus(s.Verdict)
+		return status, done, nil
+	}
+
+	return "", false, fmt.Errorf(
+		"submission %s not found in the recent submissions of bot %s",
+		externalID,
+		bot.Handle,
+	)
+}
+
+func (c *CodeforcesClient) session(handle string) *http.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessions == nil {
+		c.sessions = make(map[string]*http.Client)
+	}
+	if client, ok := c.sessions[handle]; ok {
+		return client
+	}
+
+	// cookiejar.New never returns an error without options
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar, Timeout: codeforcesRequestTimeout}
+	if c.HTTPClient != nil {
+		client.Transport = c.HTTPClient.Transport
+		client.Timeout = c.HTTPClient.Timeout
+	}
+	c.sessions[handle] = client
+	return client
+}
+
+func (c *CodeforcesClient) login(
+	ctx context.Context,
+	client *http.Client,
+	bot BotAccount,
+) error {
+	csrf, _, err := c.fetchCSRF(ctx, client, codeforcesPathEnter)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"csrf_token":    {csrf},
+		"action":        {"enter"},
+		"handleOrEmail": {bot.Handle},
+		"password":      {bot.Password},
+		"remember":      {"on"},
+	}
+	resp, err := c.postForm(ctx, client, codeforcesPathEnter, form)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	// a failed login renders the login page again
+	if resp.Request.URL.Path == codeforcesPathEnter {
+		return fmt.Errorf("cannot login to codeforces with bot %s", bot.Handle)
+	}
+
+	return nil
+}
+
+// fetchCSRF reports loggedIn=false if codeforces redirected to the login page
+func (c *CodeforcesClient) fetchCSRF(
+	ctx context.Context,
+	client *http.Client,
+	path string,
+) (csrf string, loggedIn bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot fetch %s from codeforces, %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("codeforces responded with %d for %s", resp.StatusCode, path)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read %s from codeforces, %w", path, err)
+	}
+
+	matches := codeforcesCSRFRegex.FindSubmatch(body)
+	if matches == nil {
+		return "", false, fmt.Errorf("csrf token not found in %s", path)
+	}
+
+	loggedIn = path == codeforcesPathEnter || resp.Request.URL.Path != codeforcesPathEnter
+	return string(matches[1]), loggedIn, nil
+}
+
+func (c *CodeforcesClient) postForm(
+	ctx context.Context,
+	client *http.Client,
+	path string,
+	form url.Values,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		c.BaseURL+path,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot post to %s on codeforces, %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("codeforces responded with %d for %s", resp.StatusCode, path)
+	}
+
+	return resp, nil
+}
+
+func (c *CodeforcesClient) fetchStatus(
+	ctx context.Context,
+	handle string,
+) ([]codeforcesSubmission, error) {
+	query := url.Values{
+		"handle": {handle},
+		"from":   {"1"},
+		"count":  {strconv.Itoa(codeforcesStatusCount)},
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.BaseURL+codeforcesPathUserStatus+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the api needs no session
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch status of %s from codeforces, %w", handle, err)
+	}
+	defer resp.Body.Close()
+
+	var status codeforcesStatusResponse
+	if err = json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("cannot decode status of %s from codeforces, %w", handle, err)
+	}
+	if status.Status != "OK" {
+		return nil, fmt.Errorf("codeforces api failed for %s, %s", handle, status.Comment)
+	}
+
+	return status.Result, nil
+}
+
+// parseCodeforcesProblem extracts the contest id and problem index from a problem url
+func parseCodeforcesProblem(link string) (contestID string, index string, err error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid codeforces problem link %s, %w", link, err)
+	}
+
+	matches := codeforcesProblemRegex.FindStringSubmatch(u.Path)
+	if matches == nil {
+		return "", "", fmt.Errorf("invalid codeforces problem link %s", link)
+	}
+
+	return matches[1], strings.ToUpper(matches[2]), nil
+}
+
+func codeforcesVerdictToStatus(verdict string) (submission_service.SubmissionStatus, bool) {
+	switch verdict {
+	case "", "TESTING":
+		return submission_service.StatusPending, false
+	case "OK":
+		return submission_service.StatusAccepted, true
+	case "WRONG_ANSWER", "PRESENTATION_ERROR":
+		return submission_service.StatusWrongAnswer, true
+	case "TIME_LIMIT_EXCEEDED", "IDLENESS_LIMIT_EXCEEDED":
+		return submission_service.StatusTimeLimitExceeded, true
+	case "MEMORY_LIMIT_EXCEEDED":
+		return submission_service.StatusMemoryLimitExceeded, true
+	case "RUNTIME_ERROR":
+		return submission_service.StatusRuntimeError, true
+	case "COMPILATION_ERROR":
+		return submission_service.StatusCompilationError, true
+	default:
+		return submission_service.StatusJudgeError, true
+	}
+}
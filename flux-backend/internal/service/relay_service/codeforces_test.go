@@ -0,0 +1,281 @@
+package relay_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+const (
+	fakeCSRF        = "0123456789abcdef"
+	fakeSessionName = "JSESSIONID"
+)
+
+// fakeCodeforces speaks just enough of the codeforces protocol for the client
+type fakeCodeforces struct {
+	handle   string
+	password string
+	// verdict of the submissions made on the fake server
+	verdict string
+	// reject every submission like codeforces does for a duplicate
+	rejectSubmit bool
+
+	mu          sync.Mutex
+	logins      int
+	submissions []codeforcesSubmission
+}
+
+func (f *fakeCodeforces) handler() http.Handler {
+	mux := http.NewServeMux()
+	page := fmt.Sprintf(`<form><input type="hidden" name="csrf_token" value="%s"/></form>`, fakeCSRF)
+
+	mux.HandleFunc(codeforcesPathEnter, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, page)
+			return
+		}
+		r.ParseForm()
+		if r.Form.Get("csrf_token") != fakeCSRF ||
+			r.Form.Get("handleOrEmail") != f.handle ||
+			r.Form.Get("password") != f.password {
+			// a failed login renders the login page again
+			fmt.Fprint(w, page)
+			return
+		}
+		f.mu.Lock()
+		f.logins++
+		f.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: fakeSessionName, Value: f.handle, Path: "/"})
+		http.Redirect(w, r, "/", http.StatusFound)
+	})
+
+	mux.HandleFunc(codeforcesPathSubmit, func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie(fakeSessionName); err != nil || cookie.Value != f.handle {
+			http.Redirect(w, r, codeforcesPathEnter, http.StatusFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, page)
+			return
+		}
+		r.ParseForm()
+		if f.rejectSubmit || r.Form.Get("csrf_token") != fakeCSRF {
+			fmt.Fprint(w, page)
+			return
+		}
+
+		// problem code is contest id followed by the index
+		code := r.Form.Get("submittedProblemCode")
+		f.mu.Lock()
+		submission := codeforcesSubmission{ID: int64(1000 + len(f.submissions))}
+		fmt.Sscanf(code, "%d%s", &submission.Problem.ContestID, &submission.Problem.Index)
+		// newest first, like the api
+		f.submissions = append([]codeforcesSubmission{submission}, f.submissions...)
+		f.mu.Unlock()
+		http.Redirect(w, r, "/problemset/status", http.StatusFound)
+	})
+
+	// home and status pages, where codeforces redirects after a login or a submission
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	})
+
+	mux.HandleFunc(codeforcesPathUserStatus, func(w http.ResponseWriter, r *http.Request) {
+		response := codeforcesStatusResponse{Status: "OK"}
+		if r.URL.Query().Get("handle") != f.handle {
+			response = codeforcesStatusResponse{Status: "FAILED", Comment: "handle not found"}
+		}
+		f.mu.Lock()
+		for _, s := range f.submissions {
+			s.Verdict = f.verdict
+			response.Result = append(response.Result, s)
+		}
+		f.mu.Unlock()
+		json.NewEncoder(w).Encode(response)
+	})
+
+	return mux
+}
+
+func newFakeCodeforces(t *testing.T, fake *fakeCodeforces) *CodeforcesClient {
+	t.Helper()
+	server := httptest.NewServer(fake.handler())
+	t.Cleanup(server.Close)
+	return NewCodeforcesClient(server.URL)
+}
+
+func testRelaySubmission() RelaySubmission {
+	return RelaySubmission{
+		ID:             uuid.New(),
+		Language:       "cpp",
+		Solution:       "int main() {}",
+		SubmissionLink: "https://codeforces.com/problemset/problem/1850/A",
+	}
+}
+
+func TestCodeforcesSubmitAndVerdict(t *testing.T) {
+	fake := &fakeCodeforces{handle: "bot1", password: "secret", verdict: "OK"}
+	client := newFakeCodeforces(t, fake)
+	bot := BotAccount{Handle: "bot1", Password: "secret"}
+	ctx := context.Background()
+
+	externalID, err := client.Submit(ctx, bot, testRelaySubmission())
+	if err != nil {
+		t.Fatalf("submit failed, %v", err)
+	}
+	if externalID != "1000" {
+		t.Fatalf("expected external id 1000, got %s", externalID)
+	}
+
+	// the session of the bot is reused
+	if _, err = client.Submit(ctx, bot, testRelaySubmission()); err != nil {
+		t.Fatalf("second submit failed, %v", err)
+	}
+	if fake.logins != 1 {
+		t.Fatalf("expected a single login, got %d", fake.logins)
+	}
+
+	status, done, err := client.GetVerdict(ctx, bot, externalID)
+	if err != nil {
+		t.Fatalf("get verdict failed, %v", err)
+	}
+	if !done || status != submission_service.StatusAccepted {
+		t.Fatalf("expected a final accepted verdict, got %s (done %v)", status, done)
+	}
+}
+
+func TestCodeforcesVerdictInProgress(t *testing.T) {
+	fake := &fakeCodeforces{handle: "bot1", password: "secret", verdict: "TESTING"}
+	client := newFakeCodeforces(t, fake)
+	bot := BotAccount{Handle: "bot1", Password: "secret"}
+
+	externalID, err := client.Submit(context.Background(), bot, testRelaySubmission())
+	if err != nil {
+		t.Fatalf("submit failed, %v", err)
+	}
+	status, done, err := client.GetVerdict(context.Background(), bot, externalID)
+	if err != nil {
+		t.Fatalf("get verdict failed, %v", err)
+	}
+	if done || status != submission_service.StatusPending {
+		t.Fatalf("expected a pending verdict, got %s (done %v)", status, done)
+	}
+
+	// an unknown id is an error, not a verdict
+	if _, _, err = client.GetVerdict(context.Background(), bot, "42"); err == nil {
+		t.Fatal("expected an error for an unknown submission")
+	}
+}
+
+func TestCodeforcesSubmitFailures(t *testing.T) {
+	tests := []struct {
+		name       string
+		fake       *fakeCodeforces
+		bot        BotAccount
+		submission func() RelaySubmission
+	}{
+		{
+			name: "wrong password",
+			fake: &fakeCodeforces{handle: "bot1", password: "secret"},
+			bot:  BotAccount{Handle: "bot1", Password: "wrong"},
+		},
+		{
+			name: "rejected submission",
+			fake: &fakeCodeforces{handle: "bot1", password: "secret", rejectSubmit: true},
+			bot:  BotAccount{Handle: "bot1", Password: "secret"},
+		},
+		{
+			name: "unsupported language",
+			fake: &fakeCodeforces{handle: "bot1", password: "secret"},
+			bot:  BotAccount{Handle: "bot1", Password: "secret"},
+			submission: func() RelaySubmission {
+				s := testRelaySubmission()
+				s.Language = "brainfuck"
+				return s
+			},
+		},
+		{
+			name: "invalid problem link",
+			fake: &fakeCodeforces{handle: "bot1", password: "secret"},
+			bot:  BotAccount{Handle: "bot1", Password: "secret"},
+			submission: func() RelaySubmission {
+				s := testRelaySubmission()
+				s.SubmissionLink = "https://codeforces.com/blog/entry/1"
+				return s
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newFakeCodeforces(t, tt.fake)
+			submission := testRelaySubmission()
+			if tt.submission != nil {
+				submission = tt.submission()
+			}
+			if _, err := client.Submit(context.Background(), tt.bot, submission); err == nil {
+				t.Fatal("expected submit to fail")
+			}
+			if len(tt.fake.submissions) != 0 {
+				t.Fatalf("expected no submission on the platform, got %d", len(tt.fake.submissions))
+			}
+		})
+	}
+}
+
+func TestParseCodeforcesProblem(t *testing.T) {
+	tests := []struct {
+		link      string
+		contestID string
+		index     string
+		wantErr   bool
+	}{
+		{link: "https://codeforces.com/problemset/problem/1850/A", contestID: "1850", index: "A"},
+		{link: "https://codeforces.com/contest/1850/problem/b1", contestID: "1850", index: "B1"},
+		{link: "https://codeforces.com/gym/102001/problem/C/", contestID: "102001", index: "C"},
+		{link: "https://codeforces.com/blog/entry/1", wantErr: true},
+		{link: "://bad", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		contestID, index, err := parseCodeforcesProblem(tt.link)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", tt.link)
+			}
+			continue
+		}
+		if err != nil || contestID != tt.contestID || index != tt.index {
+			t.Errorf("%s: got %s %s %v", tt.link, contestID, index, err)
+		}
+	}
+}
+
+func TestCodeforcesVerdictToStatus(t *testing.T) {
+	tests := map[string]struct {
+		status submission_service.SubmissionStatus
+		done   bool
+	}{
+		"":                      {submission_service.StatusPending, false},
+		"OK":                    {submission_service.StatusAccepted, true},
+		"WRONG_ANSWER":          {submission_service.StatusWrongAnswer, true},
+		"TIME_LIMIT_EXCEEDED":   {submission_service.StatusTimeLimitExceeded, true},
+		"MEMORY_LIMIT_EXCEEDED": {submission_service.StatusMemoryLimitExceeded, true},
+		"COMPILATION_ERROR":     {submission_service.StatusCompilationError, true},
+		"SKIPPED":               {submission_service.StatusJudgeError, true},
+	}
+
+	for verdict, want := range tests {
+		status, done := codeforcesVerdictToStatus(verdict)
+		if status != want.status || done != want.done {
+			t.Errorf("%q: got %s %v, want %s %v", verdict, status, done, want.status, want.done)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package relay_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+const (
+	KeyCodeforcesBaseURL     = "CODEFORCES_BASE_URL"
+	defaultCodeforcesBaseURL = "https://codeforces.com"
+	defaultBotLeaseDuration  = 10 * time.Minute
+	defaultPollInterval      = 5 * time.Second
+	defaultVerdictTimeout    = 5 * time.Minute
+	defaultIdleInterval      = 10 * time.Second
+)
+
+/*
+	PlatformClient submits solutions to an external judge on behalf of a bot account.
+	Submit returns the id of the submission on the platform, which is later
+	passed to GetVerdict. GetVerdict reports done=false while the platform is still judging.
+*/
+
+type PlatformClient interface {
+	Submit(ctx context.Context, bot BotAccount, submission RelaySubmission) (string, error)
+	GetVerdict(ctx context.Context, bot BotAccount, externalID string) (submission_service.SubmissionStatus, bool, error)
+}
+
+type RelayService struct {
+	DB                      *database.Queries
+	SubmissionServiceConfig *submission_service.SubmissionService
+	Clients                 map[database.Platform]PlatformClient
+
+	// zero values fall back to the defaults
+	BotLeaseDuration time.Duration
+	PollInterval     time.Duration
+	VerdictTimeout   time.Duration
+	IdleInterval     time.Duration
+}
+
+type BotAccount struct {
+	ID       uuid.UUID `json:"-"`
+	Handle   string    `json:"-"`
+	Password string    `json:"password"`
+}
+
+type RelaySubmission struct {
+	ID             uuid.UUID
+	Language       string
+	Solution       string
+	SubmissionLink string
+}
+
+// stored in submissions.website_data
+type relayWebsiteData struct {
+	Platform   database.Platform                   `json:"platform"`
+	Bot        string                              `json:"bot"`
+	ExternalID string                              `json:"external_id,omitempty"`
+	Verdict    submission_service.SubmissionStatus `json:"verdict,omitempty"`
+	Error      string                              `json:"error,omitempty"`
+}
@@ -0,0 +1,137 @@
+package relay_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/submission_service"
+)
+
+// relay submits the solution through the platform client and waits for the verdict.
+// Any failure is reported as a judge error so that the submission doesn't stay pending
+func (r *RelayService) relay(
+	ctx context.Context,
+	platform database.Platform,
+	bot database.Bot,
+	submission RelaySubmission,
+	websiteData *relayWebsiteData,
+) (submission_service.SubmissionStatus, error) {
+	client, ok := r.Clients[platform]
+	if !ok {
+		return submission_service.StatusJudgeError, fmt.Errorf(
+			"no platform client configured for %s",
+			platform,
+		)
+	}
+
+	account, err := dbBotToBotAccount(bot)
+	if err != nil {
+		return submission_service.StatusJudgeError, err
+	}
+
+	// submit
+	externalID, err := client.Submit(ctx, account, submission)
+	if err != nil {
+		return submission_service.StatusJudgeError, err
+	}
+	websiteData.ExternalID = externalID
+
+	// poll until the platform is done judging
+	pollCtx, cancel := context.WithTimeout(ctx, r.verdictTimeout())
+	defer cancel()
+	ticker := time.NewTicker(r.pollInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-pollCtx.Done():
+			return submission_service.StatusJudgeError, fmt.Errorf(
+				"no verdict for %s within %v",
+				externalID,
+				r.verdictTimeout(),
+			)
+		case <-ticker.C:
+			status, done, err := client.GetVerdict(pollCtx, account, externalID)
+			if err != nil {
+				// the platform may be flaky, keep polling until the timeout
+				log.WithField("external_id", externalID).Warnf("cannot get verdict, %v", err)
+				continue
+			}
+			if done {
+				websiteData.Verdict = status
+				return status, nil
+			}
+		}
+	}
+}
+
+func (r *RelayService) saveWebsiteData(
+	ctx context.Context,
+	submissionID uuid.UUID,
+	websiteData relayWebsiteData,
+	submissionLogger *log.Entry,
+) {
+	data, err := json.Marshal(websiteData)
+	if err != nil {
+		submissionLogger.Errorf("cannot marshal website data %v, %v", websiteData, err)
+		return
+	}
+
+	raw := json.RawMessage(data)
+	err = r.DB.SetSubmissionWebsiteData(
+		ctx,
+		database.SetSubmissionWebsiteDataParams{
+			ID:          submissionID,
+			WebsiteData: &raw,
+		},
+	)
+	if err != nil {
+		submissionLogger.Errorf("cannot save website data, %v", err)
+	}
+}
+
+func dbBotToBotAccount(bot database.Bot) (BotAccount, error) {
+	account := BotAccount{
+		ID:     bot.ID,
+		Handle: bot.AccountName,
+	}
+	if bot.WebsiteData == nil {
+		return BotAccount{}, fmt.Errorf("bot %s has no website data", bot.AccountName)
+	}
+	if err := json.Unmarshal(*bot.WebsiteData, &account); err != nil {
+		return BotAccount{}, fmt.Errorf("invalid website data of bot %s, %w", bot.AccountName, err)
+	}
+	return account, nil
+}
+
+func (r *RelayService) botLeaseDuration() time.Duration {
+	if r.BotLeaseDuration > 0 {
+		return r.BotLeaseDuration
+	}
+	return defaultBotLeaseDuration
+}
+
+func (r *RelayService) pollInterval() time.Duration {
+	if r.PollInterval > 0 {
+		return r.PollInterval
+	}
+	return defaultPollInterval
+}
+
+func (r *RelayService) verdictTimeout() time.Duration {
+	if r.VerdictTimeout > 0 {
+		return r.VerdictTimeout
+	}
+	return defaultVerdictTimeout
+}
+
+func (r *RelayService) idleInterval() time.Duration {
+	if r.IdleInterval > 0 {
+		return r.IdleInterval
+	}
+	return defaultIdleInterval
+}
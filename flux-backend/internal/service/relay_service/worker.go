@@ -0,0 +1,191 @@
+package relay_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+var (
+	once sync.Once
+)
+
+func (r *RelayService) StartRelayWorkers(numWorkers int) {
+	// using sync once to ensure that this happens only once even if the function is called multiple times
+	once.Do(func() {
+		log.Infof("starting %d relay workers", numWorkers)
+		for i := range numWorkers {
+			go r.worker(i + 1)
+		}
+	})
+}
+
+/*
+	Worker claims pending submissions of problems hosted on external platforms
+	For each one it leases a free bot of that platform, relays the solution
+	and waits for the verdict. It sleeps for a while if there is nothing to relay
+*/
+
+func (r *RelayService) worker(id int) {
+	workerLogger := log.WithField("relay_worker", id)
+	for {
+		relayed, err := r.relayNext(context.Background(), workerLogger)
+		if err != nil {
+			workerLogger.Errorf("cannot relay submission, %v", err)
+		}
+		if !relayed {
+			time.Sleep(r.idleInterval())
+		}
+	}
+}
+
+// relayNext reports false if there was no submission or no free bot
+func (r *RelayService) relayNext(ctx context.Context, workerLogger *log.Entry) (bool, error) {
+	submission, platform, bot, err := r.claimSubmission(ctx)
+	if err != nil || bot == nil {
+		return false, err
+	}
+
+	submissionLogger := workerLogger.WithFields(log.Fields{
+		"submission_id": submission.ID,
+		"platform":      platform,
+		"bot":           bot.AccountName,
+	})
+	submissionLogger.Info("relaying submission")
+
+	// the lease also expires on its own if the worker dies
+	defer func() {
+		if err := r.DB.ReleaseBot(ctx, bot.ID); err != nil {
+			submissionLogger.Errorf("cannot release bot, %v", err)
+		}
+	}()
+
+	websiteData := relayWebsiteData{
+		Platform: platform,
+		Bot:      bot.AccountName,
+	}
+	status, err := r.relay(ctx, platform, *bot, submission, &websiteData)
+	if err != nil {
+		submissionLogger.Errorf("relay failed, %v", err)
+		websiteData.Error = err.Error()
+	}
+
+	// save what the platform told us and then the verdict
+	r.saveWebsiteData(ctx, submission.ID, websiteData, submissionLogger)
+	err = r.SubmissionServiceConfig.UpdateSubmissionStatus(ctx, submission.ID, status)
+	if err != nil {
+		return true, err
+	}
+
+	submissionLogger.Infof("submission judged as %s", status)
+	return true, nil
+}
+
+// claimSubmission returns a nil bot if there is nothing to relay right now
+func (r *RelayService) claimSubmission(
+	ctx context.Context,
+) (RelaySubmission, database.Platform, *database.Bot, error) {
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return RelaySubmission{}, "", nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := r.DB.WithTx(tx)
+
+	// lock the oldest pending submission
+	dbSubmission, err := qtx.ClaimPendingRelaySubmission(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return RelaySubmission{}, "", nil, nil
+		}
+		err = fmt.Errorf(
+			"%w, cannot claim a pending submission, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return RelaySubmission{}, "", nil, err
+	}
+	platform := dbSubmission.Platform.Platform
+
+	// lease a bot of that platform
+	leasedUntil := time.Now().Add(r.botLeaseDuration())
+	bot, err := qtx.AcquireFreeBot(
+		ctx,
+		database.AcquireFreeBotParams{
+			LeasedUntil: &leasedUntil,
+			Platform:    string(platform),
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// every bot is busy, try again later
+			return RelaySubmission{}, "", nil, nil
+		}
+		err = fmt.Errorf(
+			"%w, cannot acquire a bot for platform %s, %w",
+			flux_errors.ErrInternal,
+			platform,
+			err,
+		)
+		log.Error(err)
+		return RelaySubmission{}, "", nil, err
+	}
+
+	// mark the submission as taken by this bot
+	err = qtx.SetSubmissionBot(
+		ctx,
+		database.SetSubmissionBotParams{
+			ID:           dbSubmission.ID,
+			BotAccountID: &bot.ID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot assign bot %v to submission %v, %w",
+			flux_errors.ErrInternal,
+			bot.ID,
+			dbSubmission.ID,
+			err,
+		)
+		log.Error(err)
+		return RelaySubmission{}, "", nil, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after claiming submission %v, %w",
+			flux_errors.ErrInternal,
+			dbSubmission.ID,
+			err,
+		)
+		log.Error(err)
+		return RelaySubmission{}, "", nil, err
+	}
+
+	var submissionLink string
+	if dbSubmission.SubmissionLink != nil {
+		submissionLink = *dbSubmission.SubmissionLink
+	}
+
+	return RelaySubmission{
+		ID:             dbSubmission.ID,
+		Language:       dbSubmission.Language,
+		Solution:       dbSubmission.Solution,
+		SubmissionLink: submissionLink,
+	}, platform, &bot, nil
+}
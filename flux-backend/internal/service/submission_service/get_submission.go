@@ -0,0 +1,226 @@
+package submission_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (s *SubmissionService) GetSubmissionByID(
+	ctx context.Context,
+	id uuid.UUID,
+) (Submission, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	// get the submission
+	dbSubmission, err := s.DB.GetSubmissionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Submission{}, fmt.Errorf(
+				"%w, submission with id %v does not exist",
+				flux_errors.ErrNotFound,
+				id,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch submission with id %v from db, %w",
+			flux_errors.ErrInternal,
+			id,
+			err,
+		)
+		log.Error(err)
+		return Submission{}, err
+	}
+
+	// the solution is visible to its author, teammates and
+	// users who can view any submission only
+	err = s.UserServiceConfig.AuthorizeCreatorAccess(
+		ctx,
+		dbSubmission.SubmittedBy,
+		user_service.PermSubmissionViewAny,
+		"",
+	)
+	if err != nil && dbSubmission.TeamID != nil {
+		member, memErr := s.TeamServiceConfig.IsTeamMember(
+			ctx,
+			*dbSubmission.TeamID,
+			claims.UserId,
+		)
+		if memErr != nil {
+			return Submission{}, memErr
+		}
+		if member {
+			err = nil
+		}
+	}
+	if err != nil {
+		log.Warnf(
+			"user %s tried to view submission %v of another user",
+			claims.UserName,
+			id,
+		)
+		return Submission{}, err
+	}
+
+	var status SubmissionStatus
+	if dbSubmission.Status != nil {
+		status = SubmissionStatus(*dbSubmission.Status)
+	}
+
+	return Submission{
+		ID:          dbSubmission.ID,
+		SubmittedBy: dbSubmission.SubmittedBy,
+		UserName:    dbSubmission.UserName,
+		ContestID:   dbSubmission.ContestID,
+		ProblemID:   dbSubmission.ProblemID,
+		Language:    dbSubmission.Language,
+		Solution:    dbSubmission.Solution,
+		Status:      status,
+		TeamID:      dbSubmission.TeamID,
+		CreatedAt:   dbSubmission.CreatedAt,
+		UpdatedAt:   dbSubmission.UpdatedAt,
+	}, nil
+}
+
+func (s *SubmissionService) GetSubmissionsByFilters(
+	ctx context.Context,
+	request GetSubmissionsRequest,
+) ([]SubmissionMetaData, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// only the users who can view any submission search the others' ones
+	var submittedBy *uuid.UUID
+	err = s.UserServiceConfig.AuthorizePermission(
+		ctx,
+		user_service.PermSubmissionViewAny,
+		"",
+	)
+	if err != nil {
+		if !errors.Is(err, flux_errors.ErrUnAuthorized) {
+			return nil, err
+		}
+		if request.UserName != "" && request.UserName != claims.UserName {
+			log.Warnf(
+				"user %s tried to search submissions of user %s",
+				claims.UserName,
+				request.UserName,
+			)
+			return nil, fmt.Errorf(
+				"%w, only your own submissions can be searched",
+				flux_errors.ErrUnAuthorized,
+			)
+		}
+		submittedBy = &claims.UserId
+	} else if request.UserName != "" {
+		// fetch the user id if user_name is provided
+		userID, err := s.UserServiceConfig.GetUserIDByUserName(ctx, request.UserName)
+		if err != nil {
+			return nil, err
+		}
+		submittedBy = &userID
+	}
+
+	// the filters must not reveal locked contests and problems
+	if request.ContestID != nil {
+		contest, err := s.ContestServiceConfig.GetContestByID(ctx, *request.ContestID)
+		if err != nil {
+			return nil, err
+		}
+		err = s.ContestServiceConfig.AuthorizeProblemView(ctx, contest)
+		if err != nil {
+			if errors.Is(err, flux_errors.ErrUnAuthorized) {
+				err = fmt.Errorf(
+					"%w, contest with id %v does not exist",
+					flux_errors.ErrNotFound,
+					*request.ContestID,
+				)
+			}
+			return nil, err
+		}
+	}
+	if request.ProblemID != nil {
+		_, err = s.ProblemServiceConfig.AuthorizeProblem(
+			ctx,
+			*request.ProblemID,
+			fmt.Sprintf(
+				"user %s tried to search submissions of unauthorized problem %v",
+				claims.UserName,
+				*request.ProblemID,
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// calculate offset
+	offset := (request.PageNumber - 1) * request.PageSize
+
+	// fetch submissions
+	dbSubmissions, err := s.DB.GetSubmissionsByFilters(
+		ctx,
+		database.GetSubmissionsByFiltersParams{
+			SubmittedBy: submittedBy,
+			ContestID:   request.ContestID,
+			ProblemID:   request.ProblemID,
+			Language:    request.Language,
+			Status:      request.Status,
+			Limit:       request.PageSize,
+			Offset:      offset,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch submissions with filters, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.WithField("filters", request).Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]SubmissionMetaData, 0, len(dbSubmissions))
+	for _, dbSubmission := range dbSubmissions {
+		var status SubmissionStatus
+		if dbSubmission.Status != nil {
+			status = SubmissionStatus(*dbSubmission.Status)
+		}
+		res = append(res, SubmissionMetaData{
+			ID:          dbSubmission.ID,
+			SubmittedBy: dbSubmission.SubmittedBy,
+			UserName:    dbSubmission.UserName,
+			ContestID:   dbSubmission.ContestID,
+			ProblemID:   dbSubmission.ProblemID,
+			Language:    dbSubmission.Language,
+			Status:      status,
+			TeamID:      dbSubmission.TeamID,
+			CreatedAt:   dbSubmission.CreatedAt,
+			UpdatedAt:   dbSubmission.UpdatedAt,
+		})
+	}
+
+	return res, nil
+}
@@ -0,0 +1,147 @@
+package submission_service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/judge"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+)
+
+// ReportJudgeVerdict is the judge.Reporter of the local judge
+func (s *SubmissionService) ReportJudgeVerdict(
+	ctx context.Context,
+	submissionID uuid.UUID,
+	verdict judge.Verdict,
+) error {
+	return s.UpdateSubmissionStatus(ctx, submissionID, SubmissionStatus(verdict))
+}
+
+// RequeuePendingSubmissions sends the local submissions left pending
+// by a previous run back to the judge
+func (s *SubmissionService) RequeuePendingSubmissions(ctx context.Context) error {
+	dbSubmissions, err := s.DB.GetPendingLocalSubmissions(ctx)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch pending submissions, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	log.Infof("requeuing %d pending submissions", len(dbSubmissions))
+	for _, dbSubmission := range dbSubmissions {
+		if err = s.enqueueLocalJudge(ctx, dbSubmission); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// enqueueLocalJudge sends the submission to the local judge
+// if its problem is not hosted on an external platform
+func (s *SubmissionService) enqueueLocalJudge(
+	ctx context.Context,
+	submission database.Submission,
+) error {
+	// get the problem
+	dbProblem, err := s.DB.GetProblemById(ctx, submission.ProblemID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch problem %v of submission %v, %w",
+			flux_errors.ErrInternal,
+			submission.ProblemID,
+			submission.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// external problems are relayed to their platform
+	if dbProblem.Platform.Valid {
+		return nil
+	}
+
+	return judge.NewJob(
+		ctx,
+		judge.Job{
+			SubmissionID:  submission.ID,
+			Language:      submission.Language,
+			Solution:      submission.Solution,
+			TimeLimitMs:   dbProblem.TimeLimitMs,
+			MemoryLimitKb: dbProblem.MemoryLimitKb,
+			LoadTestCases: s.testCaseLoader(dbProblem.ID, dbProblem.ExampleTestcases),
+			Checker:       dbProblemChecker(dbProblem),
+		},
+	)
+}
+
+func dbProblemChecker(dbProblem database.GetProblemByIdRow) judge.Checker {
+	checker := judge.Checker{
+		Mode: judge.CheckerMode(dbProblem.CheckerMode),
+	}
+	if dbProblem.CheckerEpsilon != nil {
+		checker.Epsilon = *dbProblem.CheckerEpsilon
+	}
+	if dbProblem.CheckerLanguage != nil {
+		checker.Language = *dbProblem.CheckerLanguage
+	}
+	if dbProblem.CheckerSource != nil {
+		checker.Source = *dbProblem.CheckerSource
+	}
+	return checker
+}
+
+// testCaseLoader loads the hidden tests of the problem,
+// the examples are used if it has none
+func (s *SubmissionService) testCaseLoader(
+	problemID int32,
+	exampleTestCases *json.RawMessage,
+) judge.TestCaseLoader {
+	return func(ctx context.Context) ([]judge.TestCase, error) {
+		testData, err := s.ProblemServiceConfig.GetTestCaseData(ctx, problemID)
+		if err != nil {
+			return nil, err
+		}
+		testCases := make([]judge.TestCase, 0, len(testData))
+		for _, data := range testData {
+			testCases = append(testCases, judge.TestCase{
+				Input:  string(data.Input),
+				Output: string(data.Output),
+			})
+		}
+		if len(testCases) > 0 || exampleTestCases == nil {
+			return testCases, nil
+		}
+
+		var examples problem_service.ExampleTestCases
+		err = json.Unmarshal(*exampleTestCases, &examples)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot unmarshal example test cases of problem %v, %w",
+				flux_errors.ErrInternal,
+				problemID,
+				err,
+			)
+			log.Error(err)
+			return nil, err
+		}
+		for _, example := range examples.Examples {
+			testCases = append(testCases, judge.TestCase{
+				Input:  example.Input,
+				Output: example.Output,
+			})
+		}
+
+		return testCases, nil
+	}
+}
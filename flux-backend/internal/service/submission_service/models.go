@@ -0,0 +1,79 @@
+package submission_service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/problem_service"
+	"github.com/tcp_snm/flux/internal/service/team_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+const (
+	StatusPending             SubmissionStatus = "pending"
+	StatusAccepted            SubmissionStatus = "accepted"
+	StatusWrongAnswer         SubmissionStatus = "wrong_answer"
+	StatusTimeLimitExceeded   SubmissionStatus = "time_limit_exceeded"
+	StatusMemoryLimitExceeded SubmissionStatus = "memory_limit_exceeded"
+	StatusRuntimeError        SubmissionStatus = "runtime_error"
+	StatusCompilationError    SubmissionStatus = "compilation_error"
+	StatusJudgeError          SubmissionStatus = "judge_error"
+)
+
+type SubmissionStatus string
+
+type SubmissionService struct {
+	DB                   *database.Queries
+	UserServiceConfig    *user_service.UserService
+	ProblemServiceConfig *problem_service.ProblemService
+	ContestServiceConfig *contest_service.ContestService
+	TeamServiceConfig    *team_service.TeamService
+}
+
+type SubmitRequest struct {
+	ProblemID int32      `json:"problem_id" validate:"required"`
+	ContestID *uuid.UUID `json:"contest_id"`
+	Language  string     `json:"language" validate:"required,oneof=c cpp java python3 go"`
+	Solution  string     `json:"solution" validate:"required,max=65536"`
+}
+
+type Submission struct {
+	ID          uuid.UUID        `json:"submission_id"`
+	SubmittedBy uuid.UUID        `json:"submitted_by"`
+	UserName    string           `json:"user_name"`
+	ContestID   *uuid.UUID       `json:"contest_id"`
+	ProblemID   int32            `json:"problem_id"`
+	Language    string           `json:"language"`
+	Solution    string           `json:"solution"`
+	Status      SubmissionStatus `json:"status"`
+	TeamID      *uuid.UUID       `json:"team_id"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+// dto submissions are requested based on filters,
+// the solution is left out, only a single submission shows it
+type SubmissionMetaData struct {
+	ID          uuid.UUID        `json:"submission_id"`
+	SubmittedBy uuid.UUID        `json:"submitted_by"`
+	UserName    string           `json:"user_name"`
+	ContestID   *uuid.UUID       `json:"contest_id"`
+	ProblemID   int32            `json:"problem_id"`
+	Language    string           `json:"language"`
+	Status      SubmissionStatus `json:"status"`
+	TeamID      *uuid.UUID       `json:"team_id"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
+
+type GetSubmissionsRequest struct {
+	UserName   string     `json:"user_name"`
+	ContestID  *uuid.UUID `json:"contest_id"`
+	ProblemID  *int32     `json:"problem_id"`
+	Language   *string    `json:"language"`
+	Status     *string    `json:"status"`
+	PageNumber int32      `json:"page_number" validate:"min=1,max=10000"`
+	PageSize   int32      `json:"page_size" validate:"min=0,max=10000"`
+}
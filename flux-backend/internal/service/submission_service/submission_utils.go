@@ -0,0 +1,162 @@
+package submission_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+// validateContestSubmission returns the team of the user for team contests
+func (s *SubmissionService) validateContestSubmission(
+	ctx context.Context,
+	userID uuid.UUID,
+	contestID uuid.UUID,
+	problemID int32,
+) (*uuid.UUID, error) {
+	// get the contest
+	contest, err := s.ContestServiceConfig.GetContestByID(ctx, contestID)
+	if err != nil {
+		return nil, err
+	}
+
+	// contest start time must not be nil
+	if contest.StartTime == nil {
+		err = fmt.Errorf(
+			"%w, contest %v has start time as nil, cannot accept submissions",
+			flux_errors.ErrInternal,
+			contest.ID,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// submissions are accepted only while the contest is running
+	now := time.Now()
+	if now.Before(*contest.StartTime) {
+		return nil, fmt.Errorf(
+			"%w, contest has not started yet",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if !now.Before(contest.EndTime) {
+		return nil, fmt.Errorf(
+			"%w, contest has ended, submit without contest_id to practice",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// in team contests users submit on behalf of their registered team
+	var teamID *uuid.UUID
+	if contest.TeamContest {
+		id, err := s.DB.GetUserTeamInContest(
+			ctx,
+			database.GetUserTeamInContestParams{
+				ContestID: contestID,
+				UserID:    userID,
+			},
+		)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf(
+					"%w, user is not in any team registered in the contest",
+					flux_errors.ErrUnAuthorized,
+				)
+			}
+			err = fmt.Errorf(
+				"%w, cannot fetch team of user %v in contest %v, %w",
+				flux_errors.ErrInternal,
+				userID,
+				contestID,
+				err,
+			)
+			log.Error(err)
+			return nil, err
+		}
+		teamID = &id
+	} else if !contest.IsPublished {
+		// published contests are open to everyone,
+		// others accept submissions only from registered users
+		registered, err := s.DB.IsUserRegisteredInContest(
+			ctx,
+			database.IsUserRegisteredInContestParams{
+				ContestID: contestID,
+				UserID:    userID,
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot check registration of user %v in contest %v, %w",
+				flux_errors.ErrInternal,
+				userID,
+				contestID,
+				err,
+			)
+			log.Error(err)
+			return nil, err
+		}
+		if !registered {
+			return nil, fmt.Errorf(
+				"%w, user is not registered in the contest",
+				flux_errors.ErrUnAuthorized,
+			)
+		}
+	}
+
+	// problem must belong to the contest
+	present, err := s.DB.IsProblemInContest(
+		ctx,
+		database.IsProblemInContestParams{
+			ContestID: contestID,
+			ProblemID: problemID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check if problem %v is in contest %v, %w",
+			flux_errors.ErrInternal,
+			problemID,
+			contestID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+	if !present {
+		return nil, fmt.Errorf(
+			"%w, problem %v is not part of the contest",
+			flux_errors.ErrInvalidRequest,
+			problemID,
+		)
+	}
+
+	return teamID, nil
+}
+
+func dbSubmissionToServiceSubmission(
+	dbSubmission database.Submission,
+) Submission {
+	var status SubmissionStatus
+	if dbSubmission.Status != nil {
+		status = SubmissionStatus(*dbSubmission.Status)
+	}
+
+	return Submission{
+		ID:          dbSubmission.ID,
+		SubmittedBy: dbSubmission.SubmittedBy,
+		ContestID:   dbSubmission.ContestID,
+		ProblemID:   dbSubmission.ProblemID,
+		Language:    dbSubmission.Language,
+		Solution:    dbSubmission.Solution,
+		Status:      status,
+		TeamID:      dbSubmission.TeamID,
+		CreatedAt:   dbSubmission.CreatedAt,
+		UpdatedAt:   dbSubmission.UpdatedAt,
+	}
+}
@@ -0,0 +1,95 @@
+package submission_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (s *SubmissionService) Submit(
+	ctx context.Context,
+	request SubmitRequest,
+) (Submission, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Submission{}, err
+	}
+
+	// contest submissions are validated against the contest,
+	// practice submissions only need the problem to be visible
+	var teamID *uuid.UUID
+	if request.ContestID != nil {
+		teamID, err = s.validateContestSubmission(
+			ctx,
+			claims.UserId,
+			*request.ContestID,
+			request.ProblemID,
+		)
+	} else {
+		_, err = s.ProblemServiceConfig.AuthorizeProblem(
+			ctx,
+			request.ProblemID,
+			fmt.Sprintf(
+				"user %s tried to submit to unauthorized problem %v",
+				claims.UserName,
+				request.ProblemID,
+			),
+		)
+	}
+	if err != nil {
+		return Submission{}, err
+	}
+
+	// store the submission
+	status := string(StatusPending)
+	dbSubmission, err := s.DB.CreateSubmission(
+		ctx,
+		database.CreateSubmissionParams{
+			SubmittedBy: claims.UserId,
+			ContestID:   request.ContestID,
+			ProblemID:   request.ProblemID,
+			Language:    request.Language,
+			Solution:    request.Solution,
+			Status:      &status,
+			TeamID:      teamID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot create submission for problem %v, %w",
+			flux_errors.ErrInternal,
+			request.ProblemID,
+			err,
+		)
+		log.Error(err)
+		return Submission{}, err
+	}
+
+	log.Infof(
+		"user %s submitted submission %v to problem %v",
+		claims.UserName,
+		dbSubmission.ID,
+		dbSubmission.ProblemID,
+	)
+
+	// the submission stays pending and is requeued on restart if this fails
+	if err = s.enqueueLocalJudge(ctx, dbSubmission); err != nil {
+		log.Errorf("cannot send submission %v to the judge, %v", dbSubmission.ID, err)
+	}
+
+	submission := dbSubmissionToServiceSubmission(dbSubmission)
+	submission.UserName = claims.UserName
+	return submission, nil
+}
@@ -0,0 +1,168 @@
+package submission_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// UpdateSubmissionStatus records the verdict of a submission. It is meant
+// to be called by judges, so it performs no user authorization. An accepted
+// contest submission also updates the leaderboard cache in the same transaction.
+func (s *SubmissionService) UpdateSubmissionStatus(
+	ctx context.Context,
+	submissionID uuid.UUID,
+	status SubmissionStatus,
+) error {
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := s.DB.WithTx(tx)
+
+	// update the status
+	dbStatus := string(status)
+	submission, err := qtx.UpdateSubmissionStatus(
+		ctx,
+		database.UpdateSubmissionStatusParams{
+			ID:     submissionID,
+			Status: &dbStatus,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf(
+				"%w, submission with id %v does not exist",
+				flux_errors.ErrNotFound,
+				submissionID,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot update status of submission %v, %w",
+			flux_errors.ErrInternal,
+			submissionID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	// update leaderboard
+	if status == StatusAccepted && submission.ContestID != nil {
+		err = s.addContestScore(ctx, qtx, submission)
+		if err != nil {
+			return err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after updating submission status, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *SubmissionService) addContestScore(
+	ctx context.Context,
+	qtx *database.Queries,
+	submission database.Submission,
+) error {
+	if qtx == nil {
+		return fmt.Errorf(
+			"%w, transaction query tool is nil, cannot add score for submission %v",
+			flux_errors.ErrInternal,
+			submission.ID,
+		)
+	}
+
+	// the team is scored instead of the user in team contests
+	if submission.TeamID != nil {
+		_, err := qtx.AddTeamScore(
+			ctx,
+			database.AddTeamScoreParams{
+				TeamID:       *submission.TeamID,
+				SubmissionID: submission.ID,
+				ContestID:    *submission.ContestID,
+				ProblemID:    submission.ProblemID,
+			},
+		)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot add team score of submission %v, %w",
+				flux_errors.ErrInternal,
+				submission.ID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+		return nil
+	}
+
+	// only the first accepted submission of a problem counts
+	inserted, err := qtx.MarkProblemSolved(
+		ctx,
+		database.MarkProblemSolvedParams{
+			UserID:    submission.SubmittedBy,
+			ContestID: *submission.ContestID,
+			ProblemID: submission.ProblemID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot mark problem %v solved in contest %v, %w",
+			flux_errors.ErrInternal,
+			submission.ProblemID,
+			*submission.ContestID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if inserted == 0 {
+		return nil
+	}
+
+	// score is taken from the contest problem
+	err = qtx.AddUserScore(
+		ctx,
+		database.AddUserScoreParams{
+			UserID:       submission.SubmittedBy,
+			SubmissionID: submission.ID,
+			ContestID:    *submission.ContestID,
+			ProblemID:    submission.ProblemID,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot add score of submission %v, %w",
+			flux_errors.ErrInternal,
+			submission.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package team_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// CreateTeam creates a team with the requesting user as its first member
+func (t *TeamService) CreateTeam(
+	ctx context.Context,
+	team Team,
+) (Team, error) {
+	// validate
+	err := service.ValidateInput(team)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// create the team
+	dbTeam, err := qtx.CreateTeam(ctx, database.CreateTeamParams{
+		Name:      team.Name,
+		CreatedBy: claims.UserId,
+		MaxSize:   team.MaxSize,
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) &&
+			pgErr.Code == flux_errors.CodeUniqueConstraintViolation {
+			return Team{}, fmt.Errorf(
+				"%w, team with name %s already exist",
+				flux_errors.ErrInvalidRequest,
+				team.Name,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot create team, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return Team{}, err
+	}
+
+	// creator is the first member
+	err = qtx.AddTeamMember(ctx, database.AddTeamMemberParams{
+		TeamID: dbTeam.ID,
+		UserID: claims.UserId,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot add creator to team %v, %w",
+			flux_errors.ErrInternal,
+			dbTeam.ID,
+			err,
+		)
+		log.Error(err)
+		return Team{}, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after creating team, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return Team{}, err
+	}
+
+	log.Infof("user %s created team %s", claims.UserName, dbTeam.Name)
+
+	return t.GetTeamByID(ctx, dbTeam.ID)
+}
@@ -0,0 +1,115 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+func (t *TeamService) GetTeamByID(
+	ctx context.Context,
+	id uuid.UUID,
+) (Team, error) {
+	// get the team
+	dbTeam, err := t.getTeam(ctx, id)
+	if err != nil {
+		return Team{}, err
+	}
+
+	// get its members
+	members, err := t.getTeamMembers(ctx, id)
+	if err != nil {
+		return Team{}, err
+	}
+
+	return Team{
+		ID:        dbTeam.ID,
+		Name:      dbTeam.Name,
+		MaxSize:   dbTeam.MaxSize,
+		CreatedBy: dbTeam.CreatedBy,
+		Members:   members,
+	}, nil
+}
+
+// GetUserTeams returns the teams of the requesting user
+func (t *TeamService) GetUserTeams(
+	ctx context.Context,
+) ([]Team, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get teams
+	dbTeams, err := t.DB.GetUserTeams(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch teams of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]Team, 0, len(dbTeams))
+	for _, dbTeam := range dbTeams {
+		members, err := t.getTeamMembers(ctx, dbTeam.ID)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, Team{
+			ID:        dbTeam.ID,
+			Name:      dbTeam.Name,
+			MaxSize:   dbTeam.MaxSize,
+			CreatedBy: dbTeam.CreatedBy,
+			Members:   members,
+		})
+	}
+
+	return res, nil
+}
+
+// GetTeamInvites returns the pending invites of the requesting user
+func (t *TeamService) GetTeamInvites(
+	ctx context.Context,
+) ([]TeamInvite, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// get invites
+	dbInvites, err := t.DB.GetUserTeamInvites(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch team invites of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// convert
+	res := make([]TeamInvite, 0, len(dbInvites))
+	for _, dbInvite := range dbInvites {
+		res = append(res, TeamInvite{
+			TeamID:    dbInvite.TeamID,
+			TeamName:  dbInvite.TeamName,
+			InvitedBy: dbInvite.InvitedBy,
+			CreatedAt: dbInvite.CreatedAt,
+		})
+	}
+
+	return res, nil
+}
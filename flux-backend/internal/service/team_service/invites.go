@@ -0,0 +1,219 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// InviteToTeam invites a user to the team, only members can invite
+func (t *TeamService) InviteToTeam(
+	ctx context.Context,
+	request InviteRequest,
+) error {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// get the team
+	dbTeam, err := t.getTeam(ctx, request.TeamID)
+	if err != nil {
+		return err
+	}
+
+	// authorize
+	member, err := t.IsTeamMember(ctx, dbTeam.ID, claims.UserId)
+	if err != nil {
+		return err
+	}
+	if !member {
+		log.Warnf(
+			"user %s tried to invite to team %s without being its member",
+			claims.UserName,
+			dbTeam.Name,
+		)
+		return fmt.Errorf(
+			"%w, only members can invite to the team",
+			flux_errors.ErrUnAuthorized,
+		)
+	}
+
+	// get the invitee
+	inviteeID, err := t.UserServiceConfig.GetUserIDByUserName(ctx, request.UserName)
+	if err != nil {
+		return err
+	}
+
+	// invitee must not be a member already
+	member, err = t.IsTeamMember(ctx, dbTeam.ID, inviteeID)
+	if err != nil {
+		return err
+	}
+	if member {
+		return fmt.Errorf(
+			"%w, user %s is already a member of the team",
+			flux_errors.ErrInvalidRequest,
+			request.UserName,
+		)
+	}
+
+	// team must have space (checked again when accepted)
+	count, err := t.DB.CountTeamMembers(ctx, dbTeam.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot count members of team %v, %w",
+			flux_errors.ErrInternal,
+			dbTeam.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if count >= int64(dbTeam.MaxSize) {
+		return fmt.Errorf(
+			"%w, team is full",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// invite (inviting again has no effect)
+	err = t.DB.CreateTeamInvite(ctx, database.CreateTeamInviteParams{
+		TeamID:    dbTeam.ID,
+		UserID:    inviteeID,
+		InvitedBy: claims.UserId,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot invite user %s to team %v, %w",
+			flux_errors.ErrInternal,
+			request.UserName,
+			dbTeam.ID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
+
+// RespondToInvite accepts or declines an invite of the requesting user
+func (t *TeamService) RespondToInvite(
+	ctx context.Context,
+	request RespondToInviteRequest,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// lock the team so concurrent accepts don't exceed its size
+	maxSize, err := t.lockTeam(ctx, qtx, request.TeamID)
+	if err != nil {
+		return err
+	}
+
+	// an invite is used only once
+	rows, err := qtx.DeleteTeamInvite(ctx, database.DeleteTeamInviteParams{
+		TeamID: request.TeamID,
+		UserID: claims.UserId,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot delete invite of user %s to team %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			request.TeamID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf(
+			"%w, no invite from team with id %v",
+			flux_errors.ErrNotFound,
+			request.TeamID,
+		)
+	}
+
+	// join the team
+	if request.Accept {
+		err = t.validateRosterChange(ctx, qtx, request.TeamID)
+		if err != nil {
+			return err
+		}
+
+		count, err := qtx.CountTeamMembers(ctx, request.TeamID)
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot count members of team %v, %w",
+				flux_errors.ErrInternal,
+				request.TeamID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+		if count >= int64(maxSize) {
+			return fmt.Errorf(
+				"%w, team is full",
+				flux_errors.ErrInvalidRequest,
+			)
+		}
+
+		err = qtx.AddTeamMember(ctx, database.AddTeamMemberParams{
+			TeamID: request.TeamID,
+			UserID: claims.UserId,
+		})
+		if err != nil {
+			err = fmt.Errorf(
+				"%w, cannot add user %s to team %v, %w",
+				flux_errors.ErrInternal,
+				claims.UserName,
+				request.TeamID,
+				err,
+			)
+			log.Error(err)
+			return err
+		}
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after responding to invite, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
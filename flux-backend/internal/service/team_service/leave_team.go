@@ -0,0 +1,84 @@
+package team_service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// LeaveTeam removes the requesting user from the team
+func (t *TeamService) LeaveTeam(
+	ctx context.Context,
+	teamID uuid.UUID,
+) error {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// lock the team so it isn't registered to a contest meanwhile
+	_, err = t.lockTeam(ctx, qtx, teamID)
+	if err != nil {
+		return err
+	}
+
+	// members cannot leave in the middle of a contest
+	err = t.validateRosterChange(ctx, qtx, teamID)
+	if err != nil {
+		return err
+	}
+
+	// leave
+	rows, err := qtx.RemoveTeamMember(ctx, database.RemoveTeamMemberParams{
+		TeamID: teamID,
+		UserID: claims.UserId,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot remove user %s from team %v, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			teamID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf(
+			"%w, you are not a member of the team",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after leaving team, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+
+	return nil
+}
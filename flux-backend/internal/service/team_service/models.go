@@ -0,0 +1,45 @@
+package team_service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+type TeamService struct {
+	DB                *database.Queries
+	UserServiceConfig *user_service.UserService
+}
+
+type Team struct {
+	ID        uuid.UUID    `json:"team_id"`
+	Name      string       `json:"name" validate:"required,min=3,max=50"`
+	MaxSize   int32        `json:"max_size" validate:"required,min=1,max=10"`
+	CreatedBy uuid.UUID    `json:"created_by"`
+	Members   []TeamMember `json:"members"`
+}
+
+type TeamMember struct {
+	UserName string    `json:"user_name"`
+	RollNo   string    `json:"roll_no"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+type TeamInvite struct {
+	TeamID    uuid.UUID `json:"team_id"`
+	TeamName  string    `json:"team_name"`
+	InvitedBy string    `json:"invited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type InviteRequest struct {
+	TeamID   uuid.UUID `json:"team_id"`
+	UserName string    `json:"user_name" validate:"required"`
+}
+
+type RespondToInviteRequest struct {
+	TeamID uuid.UUID `json:"team_id"`
+	Accept bool      `json:"accept"`
+}
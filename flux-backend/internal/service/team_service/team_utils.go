@@ -0,0 +1,149 @@
+package team_service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+)
+
+func (t *TeamService) getTeam(
+	ctx context.Context,
+	id uuid.UUID,
+) (database.Team, error) {
+	dbTeam, err := t.DB.GetTeamByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return database.Team{}, fmt.Errorf(
+				"%w, team with id %v does not exist",
+				flux_errors.ErrNotFound,
+				id,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch team with id %v, %w",
+			flux_errors.ErrInternal,
+			id,
+			err,
+		)
+		log.Error(err)
+		return database.Team{}, err
+	}
+
+	return dbTeam, nil
+}
+
+func (t *TeamService) getTeamMembers(
+	ctx context.Context,
+	teamID uuid.UUID,
+) ([]TeamMember, error) {
+	dbMembers, err := t.DB.GetTeamMembers(ctx, teamID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch members of team %v, %w",
+			flux_errors.ErrInternal,
+			teamID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	members := make([]TeamMember, 0, len(dbMembers))
+	for _, dbMember := range dbMembers {
+		members = append(members, TeamMember{
+			UserName: dbMember.UserName,
+			RollNo:   dbMember.RollNo,
+			JoinedAt: dbMember.JoinedAt,
+		})
+	}
+
+	return members, nil
+}
+
+// lockTeam locks the team row till the end of the transaction and
+// returns its max size
+func (t *TeamService) lockTeam(
+	ctx context.Context,
+	qtx *database.Queries,
+	teamID uuid.UUID,
+) (int32, error) {
+	maxSize, err := qtx.LockTeamForUpdate(ctx, teamID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf(
+				"%w, team with id %v does not exist",
+				flux_errors.ErrNotFound,
+				teamID,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot lock team %v, %w",
+			flux_errors.ErrInternal,
+			teamID,
+			err,
+		)
+		log.Error(err)
+		return 0, err
+	}
+
+	return maxSize, nil
+}
+
+// IsTeamMember reports whether the user is a member of the team
+func (t *TeamService) IsTeamMember(
+	ctx context.Context,
+	teamID uuid.UUID,
+	userID uuid.UUID,
+) (bool, error) {
+	member, err := t.DB.IsUserInTeam(ctx, database.IsUserInTeamParams{
+		TeamID: teamID,
+		UserID: userID,
+	})
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check if user %v is in team %v, %w",
+			flux_errors.ErrInternal,
+			userID,
+			teamID,
+			err,
+		)
+		log.Error(err)
+		return false, err
+	}
+
+	return member, nil
+}
+
+// validateRosterChange ensures members don't change while the team is
+// registered in a contest which hasn't ended yet
+func (t *TeamService) validateRosterChange(
+	ctx context.Context,
+	qtx *database.Queries,
+	teamID uuid.UUID,
+) error {
+	frozen, err := qtx.IsTeamInUnfinishedContest(ctx, teamID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot check contests of team %v, %w",
+			flux_errors.ErrInternal,
+			teamID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if frozen {
+		return fmt.Errorf(
+			"%w, members cannot change while the team is registered in an unfinished contest",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,383 @@
+package tournament_service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/contest_service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (t *TournamentService) UpdateRoundAdvancement(
+	ctx context.Context,
+	request UpdateRoundAdvancementRequest,
+) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// authorize (only managers can change advancement rules)
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
+		fmt.Sprintf(
+			"user %s tried to change advancement of round %v of tournament %v",
+			claims.UserName,
+			request.RoundNumber,
+			request.TournamentID,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+	err = validateRoundAdvancement(
+		request.Advancement,
+		request.AdvanceK,
+		request.AdvanceThreshold,
+	)
+	if err != nil {
+		return err
+	}
+
+	// update
+	n, err := t.DB.UpdateTournamentRoundAdvancement(
+		ctx,
+		database.UpdateTournamentRoundAdvancementParams{
+			TournamentID:     request.TournamentID,
+			RoundNumber:      request.RoundNumber,
+			Advancement:      database.RoundAdvancement(request.Advancement),
+			AdvanceK:         request.AdvanceK,
+			AdvanceThreshold: request.AdvanceThreshold,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot update advancement of round %v of tournament %v, %w",
+			flux_errors.ErrInternal,
+			request.RoundNumber,
+			request.TournamentID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, invalid tournament id or round number",
+			flux_errors.ErrNotFound,
+		)
+	}
+
+	log.Infof(
+		"advancement of round %v of tournament %v changed to %s by %s",
+		request.RoundNumber,
+		request.TournamentID,
+		request.Advancement,
+		claims.UserName,
+	)
+
+	return nil
+}
+
+// AdvanceTournamentRound registers the qualifiers of the previous round, once
+// it has ended, into the contests of the given round that have not started,
+// already registered users are skipped
+func (t *TournamentService) AdvanceTournamentRound(
+	ctx context.Context,
+	request AdvanceRoundRequest,
+) ([]string, error) {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize (only managers can advance users)
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
+		fmt.Sprintf(
+			"user %s tried to advance users to round %v of tournament %v",
+			claims.UserName,
+			request.RoundNumber,
+			request.TournamentID,
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// get the round and the one before it
+	round, err := t.getRoundByNumber(ctx, request.TournamentID, request.RoundNumber)
+	if err != nil {
+		return nil, err
+	}
+	prevRound, err := t.getRoundByNumber(ctx, request.TournamentID, request.RoundNumber-1)
+	if err != nil {
+		return nil, err
+	}
+	if prevRound.Advancement == AdvanceNone {
+		return nil, fmt.Errorf(
+			"%w, round %v has no advancement rules",
+			flux_errors.ErrInvalidRequest,
+			prevRound.RoundNumber,
+		)
+	}
+
+	// get the contests of the round
+	contestIDs, err := t.DB.GetTournamentContests(ctx, round.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch contests of round %v of tournament %v, %w",
+			flux_errors.ErrInternal,
+			round.RoundNumber,
+			request.TournamentID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+	if len(contestIDs) == 0 {
+		return nil, fmt.Errorf(
+			"%w, round %v has no contests",
+			flux_errors.ErrInvalidRequest,
+			round.RoundNumber,
+		)
+	}
+	contests, err := t.ContestServiceConfig.GetContestsByFilters(
+		ctx,
+		contest_service.GetContestRequest{
+			ContestIDs: contestIDs,
+			PageNumber: 1,
+			PageSize:   int32(len(contestIDs)),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// start a transaction
+	tx, err := service.GetNewTransaction(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// if anything goes wrong roll back
+	defer tx.Rollback(ctx)
+
+	// get a new query tool with this transaction
+	qtx := t.DB.WithTx(tx)
+
+	// register the qualifiers
+	qualifiers, err := t.registerQualifiers(ctx, qtx, prevRound, contests)
+	if err != nil {
+		return nil, err
+	}
+
+	// commit the transaction
+	if err = tx.Commit(ctx); err != nil {
+		err = fmt.Errorf(
+			"%w, cannot commit transaction after advancing users, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	log.Infof(
+		"%v users advanced to round %v of tournament %v by %s",
+		len(qualifiers),
+		round.RoundNumber,
+		request.TournamentID,
+		claims.UserName,
+	)
+
+	return qualifiers, nil
+}
+
+// registerQualifiers registers the users qualified from prevRound into
+// the individual contests, team contests register their teams themselves
+func (t *TournamentService) registerQualifiers(
+	ctx context.Context,
+	qtx *database.Queries,
+	prevRound TournamentRound,
+	contests []contest_service.Contest,
+) ([]string, error) {
+	if prevRound.Advancement == AdvanceNone {
+		return nil, nil
+	}
+
+	// qualifiers are picked only from the final scores
+	endTime, err := qtx.GetTournamentRoundEndTime(ctx, prevRound.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot get end time of round %v, %w",
+			flux_errors.ErrInternal,
+			prevRound.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+	if time.Now().Before(endTime) {
+		return nil, fmt.Errorf(
+			"%w, round %v has not ended yet",
+			flux_errors.ErrInvalidRequest,
+			prevRound.RoundNumber,
+		)
+	}
+
+	qualifiers, err := t.getRoundQualifiers(ctx, prevRound)
+	if err != nil {
+		return nil, err
+	}
+	if len(qualifiers) == 0 {
+		return qualifiers, nil
+	}
+
+	for _, contest := range contests {
+		if contest.TeamContest {
+			log.Infof(
+				"skipping team contest %v while advancing users from round %v",
+				contest.ID,
+				prevRound.RoundNumber,
+			)
+			continue
+		}
+		// anyone can participate in a published contest, it has no registered users
+		if contest.IsPublished {
+			log.Infof(
+				"skipping published contest %v while advancing users from round %v",
+				contest.ID,
+				prevRound.RoundNumber,
+			)
+			continue
+		}
+		// dbContestToServiceContest ensures start time is not nil
+		if !time.Now().Before(*contest.StartTime) {
+			return nil, fmt.Errorf(
+				"%w, contest %s has already started, users cannot be advanced into it",
+				flux_errors.ErrInvalidRequest,
+				contest.Title,
+			)
+		}
+		err = t.ContestServiceConfig.AddUsersToContestWithTx(ctx, qtx, contest.ID, qualifiers)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return qualifiers, nil
+}
+
+// getRoundQualifiers returns the user names of the users who
+// qualify from the round as per its advancement rules
+func (t *TournamentService) getRoundQualifiers(
+	ctx context.Context,
+	round TournamentRound,
+) ([]string, error) {
+	rows, err := t.DB.GetTournamentRoundUserScores(ctx, round.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user scores of round %v, %w",
+			flux_errors.ErrInternal,
+			round.ID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	var qualifiers []string
+	switch round.Advancement {
+	case AdvanceTopKPerContest:
+		// top k of every contest, a user qualifies once
+		contestScores := make(map[uuid.UUID]map[string]float64)
+		for _, row := range rows {
+			if contestScores[row.ContestID] == nil {
+				contestScores[row.ContestID] = make(map[string]float64)
+			}
+			contestScores[row.ContestID][row.UserName] = float64(row.Score)
+		}
+		qualified := make(map[string]bool)
+		for _, scores := range contestScores {
+			for _, userName := range topKWithTies(scores, int(*round.AdvanceK)) {
+				qualified[userName] = true
+			}
+		}
+		for userName := range qualified {
+			qualifiers = append(qualifiers, userName)
+		}
+	default:
+		// combine the contests as the round is scored
+		contestScores := make(map[string][]roundContestScore)
+		for _, row := range rows {
+			contestScores[row.UserName] = append(contestScores[row.UserName], roundContestScore{
+				scoring: round.Scoring,
+				bestN:   round.BestN,
+				weight:  row.Weight,
+				score:   row.Score,
+			})
+		}
+		roundScores := make(map[string]float64, len(contestScores))
+		for userName, scores := range contestScores {
+			roundScores[userName] = combineRoundScores(scores)
+		}
+
+		if round.Advancement == AdvanceTopK {
+			qualifiers = topKWithTies(roundScores, int(*round.AdvanceK))
+			break
+		}
+		for userName, score := range roundScores {
+			if score >= *round.AdvanceThreshold {
+				qualifiers = append(qualifiers, userName)
+			}
+		}
+	}
+
+	slices.Sort(qualifiers)
+	return qualifiers, nil
+}
+
+// topKWithTies returns the users ranked in the top k,
+// users tied with the k-th one are included as well
+func topKWithTies(scores map[string]float64, k int) []string {
+	values := make([]float64, 0, len(scores))
+	for _, score := range scores {
+		values = append(values, score)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	slices.SortFunc(values, func(a, b float64) int { return cmp.Compare(b, a) })
+	cutoff := values[min(k, len(values))-1]
+
+	var res []string
+	for userName, score := range scores {
+		if score >= cutoff {
+			res = append(res, userName)
+		}
+	}
+	return res
+}
@@ -27,8 +27,8 @@ func (t *TournamentService) ChangeTournamentContests(
 	}
 
 	// authorize (only managers can add contests to a tournament)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
 		fmt.Sprintf(
 			"user %s tried to add a contest to a tournament %v in round %v",
 			claims.UserName,
@@ -40,6 +40,18 @@ func (t *TournamentService) ChangeTournamentContests(
 		return nil, err
 	}
 
+	// validate the request
+	err = service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+	if request.Weights != nil && len(request.Weights) != len(request.ContestIDs) {
+		return nil, fmt.Errorf(
+			"%w, number of weights must match the number of contests",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
 	// fetch tournament by ID to check if it exists
 	_, err = t.GetTournamentByID(ctx, request.TournamentID)
 	if err != nil {
@@ -118,6 +130,15 @@ func (t *TournamentService) ChangeTournamentContests(
 		return nil, err
 	}
 
+	// weight of each contest
+	weights := make(map[uuid.UUID]int32, len(request.ContestIDs))
+	for i, contestID := range request.ContestIDs {
+		weights[contestID] = defaultContestWeight
+		if request.Weights != nil {
+			weights[contestID] = request.Weights[i]
+		}
+	}
+
 	// add contests to the tournament round
 	for _, contest := range contests {
 		err = qtx.AddTournamentContest(
@@ -125,6 +146,7 @@ func (t *TournamentService) ChangeTournamentContests(
 			database.AddTournamentContestParams{
 				RoundID:   latestRound.ID,
 				ContestID: contest.ID,
+				Weight:    weights[contest.ID],
 			},
 		)
 		if err != nil {
@@ -140,6 +162,18 @@ func (t *TournamentService) ChangeTournamentContests(
 		}
 	}
 
+	// qualifiers of the previous round advance as soon as the round gets its contests
+	if latestRound.RoundNumber > 1 {
+		prevRound, err := t.getRoundByNumber(ctx, request.TournamentID, latestRound.RoundNumber-1)
+		if err != nil {
+			return nil, err
+		}
+		_, err = t.registerQualifiers(ctx, qtx, prevRound, contests)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// commit the transaction
 	if err = tx.Commit(ctx); err != nil {
 		err = fmt.Errorf(
@@ -151,6 +185,9 @@ func (t *TournamentService) ChangeTournamentContests(
 		return nil, err
 	}
 
+	// cached standings were computed with the old contests and weights
+	t.standingsCache.Remove(request.TournamentID)
+
 	return contests, nil
 }
 
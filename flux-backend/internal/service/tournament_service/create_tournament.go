@@ -22,8 +22,8 @@ func (t *TournamentService) CreateTournament(
 	}
 
 	// authorize (only managers can create a tournament)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
 		fmt.Sprintf("user %s tried to create a tournament", claims.UserName),
 	)
 	if err != nil {
@@ -25,8 +25,8 @@ func (t *TournamentService) CreateTournamentRound(
 	}
 
 	// authorize (only managers can create a tournament round)
-	err = t.UserServiceConfig.AuthorizeUserRole(
-		ctx, user_service.RoleManager,
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
 		fmt.Sprintf("user %s tried to create a tournament round", claims.UserName),
 	)
 	if err != nil {
@@ -64,6 +64,28 @@ func (t *TournamentService) CreateTournamentRound(
 		return TournamentRound{}, err
 	}
 
+	// validate scoring
+	if tournamentRound.Scoring == "" {
+		tournamentRound.Scoring = ScoringSum
+	}
+	err = validateRoundScoring(tournamentRound.Scoring, tournamentRound.BestN)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
+	// validate advancement
+	if tournamentRound.Advancement == "" {
+		tournamentRound.Advancement = AdvanceNone
+	}
+	err = validateRoundAdvancement(
+		tournamentRound.Advancement,
+		tournamentRound.AdvanceK,
+		tournamentRound.AdvanceThreshold,
+	)
+	if err != nil {
+		return TournamentRound{}, err
+	}
+
 	// validate new round's lock
 	if tournamentRound.LockID == nil {
 		return TournamentRound{}, fmt.Errorf(
@@ -79,11 +101,16 @@ func (t *TournamentService) CreateTournamentRound(
 	// create tournament round
 	dbRound, err := t.DB.CreateTournamentRound(ctx,
 		database.CreateTournamentRoundParams{
-			TournamentID: tournamentRound.TournamentID,
-			LockID:       tournamentRound.LockID,
-			Title:        tournamentRound.Title,
-			RoundNumber:  tournament.Rounds + 1,
-			CreatedBy:    claims.UserId,
+			TournamentID:     tournamentRound.TournamentID,
+			LockID:           tournamentRound.LockID,
+			Title:            tournamentRound.Title,
+			RoundNumber:      tournament.Rounds + 1,
+			CreatedBy:        claims.UserId,
+			Scoring:          database.RoundScoring(tournamentRound.Scoring),
+			BestN:            tournamentRound.BestN,
+			Advancement:      database.RoundAdvancement(tournamentRound.Advancement),
+			AdvanceK:         tournamentRound.AdvanceK,
+			AdvanceThreshold: tournamentRound.AdvanceThreshold,
 		},
 	)
 	if err != nil {
@@ -118,11 +145,16 @@ func (t *TournamentService) CreateTournamentRound(
 
 	// return response
 	return TournamentRound{
-		ID:           dbRound.ID,
-		TournamentID: dbRound.TournamentID,
-		Title:        dbRound.Title,
-		RoundNumber:  dbRound.RoundNumber,
-		LockID:       dbRound.LockID,
-		CreatedBy:    dbRound.CreatedBy,
+		ID:               dbRound.ID,
+		TournamentID:     dbRound.TournamentID,
+		Title:            dbRound.Title,
+		RoundNumber:      dbRound.RoundNumber,
+		LockID:           dbRound.LockID,
+		CreatedBy:        dbRound.CreatedBy,
+		Scoring:          RoundScoring(dbRound.Scoring),
+		BestN:            dbRound.BestN,
+		Advancement:      RoundAdvancement(dbRound.Advancement),
+		AdvanceK:         dbRound.AdvanceK,
+		AdvanceThreshold: dbRound.AdvanceThreshold,
 	}, nil
 }
@@ -0,0 +1,246 @@
+package tournament_service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// users and teams are ranked together, but are different participants
+type participantKey struct {
+	id     uuid.UUID
+	isTeam bool
+}
+
+// score of a participant in one contest of a round
+type roundContestScore struct {
+	scoring RoundScoring
+	bestN   *int32
+	weight  int32
+	score   int32
+}
+
+type participantScores struct {
+	entry  StandingsEntry
+	rounds map[int32][]roundContestScore
+}
+
+func (t *TournamentService) GetTournamentStandings(
+	ctx context.Context,
+	request GetStandingsRequest,
+) ([]StandingsEntry, error) {
+	// validate
+	err := service.ValidateInput(request)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if the tournament exists
+	_, err = t.GetTournamentByID(ctx, request.TournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// standings change only when a contest ends or its scores change
+	version, err := t.DB.GetTournamentStandingsVersion(ctx, request.TournamentID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot get standings version of tournament %v, %w",
+			flux_errors.ErrInternal,
+			request.TournamentID,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// recompute if a contest ended or a late verdict came after they were cached
+	standings, ok := t.standingsCache.Get(request.TournamentID)
+	if ok && standings.version.Equal(version) {
+		log.Debugf("standingsCache hit for tournament %v", request.TournamentID)
+	} else {
+		standings, err = t.computeStandings(ctx, request.TournamentID, version)
+		if err != nil {
+			return nil, err
+		}
+		t.standingsCache.Add(request.TournamentID, standings)
+	}
+
+	// paginate
+	offset := int((request.PageNumber - 1) * request.PageSize)
+	if offset >= len(standings.entries) {
+		return []StandingsEntry{}, nil
+	}
+	end := min(offset+int(request.PageSize), len(standings.entries))
+
+	return standings.entries[offset:end], nil
+}
+
+func (t *TournamentService) computeStandings(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+	version time.Time,
+) (tournamentStandings, error) {
+	participants := make(map[participantKey]*participantScores)
+	getParticipant := func(key participantKey, entry StandingsEntry) *participantScores {
+		p, ok := participants[key]
+		if !ok {
+			p = &participantScores{
+				entry:  entry,
+				rounds: make(map[int32][]roundContestScore),
+			}
+			participants[key] = p
+		}
+		return p
+	}
+
+	// individual contests
+	userRows, err := t.DB.GetTournamentUserScores(
+		ctx,
+		database.GetTournamentUserScoresParams{
+			TournamentID: tournamentID,
+			EndedBefore:  version,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user scores of tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournamentID,
+			err,
+		)
+		log.Error(err)
+		return tournamentStandings{}, err
+	}
+	for _, row := range userRows {
+		p := getParticipant(
+			participantKey{id: row.UserID},
+			StandingsEntry{UserName: row.UserName, RollNo: row.RollNo},
+		)
+		p.rounds[row.RoundNumber] = append(p.rounds[row.RoundNumber], roundContestScore{
+			scoring: RoundScoring(row.Scoring),
+			bestN:   row.BestN,
+			weight:  row.Weight,
+			score:   row.Score,
+		})
+	}
+
+	// team contests
+	teamRows, err := t.DB.GetTournamentTeamScores(
+		ctx,
+		database.GetTournamentTeamScoresParams{
+			TournamentID: tournamentID,
+			EndedBefore:  version,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch team scores of tournament %v, %w",
+			flux_errors.ErrInternal,
+			tournamentID,
+			err,
+		)
+		log.Error(err)
+		return tournamentStandings{}, err
+	}
+	for _, row := range teamRows {
+		p := getParticipant(
+			participantKey{id: row.TeamID, isTeam: true},
+			StandingsEntry{TeamName: row.TeamName},
+		)
+		p.rounds[row.RoundNumber] = append(p.rounds[row.RoundNumber], roundContestScore{
+			scoring: RoundScoring(row.Scoring),
+			bestN:   row.BestN,
+			weight:  row.Weight,
+			score:   row.Score,
+		})
+	}
+
+	// combine the rounds
+	entries := make([]StandingsEntry, 0, len(participants))
+	for _, p := range participants {
+		entry := p.entry
+		entry.RoundScores = make(map[int32]float64, len(p.rounds))
+		for roundNumber, scores := range p.rounds {
+			score := combineRoundScores(scores)
+			entry.RoundScores[roundNumber] = score
+			entry.TotalScore += score
+		}
+		entries = append(entries, entry)
+	}
+
+	// rank, equal totals share a rank
+	slices.SortFunc(entries, func(a, b StandingsEntry) int {
+		if a.TotalScore != b.TotalScore {
+			return cmp.Compare(b.TotalScore, a.TotalScore)
+		}
+		return strings.Compare(participantName(a), participantName(b))
+	})
+	for i := range entries {
+		if i > 0 && entries[i].TotalScore == entries[i-1].TotalScore {
+			entries[i].Rank = entries[i-1].Rank
+			continue
+		}
+		entries[i].Rank = int32(i + 1)
+	}
+
+	log.Infof(
+		"computed standings of tournament %v with %v participants",
+		tournamentID,
+		len(entries),
+	)
+
+	return tournamentStandings{
+		version: version,
+		entries: entries,
+	}, nil
+}
+
+// combineRoundScores combines the contest scores of a participant
+// in a round according to the round's scoring
+func combineRoundScores(scores []roundContestScore) float64 {
+	// every contest of a round has the same scoring
+	scoring, bestN := scores[0].scoring, scores[0].bestN
+
+	var total float64
+	switch scoring {
+	case ScoringBestN:
+		best := make([]int32, 0, len(scores))
+		for _, s := range scores {
+			best = append(best, s.score)
+		}
+		slices.SortFunc(best, func(a, b int32) int { return cmp.Compare(b, a) })
+		if bestN != nil && int(*bestN) < len(best) {
+			best = best[:*bestN]
+		}
+		for _, score := range best {
+			total += float64(score)
+		}
+	case ScoringWeighted:
+		for _, s := range scores {
+			total += float64(s.score) * float64(s.weight) / 100
+		}
+	default:
+		for _, s := range scores {
+			total += float64(s.score)
+		}
+	}
+
+	return total
+}
+
+func participantName(entry StandingsEntry) string {
+	if entry.TeamName != "" {
+		return entry.TeamName
+	}
+	return entry.UserName
+}
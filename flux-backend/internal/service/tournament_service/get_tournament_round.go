@@ -53,14 +53,20 @@ func (t *TournamentService) GetTournamentRound(
 
 	// create a service TournamentRound
 	serviceTournamentRound := TournamentRound{
-		ID:           round.ID,
-		TournamentID: round.TournamentID,
-		Title:        round.Title,
-		RoundNumber:  round.RoundNumber,
-		LockID:       round.LockID,
-		CreatedBy:    round.CreatedBy,
-		LockAccess:   &access,
-		LockTimeout:  round.Timeout,
+		ID:               round.ID,
+		TournamentID:     round.TournamentID,
+		Title:            round.Title,
+		RoundNumber:      round.RoundNumber,
+		LockID:           round.LockID,
+		CreatedBy:        round.CreatedBy,
+		Scoring:          RoundScoring(round.Scoring),
+		BestN:            round.BestN,
+		Advancement:      RoundAdvancement(round.Advancement),
+		AdvanceK:         round.AdvanceK,
+		AdvanceThreshold: round.AdvanceThreshold,
+		LockAccess:       &access,
+		LockTimeout:      round.Timeout,
+		LockReleasedAt:   round.ReleasedAt,
 	}
 
 	// authourize if it has a lock
@@ -79,7 +85,9 @@ func (t *TournamentService) GetTournamentRound(
 		// authourize
 		if err = t.LockServiceConfig.AuthorizeLock(
 			ctx,
+			*serviceTournamentRound.LockID,
 			serviceTournamentRound.LockTimeout,
+			serviceTournamentRound.LockReleasedAt,
 			*serviceTournamentRound.LockAccess,
 			"",
 		); err != nil {
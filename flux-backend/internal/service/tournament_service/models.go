@@ -4,12 +4,27 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/service/contest_service"
 	"github.com/tcp_snm/flux/internal/service/lock_service"
 	"github.com/tcp_snm/flux/internal/service/user_service"
 )
 
+const (
+	ScoringSum            RoundScoring     = "sum"
+	ScoringBestN          RoundScoring     = "best_n"
+	ScoringWeighted       RoundScoring     = "weighted"
+	AdvanceNone           RoundAdvancement = "none"
+	AdvanceTopK           RoundAdvancement = "top_k"
+	AdvanceTopKPerContest RoundAdvancement = "top_k_per_contest"
+	AdvanceThreshold      RoundAdvancement = "threshold"
+	// weight of a contest (in percent) when not provided
+	defaultContestWeight   = 100
+	standingsCacheCapacity = 20
+)
+
 var dbConstraintMessages = map[string]string{
 	"fk_rounds_tournament": "Tournament does not exist.",
 }
@@ -19,8 +34,26 @@ type TournamentService struct {
 	ContestServiceConfig *contest_service.ContestService
 	UserServiceConfig    *user_service.UserService
 	LockServiceConfig    *lock_service.LockService
+	standingsCache       *lru.Cache[uuid.UUID, tournamentStandings]
 }
 
+func (t *TournamentService) IntializeTournamentService() error {
+	log.Infof(
+		"intializing uuid->tournamentStandings (standingsCache) cache with capacity %d",
+		standingsCacheCapacity,
+	)
+	cache, err := lru.New[uuid.UUID, tournamentStandings](standingsCacheCapacity)
+	if err != nil {
+		return err
+	}
+	t.standingsCache = cache
+	return nil
+}
+
+type RoundScoring string
+
+type RoundAdvancement string
+
 type Tournament struct {
 	ID          uuid.UUID `json:"id"`
 	Title       string    `json:"title" validate:"min=5,max=100"`
@@ -36,17 +69,70 @@ type TournamentRound struct {
 	RoundNumber  int32      `json:"round_no"`
 	LockID       *uuid.UUID `json:"lock_id"`
 	CreatedBy    uuid.UUID  `json:"created_by"`
+	// defaults to sum
+	Scoring RoundScoring `json:"scoring" validate:"omitempty,oneof=sum best_n weighted"`
+	BestN   *int32       `json:"best_n" validate:"omitempty,min=1,max=100"`
+	// who advances to the next round, defaults to none
+	Advancement      RoundAdvancement `json:"advancement" validate:"omitempty,oneof=none top_k top_k_per_contest threshold"`
+	AdvanceK         *int32           `json:"advance_k" validate:"omitempty,min=1,max=10000"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
 
 	// fields used internally
 	LockAccess *user_service.UserRole `json:"-"`
 	// currently this field is nil-only
-	LockTimeout *time.Time `json:"-"`
+	LockTimeout    *time.Time `json:"-"`
+	LockReleasedAt *time.Time `json:"-"`
 }
 
 type ChangeTournamentContestsRequest struct {
 	TournamentID uuid.UUID   `json:"tournament_id"`
 	RoundNumber  int32       `json:"round_no"`
 	ContestIDs   []uuid.UUID `json:"contest_ids"`
+	// optional, weight (in percent) of each contest in the same order as contest_ids
+	Weights []int32 `json:"weights" validate:"omitempty,dive,min=0,max=1000"`
+}
+
+type UpdateRoundScoringRequest struct {
+	TournamentID uuid.UUID    `json:"tournament_id"`
+	RoundNumber  int32        `json:"round_no"`
+	Scoring      RoundScoring `json:"scoring" validate:"required,oneof=sum best_n weighted"`
+	BestN        *int32       `json:"best_n" validate:"omitempty,min=1,max=100"`
+}
+
+type UpdateRoundAdvancementRequest struct {
+	TournamentID     uuid.UUID        `json:"tournament_id"`
+	RoundNumber      int32            `json:"round_no"`
+	Advancement      RoundAdvancement `json:"advancement" validate:"required,oneof=none top_k top_k_per_contest threshold"`
+	AdvanceK         *int32           `json:"advance_k" validate:"omitempty,min=1,max=10000"`
+	AdvanceThreshold *float64         `json:"advance_threshold"`
+}
+
+type AdvanceRoundRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	RoundNumber  int32     `json:"round_no" validate:"min=2"`
+}
+
+type GetStandingsRequest struct {
+	TournamentID uuid.UUID `json:"tournament_id"`
+	PageNumber   int32     `json:"page_number" validate:"min=1,max=10000"`
+	PageSize     int32     `json:"page_size" validate:"min=0,max=10000"`
+}
+
+// either user fields or team name is set depending on
+// whether the participant competed individually or as a team
+type StandingsEntry struct {
+	Rank        int32             `json:"rank"`
+	UserName    string            `json:"user_name,omitempty"`
+	RollNo      string            `json:"roll_no,omitempty"`
+	TeamName    string            `json:"team_name,omitempty"`
+	TotalScore  float64           `json:"total_score"`
+	RoundScores map[int32]float64 `json:"round_scores"`
+}
+
+// standings computed at a version, see GetTournamentStandingsVersion
+type tournamentStandings struct {
+	version time.Time
+	entries []StandingsEntry
 }
 
 type GetTournamentRequest struct {
@@ -2,9 +2,12 @@ package tournament_service
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 	"github.com/tcp_snm/flux/internal/flux_errors"
 )
@@ -29,3 +32,108 @@ func (t *TournamentService) validateTournamentRoundLock(
 
 	return nil
 }
+
+func validateRoundScoring(scoring RoundScoring, bestN *int32) error {
+	// best_n is meaningful only for best_n rounds
+	if scoring == ScoringBestN && bestN == nil {
+		return fmt.Errorf(
+			"%w, best_n is required for best_n scoring",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if scoring != ScoringBestN && bestN != nil {
+		return fmt.Errorf(
+			"%w, best_n is only used by best_n scoring",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+func validateRoundAdvancement(
+	advancement RoundAdvancement,
+	advanceK *int32,
+	advanceThreshold *float64,
+) error {
+	// advance_k is meaningful only for top k rules
+	topK := advancement == AdvanceTopK || advancement == AdvanceTopKPerContest
+	if topK && advanceK == nil {
+		return fmt.Errorf(
+			"%w, advance_k is required for %s advancement",
+			flux_errors.ErrInvalidRequest,
+			advancement,
+		)
+	}
+	if !topK && advanceK != nil {
+		return fmt.Errorf(
+			"%w, advance_k is only used by top_k and top_k_per_contest advancement",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// advance_threshold is meaningful only for threshold rules
+	if advancement == AdvanceThreshold && advanceThreshold == nil {
+		return fmt.Errorf(
+			"%w, advance_threshold is required for threshold advancement",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+	if advancement != AdvanceThreshold && advanceThreshold != nil {
+		return fmt.Errorf(
+			"%w, advance_threshold is only used by threshold advancement",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	return nil
+}
+
+// getRoundByNumber fetches the round without authorizing its lock
+func (t *TournamentService) getRoundByNumber(
+	ctx context.Context,
+	tournamentID uuid.UUID,
+	roundNumber int32,
+) (TournamentRound, error) {
+	round, err := t.DB.GetTournamentRoundByNumber(
+		ctx,
+		database.GetTournamentRoundByNumberParams{
+			TournamentID: tournamentID,
+			RoundNumber:  roundNumber,
+		},
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TournamentRound{}, fmt.Errorf(
+				"%w, tournament %v has no round %v",
+				flux_errors.ErrNotFound,
+				tournamentID,
+				roundNumber,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot fetch round %v of tournament %v, %w",
+			flux_errors.ErrInternal,
+			roundNumber,
+			tournamentID,
+			err,
+		)
+		log.Error(err)
+		return TournamentRound{}, err
+	}
+
+	return TournamentRound{
+		ID:               round.ID,
+		TournamentID:     round.TournamentID,
+		Title:            round.Title,
+		RoundNumber:      round.RoundNumber,
+		LockID:           round.LockID,
+		CreatedBy:        round.CreatedBy,
+		Scoring:          RoundScoring(round.Scoring),
+		BestN:            round.BestN,
+		Advancement:      RoundAdvancement(round.Advancement),
+		AdvanceK:         round.AdvanceK,
+		AdvanceThreshold: round.AdvanceThreshold,
+		LockTimeout:      round.Timeout,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package tournament_service
+
+import (
+	"context"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+	"github.com/tcp_snm/flux/internal/service/user_service"
+)
+
+func (t *TournamentService) UpdateRoundScoring(
+	ctx context.Context,
+	request UpdateRoundScoringRequest,
+) error {
+	// fetch claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	// authorize (only managers can change how a round is scored)
+	err = t.UserServiceConfig.AuthorizePermission(
+		ctx, user_service.PermTournamentManage,
+		fmt.Sprintf(
+			"user %s tried to change scoring of round %v of tournament %v",
+			claims.UserName,
+			request.RoundNumber,
+			request.TournamentID,
+		),
+	)
+	if err != nil {
+		return err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return err
+	}
+	err = validateRoundScoring(request.Scoring, request.BestN)
+	if err != nil {
+		return err
+	}
+
+	// update
+	n, err := t.DB.UpdateTournamentRoundScoring(
+		ctx,
+		database.UpdateTournamentRoundScoringParams{
+			TournamentID: request.TournamentID,
+			RoundNumber:  request.RoundNumber,
+			Scoring:      database.RoundScoring(request.Scoring),
+			BestN:        request.BestN,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot update scoring of round %v of tournament %v, %w",
+			flux_errors.ErrInternal,
+			request.RoundNumber,
+			request.TournamentID,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, invalid tournament id or round number",
+			flux_errors.ErrNotFound,
+		)
+	}
+
+	// cached standings were computed with the old scoring
+	t.standingsCache.Remove(request.TournamentID)
+
+	log.Infof(
+		"scoring of round %v of tournament %v changed to %s by %s",
+		request.RoundNumber,
+		request.TournamentID,
+		request.Scoring,
+		claims.UserName,
+	)
+
+	return nil
+}
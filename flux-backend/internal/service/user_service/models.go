@@ -1,30 +1,56 @@
 package user_service
 
 import (
+	"time"
+
 	"github.com/google/uuid"
-	lru "github.com/hashicorp/golang-lru/v2"
 	log "github.com/sirupsen/logrus"
 	"github.com/tcp_snm/flux/internal/database"
 )
 
 const (
-	RoleManager   UserRole = "role_manager"
-	RoleHC        UserRole = "role_hc"
-	cacheCapacity          = 50
+	RoleManager UserRole = "role_manager"
+	RoleHC      UserRole = "role_hc"
+)
+
+// permissions are mapped to roles in the db (role_permissions),
+// services must authorize these instead of role names
+const (
+	PermContestManagePublic Permission = "contest.manage_public"
+	PermContestEditAny      Permission = "contest.edit_any"
+	PermLockCreate          Permission = "lock.create"
+	PermLockView            Permission = "lock.view"
+	PermLockEditAny         Permission = "lock.edit_any"
+	PermLockReschedule      Permission = "lock.reschedule"
+	PermProblemCreate       Permission = "problem.create"
+	PermProblemEditAny      Permission = "problem.edit_any"
+	PermSubmissionViewAny   Permission = "submission.view_any"
+	PermTournamentManage    Permission = "tournament.manage"
+	PermRoleManage          Permission = "role.manage"
 )
 
 type UserService struct {
-	DB         *database.Queries
-	rolesCache *lru.Cache[uuid.UUID, []string]
+	DB *database.Queries
+	// defaults to an in process lru, see IntializeUserServices
+	RolesCache  RolesCache
+	generations rolesGenerations
+}
+
+// roles (including the inherited ones) and permissions of a user
+type UserAccess struct {
+	Roles       []string
+	Permissions []string
 }
 
 func (u *UserService) IntializeUserServices() error {
-	log.Infof("intializing uuid->[]string (rolesCache) cache with capacity %d", cacheCapacity)
-	cache, err := lru.New[uuid.UUID, []string](cacheCapacity)
-	if err != nil {
-		return err
+	if u.RolesCache == nil {
+		log.Infof(
+			"intializing uuid->UserAccess (rolesCache) cache with capacity %d and ttl %v",
+			DefaultRolesCacheCapacity,
+			DefaultRolesCacheTTL,
+		)
+		u.RolesCache = NewLRURolesCache(DefaultRolesCacheCapacity, DefaultRolesCacheTTL)
 	}
-	u.rolesCache = cache
 	return nil
 }
 
@@ -38,6 +64,8 @@ type GetUsersRequest struct {
 
 type UserRole string
 
+type Permission string
+
 // this type must be used only when a
 // "multiple" users are being passed
 type UserMetaData struct {
@@ -45,3 +73,72 @@ type UserMetaData struct {
 	UserName string    `json:"user_name"`
 	RollNo   string    `json:"roll_no"`
 }
+
+// the user is identified either by user_name or roll_no
+type ChangeUserRoleRequest struct {
+	UserName string   `json:"user_name"`
+	RollNo   string   `json:"roll_no"`
+	Role     UserRole `json:"role" validate:"required,max=50"`
+}
+
+type RoleHolders struct {
+	Role  string         `json:"role"`
+	Users []UserMetaData `json:"users"`
+}
+
+// the role is identified by name and the permission must exist
+type ChangeRolePermissionRequest struct {
+	Role       UserRole   `json:"role" validate:"required,max=50"`
+	Permission Permission `json:"permission" validate:"required,max=100"`
+}
+
+type RolePermissions struct {
+	Role        string   `json:"role"`
+	Inherits    []string `json:"inherits"`
+	Permissions []string `json:"permissions"`
+}
+
+type RolesCacheStats struct {
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Size       int    `json:"size"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+}
+
+type UserProfile struct {
+	UserName  string    `json:"user_name"`
+	RollNo    string    `json:"roll_no"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+	Roles     []string  `json:"roles"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// user names are chosen by users, generated ones (flux#...)
+// contain a '#' so they never collide with the chosen ones.
+// The current user name is kept if user_name is omitted
+type UpdateProfileRequest struct {
+	UserName  *string `json:"user_name" validate:"omitempty,min=4,max=30"`
+	FirstName string  `json:"first_name" validate:"required,min=4,max=255"`
+	LastName  string  `json:"last_name" validate:"required,min=4,max=255"`
+}
+
+// visible to every user, so roll_no and email are left out
+type PublicProfile struct {
+	UserName       string                `json:"user_name"`
+	FirstName      string                `json:"first_name"`
+	LastName       string                `json:"last_name"`
+	JoinedAt       time.Time             `json:"joined_at"`
+	SolvedCount    int64                 `json:"solved_count"`
+	ContestHistory []ContestHistoryEntry `json:"contest_history"`
+}
+
+type ContestHistoryEntry struct {
+	ContestID uuid.UUID  `json:"contest_id"`
+	Title     string     `json:"title"`
+	StartTime *time.Time `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	Score     int32      `json:"score"`
+	Solved    int64      `json:"solved"`
+}
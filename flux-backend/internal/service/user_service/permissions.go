@@ -0,0 +1,264 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// GetRolePermissions lists every role along with its own permissions
+// and the roles it inherits the rest of its permissions from
+func (u *UserService) GetRolePermissions(ctx context.Context) ([]RolePermissions, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize
+	err = u.AuthorizePermission(
+		ctx, PermRoleManage,
+		fmt.Sprintf("user %s tried to list the role permissions", claims.UserName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch
+	rows, err := u.DB.GetRolePermissions(ctx)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch role permissions, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+	inheritance, err := u.DB.GetRoleInheritance(ctx)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch role inheritance, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// group by role, rows are ordered by role
+	res := make([]RolePermissions, 0)
+	index := make(map[string]int)
+	for _, row := range rows {
+		if len(res) == 0 || res[len(res)-1].Role != row.RoleName {
+			index[row.RoleName] = len(res)
+			res = append(res, RolePermissions{
+				Role:        row.RoleName,
+				Inherits:    make([]string, 0),
+				Permissions: make([]string, 0),
+			})
+		}
+		// role without any permissions of its own
+		if row.Permission == nil {
+			continue
+		}
+		res[len(res)-1].Permissions = append(res[len(res)-1].Permissions, *row.Permission)
+	}
+	for _, edge := range inheritance {
+		i, ok := index[edge.RoleName]
+		if !ok {
+			continue
+		}
+		res[i].Inherits = append(res[i].Inherits, edge.ParentRole)
+	}
+
+	return res, nil
+}
+
+func (u *UserService) GrantRolePermission(
+	ctx context.Context,
+	request ChangeRolePermissionRequest,
+) error {
+	claims, err := u.authorizePermissionChange(ctx, request, "grant")
+	if err != nil {
+		return err
+	}
+
+	// grant
+	n, err := u.DB.GrantRolePermission(
+		ctx,
+		database.GrantRolePermissionParams{
+			RoleName:   string(request.Role),
+			Permission: string(request.Permission),
+		},
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == flux_errors.CodeForeignKeyConstraint {
+			return fmt.Errorf(
+				"%w, role %s or permission %s does not exist",
+				flux_errors.ErrInvalidRequest,
+				request.Role,
+				request.Permission,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot grant permission %s to role %s, %w",
+			flux_errors.ErrInternal,
+			request.Permission,
+			request.Role,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, role %s already has permission %s",
+			flux_errors.ErrInvalidRequest,
+			request.Role,
+			request.Permission,
+		)
+	}
+
+	// any user may hold the role, directly or by inheritance
+	u.InvalidateAllUserRoles()
+
+	log.Infof(
+		"user %s granted permission %s to role %s",
+		claims.UserName,
+		request.Permission,
+		request.Role,
+	)
+	return nil
+}
+
+func (u *UserService) RevokeRolePermission(
+	ctx context.Context,
+	request ChangeRolePermissionRequest,
+) error {
+	claims, err := u.authorizePermissionChange(ctx, request, "revoke")
+	if err != nil {
+		return err
+	}
+
+	// the hc must not lose the ability to fix the mapping
+	if request.Role == RoleHC && request.Permission == PermRoleManage {
+		return fmt.Errorf(
+			"%w, cannot revoke %s from %s",
+			flux_errors.ErrInvalidRequest,
+			PermRoleManage,
+			RoleHC,
+		)
+	}
+
+	// revoke
+	n, err := u.DB.RevokeRolePermission(
+		ctx,
+		database.RevokeRolePermissionParams{
+			RoleName:   string(request.Role),
+			Permission: string(request.Permission),
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot revoke permission %s from role %s, %w",
+			flux_errors.ErrInternal,
+			request.Permission,
+			request.Role,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, role %s does not have permission %s of its own",
+			flux_errors.ErrNotFound,
+			request.Role,
+			request.Permission,
+		)
+	}
+
+	// any user may hold the role, directly or by inheritance
+	u.InvalidateAllUserRoles()
+
+	log.Infof(
+		"user %s revoked permission %s from role %s",
+		claims.UserName,
+		request.Permission,
+		request.Role,
+	)
+	return nil
+}
+
+// InvalidateAllUserRoles drops every cached role and permission,
+// must be called whenever the role to permission mapping changes
+func (u *UserService) InvalidateAllUserRoles() {
+	u.generations.mu.Lock()
+	defer u.generations.mu.Unlock()
+	u.generations.all++
+	u.RolesCache.Purge()
+	log.Debug("invalidated every cached role")
+}
+
+// GetRolesCacheStats reports how well the roles cache of this instance performs
+func (u *UserService) GetRolesCacheStats(ctx context.Context) (RolesCacheStats, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return RolesCacheStats{}, err
+	}
+
+	// authorize
+	err = u.AuthorizePermission(
+		ctx, PermRoleManage,
+		fmt.Sprintf("user %s tried to view the roles cache stats", claims.UserName),
+	)
+	if err != nil {
+		return RolesCacheStats{}, err
+	}
+
+	return u.RolesCache.Stats(), nil
+}
+
+func (u *UserService) authorizePermissionChange(
+	ctx context.Context,
+	request ChangeRolePermissionRequest,
+	action string,
+) (service.UserCredentialClaims, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	// authorize
+	err = u.AuthorizePermission(
+		ctx, PermRoleManage,
+		fmt.Sprintf(
+			"user %s tried to %s permission %s of role %s",
+			claims.UserName,
+			action,
+			request.Permission,
+			request.Role,
+		),
+	)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return service.UserCredentialClaims{}, err
+	}
+
+	return claims, nil
+}
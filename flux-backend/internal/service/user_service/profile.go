@@ -0,0 +1,195 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+const (
+	// only the latest contests are shown on a profile
+	contestHistoryLimit = 20
+)
+
+var (
+	// starts with a letter, only lower case letters, digits, '_' and '.'
+	userNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_.]*$`)
+)
+
+func (u *UserService) GetMyProfile(ctx context.Context) (UserProfile, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	// fetch
+	user, err := u.DB.GetUserById(ctx, claims.UserId)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return UserProfile{}, err
+	}
+	roles, err := u.FetchUserRoles(ctx, user.ID)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	return dbUserToProfile(user, roles), nil
+}
+
+func (u *UserService) UpdateMyProfile(
+	ctx context.Context,
+	request UpdateProfileRequest,
+) (UserProfile, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	// validate, an unchanged user name (even a generated one) is kept as is
+	if request.UserName != nil && *request.UserName == claims.UserName {
+		request.UserName = nil
+	}
+	err = service.ValidateInput(request)
+	if err != nil {
+		return UserProfile{}, err
+	}
+	if request.UserName != nil && !userNameRegex.MatchString(*request.UserName) {
+		return UserProfile{}, fmt.Errorf(
+			"%w, user_name must start with a letter and contain only lower case letters, digits, '_' and '.'",
+			flux_errors.ErrInvalidRequest,
+		)
+	}
+
+	// update
+	user, err := u.DB.UpdateUserProfile(
+		ctx,
+		database.UpdateUserProfileParams{
+			ID:        claims.UserId,
+			FirstName: request.FirstName,
+			LastName:  request.LastName,
+			UserName:  request.UserName,
+		},
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == flux_errors.CodeUniqueConstraintViolation {
+			return UserProfile{}, fmt.Errorf(
+				"%w, user_name %s is already taken",
+				flux_errors.ErrUserAlreadyExists,
+				*request.UserName,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot update profile of user %s, %w",
+			flux_errors.ErrInternal,
+			claims.UserName,
+			err,
+		)
+		log.Error(err)
+		return UserProfile{}, err
+	}
+	roles, err := u.FetchUserRoles(ctx, user.ID)
+	if err != nil {
+		return UserProfile{}, err
+	}
+
+	// claims carry the old user name until the access token is refreshed
+	log.Infof("user %s updated their profile, user_name: %s", claims.UserName, user.UserName)
+
+	return dbUserToProfile(user, roles), nil
+}
+
+// GetPublicProfile shows the user along with the contests they took part in
+func (u *UserService) GetPublicProfile(
+	ctx context.Context,
+	userName string,
+) (PublicProfile, error) {
+	// fetch the user
+	user, err := u.FetchUserByUserName(ctx, userName)
+	if err != nil {
+		// not a login, so the user simply does not exist
+		if errors.Is(err, flux_errors.ErrInvalidUserCredentials) {
+			err = fmt.Errorf("%w, user not found", flux_errors.ErrNotFound)
+		}
+		return PublicProfile{}, err
+	}
+
+	// solved problems
+	solvedCount, err := u.DB.GetUserSolvedCount(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot count problems solved by %s, %w",
+			flux_errors.ErrInternal,
+			user.UserName,
+			err,
+		)
+		log.Error(err)
+		return PublicProfile{}, err
+	}
+
+	// contest history
+	rows, err := u.DB.GetUserContestHistory(
+		ctx,
+		database.GetUserContestHistoryParams{
+			UserID: user.ID,
+			Limit:  contestHistoryLimit,
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch contest history of %s, %w",
+			flux_errors.ErrInternal,
+			user.UserName,
+			err,
+		)
+		log.Error(err)
+		return PublicProfile{}, err
+	}
+	history := make([]ContestHistoryEntry, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, ContestHistoryEntry{
+			ContestID: row.ID,
+			Title:     row.Title,
+			StartTime: row.StartTime,
+			EndTime:   row.EndTime,
+			Score:     row.Score,
+			Solved:    row.Solved,
+		})
+	}
+
+	return PublicProfile{
+		UserName:       user.UserName,
+		FirstName:      user.FirstName,
+		LastName:       user.LastName,
+		JoinedAt:       user.CreatedAt,
+		SolvedCount:    solvedCount,
+		ContestHistory: history,
+	}, nil
+}
+
+func dbUserToProfile(user database.User, roles []string) UserProfile {
+	return UserProfile{
+		UserName:  user.UserName,
+		RollNo:    user.RollNo,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		Roles:     roles,
+		CreatedAt: user.CreatedAt,
+	}
+}
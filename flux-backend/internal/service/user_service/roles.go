@@ -0,0 +1,221 @@
+package user_service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/database"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/service"
+)
+
+// GetRoleHolders lists every role along with the users holding it
+func (u *UserService) GetRoleHolders(ctx context.Context) ([]RoleHolders, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// authorize (only role managers can see the roles)
+	err = u.AuthorizePermission(
+		ctx, PermRoleManage,
+		fmt.Sprintf("user %s tried to list the role holders", claims.UserName),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// fetch
+	rows, err := u.DB.GetRoleHolders(ctx)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot fetch role holders, %w",
+			flux_errors.ErrInternal,
+			err,
+		)
+		log.Error(err)
+		return nil, err
+	}
+
+	// group by role, rows are ordered by role
+	res := make([]RoleHolders, 0)
+	for _, row := range rows {
+		if len(res) == 0 || res[len(res)-1].Role != row.RoleName {
+			res = append(res, RoleHolders{
+				Role:  row.RoleName,
+				Users: make([]UserMetaData, 0),
+			})
+		}
+		// role without any holders
+		if row.UserName == nil || row.RollNo == nil {
+			continue
+		}
+		res[len(res)-1].Users = append(res[len(res)-1].Users, UserMetaData{
+			UserName: *row.UserName,
+			RollNo:   *row.RollNo,
+		})
+	}
+
+	return res, nil
+}
+
+func (u *UserService) GrantRole(
+	ctx context.Context,
+	request ChangeUserRoleRequest,
+) error {
+	claims, user, err := u.authorizeRoleChange(ctx, request, "grant")
+	if err != nil {
+		return err
+	}
+
+	// grant
+	n, err := u.DB.GrantUserRole(
+		ctx,
+		database.GrantUserRoleParams{
+			UserID:   user.ID,
+			RoleName: string(request.Role),
+		},
+	)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == flux_errors.CodeForeignKeyConstraint {
+			return fmt.Errorf(
+				"%w, role %s does not exist",
+				flux_errors.ErrInvalidRequest,
+				request.Role,
+			)
+		}
+		err = fmt.Errorf(
+			"%w, cannot grant role %s to user %s, %w",
+			flux_errors.ErrInternal,
+			request.Role,
+			user.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, user %s already has role %s",
+			flux_errors.ErrInvalidRequest,
+			user.UserName,
+			request.Role,
+		)
+	}
+
+	// roles are cached
+	u.InvalidateUserRoles(user.ID)
+
+	log.Infof("user %s granted role %s to %s", claims.UserName, request.Role, user.UserName)
+	return nil
+}
+
+func (u *UserService) RevokeRole(
+	ctx context.Context,
+	request ChangeUserRoleRequest,
+) error {
+	claims, user, err := u.authorizeRoleChange(ctx, request, "revoke")
+	if err != nil {
+		return err
+	}
+
+	// an hc cannot lock themselves out, another hc must do it
+	if user.ID == claims.UserId && request.Role == RoleHC {
+		return fmt.Errorf(
+			"%w, cannot revoke your own %s role",
+			flux_errors.ErrInvalidRequest,
+			RoleHC,
+		)
+	}
+
+	// revoke
+	n, err := u.DB.RevokeUserRole(
+		ctx,
+		database.RevokeUserRoleParams{
+			UserID:   user.ID,
+			RoleName: string(request.Role),
+		},
+	)
+	if err != nil {
+		err = fmt.Errorf(
+			"%w, cannot revoke role %s from user %s, %w",
+			flux_errors.ErrInternal,
+			request.Role,
+			user.UserName,
+			err,
+		)
+		log.Error(err)
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf(
+			"%w, user %s does not have role %s",
+			flux_errors.ErrNotFound,
+			user.UserName,
+			request.Role,
+		)
+	}
+
+	// roles are cached
+	u.InvalidateUserRoles(user.ID)
+
+	log.Infof("user %s revoked role %s from %s", claims.UserName, request.Role, user.UserName)
+	return nil
+}
+
+// InvalidateUserRoles drops the cached roles and permissions of the user,
+// must be called whenever the roles of a user change. Other instances
+// learn about the change from the db (see ListenRoleChanges)
+func (u *UserService) InvalidateUserRoles(userID uuid.UUID) {
+	u.generations.mu.Lock()
+	defer u.generations.mu.Unlock()
+	u.generations.bumpUser(userID)
+	present := u.RolesCache.Remove(userID)
+	log.Debugf("invalidated cached roles of %v, present: %v", userID, present)
+}
+
+// authorizeRoleChange authorizes the hc and fetches the user whose role changes
+func (u *UserService) authorizeRoleChange(
+	ctx context.Context,
+	request ChangeUserRoleRequest,
+	action string,
+) (service.UserCredentialClaims, database.User, error) {
+	// get claims
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return service.UserCredentialClaims{}, database.User{}, err
+	}
+
+	// authorize (only role managers can change roles)
+	err = u.AuthorizePermission(
+		ctx, PermRoleManage,
+		fmt.Sprintf("user %s tried to %s role %s", claims.UserName, action, request.Role),
+	)
+	if err != nil {
+		return service.UserCredentialClaims{}, database.User{}, err
+	}
+
+	// validate
+	err = service.ValidateInput(request)
+	if err != nil {
+		return service.UserCredentialClaims{}, database.User{}, err
+	}
+
+	// get the user
+	user, err := u.GetUserByUserNameOrRollNo(ctx, request.UserName, request.RollNo)
+	if err != nil {
+		// not a login, so the user simply does not exist
+		if errors.Is(err, flux_errors.ErrInvalidUserCredentials) {
+			err = fmt.Errorf("%w, user not found", flux_errors.ErrNotFound)
+		}
+		return service.UserCredentialClaims{}, database.User{}, err
+	}
+
+	return claims, user, nil
+}
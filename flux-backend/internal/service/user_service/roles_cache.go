@@ -0,0 +1,102 @@
+package user_service
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+const (
+	KeyRolesCacheTTL          = "ROLES_CACHE_TTL"
+	DefaultRolesCacheTTL      = time.Minute * 5
+	DefaultRolesCacheCapacity = 50
+)
+
+/*
+	RolesCache keeps the roles and permissions of recently seen users.
+	Entries must expire on their own, so that an instance which missed an
+	invalidation (e.g. while its listener was reconnecting) heals eventually
+*/
+
+type RolesCache interface {
+	Get(userID uuid.UUID) (UserAccess, bool)
+	Add(userID uuid.UUID, access UserAccess)
+	Remove(userID uuid.UUID) bool
+	Purge()
+	Stats() RolesCacheStats
+}
+
+// LRURolesCache is an in process RolesCache, the default one
+type LRURolesCache struct {
+	cache  *expirable.LRU[uuid.UUID, UserAccess]
+	ttl    time.Duration
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+func NewLRURolesCache(capacity int, ttl time.Duration) *LRURolesCache {
+	return &LRURolesCache{
+		cache: expirable.NewLRU[uuid.UUID, UserAccess](capacity, nil, ttl),
+		ttl:   ttl,
+	}
+}
+
+func (c *LRURolesCache) Get(userID uuid.UUID) (UserAccess, bool) {
+	access, ok := c.cache.Get(userID)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return access, ok
+}
+
+func (c *LRURolesCache) Add(userID uuid.UUID, access UserAccess) {
+	c.cache.Add(userID, access)
+}
+
+func (c *LRURolesCache) Remove(userID uuid.UUID) bool {
+	return c.cache.Remove(userID)
+}
+
+func (c *LRURolesCache) Purge() {
+	c.cache.Purge()
+}
+
+func (c *LRURolesCache) Stats() RolesCacheStats {
+	return RolesCacheStats{
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+		Size:       c.cache.Len(),
+		TTLSeconds: int64(c.ttl.Seconds()),
+	}
+}
+
+/*
+	rolesGenerations counts the invalidations of the cached roles, per user
+	and of the whole cache. A fetch remembers the generation it started at
+	and caches what it read only if no invalidation happened meanwhile,
+	otherwise the roles it read before the change would live until the ttl
+*/
+
+type rolesGenerations struct {
+	mu    sync.Mutex
+	all   uint64
+	users map[uuid.UUID]uint64
+}
+
+// current must be called with mu held, both counters only grow
+// so their sum changes with any invalidation that concerns the user
+func (g *rolesGenerations) current(userID uuid.UUID) uint64 {
+	return g.all + g.users[userID]
+}
+
+func (g *rolesGenerations) bumpUser(userID uuid.UUID) {
+	if g.users == nil {
+		g.users = make(map[uuid.UUID]uint64)
+	}
+	g.users[userID]++
+}
@@ -0,0 +1,74 @@
+package user_service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// notified by the triggers on user_roles, role_permissions and role_inheritance
+	rolesChangedChannel = "user_roles_changed"
+	purgeAllPayload     = "*"
+	listenRetryDelay    = time.Second * 5
+)
+
+// ListenRoleChanges drops cached roles whenever they change in the db,
+// so that every instance sees grants and revocations made by others.
+// It blocks until ctx is done and reconnects whenever the connection breaks
+func (u *UserService) ListenRoleChanges(ctx context.Context, pool *pgxpool.Pool) {
+	for {
+		err := u.listenRoleChanges(ctx, pool)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Errorf("roles listener stopped, retrying in %v, %v", listenRetryDelay, err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (u *UserService) listenRoleChanges(ctx context.Context, pool *pgxpool.Pool) error {
+	poolConn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the connection listens for as long as we run, keep it out of the pool
+	conn := poolConn.Hijack()
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+rolesChangedChannel)
+	if err != nil {
+		return err
+	}
+
+	// changes made while we were not listening were missed
+	u.InvalidateAllUserRoles()
+	log.Infof("listening on %s for role changes", rolesChangedChannel)
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		if notification.Payload == purgeAllPayload {
+			u.InvalidateAllUserRoles()
+			continue
+		}
+		userID, err := uuid.Parse(notification.Payload)
+		if err != nil {
+			log.Warnf("invalid payload %q on %s", notification.Payload, rolesChangedChannel)
+			continue
+		}
+		u.InvalidateUserRoles(userID)
+	}
+}
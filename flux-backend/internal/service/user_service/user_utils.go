@@ -48,35 +48,70 @@ func (u *UserService) FetchUserByRollNo(
 	return
 }
 
-// extract user roles
+// extract user roles, inherited roles are included
 func (u *UserService) FetchUserRoles(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	access, err := u.fetchUserAccess(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return access.Roles, nil
+}
+
+// extract the permissions granted by the roles of the user
+func (u *UserService) FetchUserPermissions(ctx context.Context, userId uuid.UUID) ([]string, error) {
+	access, err := u.fetchUserAccess(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return access.Permissions, nil
+}
+
+func (u *UserService) fetchUserAccess(ctx context.Context, userId uuid.UUID) (UserAccess, error) {
 	// try to get roles from cache
-	roles, ok := u.rolesCache.Get(userId)
+	access, ok := u.RolesCache.Get(userId)
 	if ok {
 		log.Debugf("rolesCache hit for user %v", userId)
-		return roles, nil
+		return access, nil
 	}
 
+	// an invalidation during the db read makes what is read stale
+	u.generations.mu.Lock()
+	generation := u.generations.current(userId)
+	u.generations.mu.Unlock()
+
 	// get from db
 	log.Debugf("roleCache miss for user %s", userId)
-	userRoles, err := u.DB.GetUserRolesByUserName(ctx, userId)
-	roles = make([]string, 1)
-	roles[0] = "User"
-
+	userRoles, err := u.DB.GetUserEffectiveRoles(ctx, userId)
 	if err != nil {
 		log.Errorf("error fetching roles for user %s, %v", userId, err)
-		return nil, flux_errors.ErrInternal
+		return UserAccess{}, flux_errors.ErrInternal
 	}
-	// convert to string
-	for _, userRole := range userRoles {
-		roles = append(roles, userRole.RoleName)
+	permissions, err := u.DB.GetUserPermissions(ctx, userId)
+	if err != nil {
+		log.Errorf("error fetching permissions for user %s, %v", userId, err)
+		return UserAccess{}, flux_errors.ErrInternal
 	}
 
-	evicted := u.rolesCache.Add(userId, roles)
-	log.Debugf("added roles of %v to cache, evicted: %v", userId, evicted)
-	return roles, nil
+	access.Roles = append([]string{"User"}, userRoles...)
+	access.Permissions = permissions
+	if access.Permissions == nil {
+		access.Permissions = make([]string, 0)
+	}
+
+	// cache only if the roles did not change meanwhile
+	u.generations.mu.Lock()
+	defer u.generations.mu.Unlock()
+	if u.generations.current(userId) != generation {
+		log.Debugf("roles of %v changed while fetching, not caching them", userId)
+		return access, nil
+	}
+	u.RolesCache.Add(userId, access)
+	log.Debugf("added roles of %v to cache", userId)
+	return access, nil
 }
 
+// AuthorizeUserRole checks if the user holds or inherits the role,
+// prefer AuthorizePermission unless the role itself is the data (like lock access)
 func (u *UserService) AuthorizeUserRole(
 	ctx context.Context,
 	role UserRole,
@@ -107,31 +142,54 @@ func (u *UserService) AuthorizeUserRole(
 	return flux_errors.ErrUnAuthorized
 }
 
-func (u *UserService) AuthorizeCreatorAccess(
+func (u *UserService) AuthorizePermission(
 	ctx context.Context,
-	creatorId uuid.UUID,
+	permission Permission,
 	warnMessage string,
 ) error {
+	// get claims
 	claims, err := service.GetClaimsFromContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	// check if they are hc
-	err = u.AuthorizeUserRole(
-		ctx,
-		RoleHC,
-		"",
-	)
-	if err == nil {
+	// get permissions
+	permissions, err := u.FetchUserPermissions(ctx, claims.UserId)
+	if err != nil {
+		return err
+	}
+
+	if slices.Contains(permissions, string(permission)) {
 		return nil
 	}
 
-	if claims.UserId != creatorId {
-		return flux_errors.ErrUnAuthorized
+	// warn
+	if warnMessage != "" {
+		log.Warn(warnMessage)
+	}
+
+	return flux_errors.ErrUnAuthorized
+}
+
+// AuthorizeCreatorAccess allows the creator of a resource and
+// the users with the permission to act on anyone's resource
+func (u *UserService) AuthorizeCreatorAccess(
+	ctx context.Context,
+	creatorId uuid.UUID,
+	anyPermission Permission,
+	warnMessage string,
+) error {
+	claims, err := service.GetClaimsFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if claims.UserId == creatorId {
+		return nil
 	}
 
-	return nil
+	// check if they can access anyone's resource
+	return u.AuthorizePermission(ctx, anyPermission, warnMessage)
 }
 
 // only 3 functions
@@ -77,6 +77,29 @@ func JWTMiddleware(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		// the token is valid until it expires, so check if its session was revoked meanwhile
+		if sessionChecker == nil {
+			log.Error("session checker is not initialized")
+			http.Error(
+				w, "internal error. please try again later",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		active, err := sessionChecker(r.Context(), claims.SessionID)
+		if err != nil {
+			http.Error(
+				w, "internal error. please try again later",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+		if !active {
+			log.Infof("user %s used a token of revoked session %v", claims.UserName, claims.SessionID)
+			http.Error(w, "Unauthorized: session has been revoked", http.StatusUnauthorized)
+			return
+		}
+
 		// log the endpoint user tyring to access
 		log.WithFields(log.Fields{
 			"user_name": claims.UserName,
@@ -6,6 +6,11 @@ package middleware
 */
 
 const (
-	KeyJwtSessionCookieName            = "jwt_session"
-	
+	KeyJwtSessionCookieName = "jwt_session"
+	KeyRefreshCookieName    = "refresh_session"
+	// refresh cookie is sent only to the auth endpoints
+	RefreshCookiePath = "/v1/auth"
+	// holds the oidc state between the redirect to the provider and the callback
+	KeyOIDCCookieName = "oidc_login"
+	OIDCCookiePath    = "/v1/auth/oidc"
 )
@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/tcp_snm/flux/internal/flux_errors"
+	"github.com/tcp_snm/flux/internal/rate_limit"
+)
+
+const (
+	// comma separated addresses or cidrs of the proxies in front of the server,
+	// X-Forwarded-For is only read when the request comes from one of them
+	KeyTrustedProxies = "TRUSTED_PROXIES"
+)
+
+var (
+	limiter            *rate_limit.Limiter
+	trustedProxies     []netip.Prefix
+	loadTrustedProxies sync.Once
+)
+
+// InitializeRateLimiter registers the limiter used by RateLimitMiddleware
+func InitializeRateLimiter(l *rate_limit.Limiter) {
+	limiter = l
+}
+
+// RateLimitMiddleware throttles the requests of every ip,
+// it guards the endpoints that can be used without logging in
+func RateLimitMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if limiter == nil {
+			log.Error("rate limiter is not initialized")
+			http.Error(
+				w, "internal error. please try again later",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+
+		ip := clientIP(r)
+		wait, err := limiter.AllowIP(r.Context(), ip)
+		if err != nil {
+			if errors.Is(err, flux_errors.ErrTooManyRequests) {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, err.Error(), http.StatusTooManyRequests)
+				return
+			}
+			http.Error(
+				w, "internal error. please try again later",
+				http.StatusInternalServerError,
+			)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+}
+
+/*
+	clientIP is the address of the last hop, unless that hop is a trusted proxy
+	Proxies append to X-Forwarded-For, so everything left of the entries they
+	added is chosen by the client. The rightmost entry that is not a trusted
+	proxy is the client as seen by the outermost trusted proxy
+*/
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host) {
+		return host
+	}
+
+	forwarded := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(forwarded[i])
+		if ip == "" {
+			continue
+		}
+		if !isTrustedProxy(ip) {
+			return ip
+		}
+		host = ip
+	}
+	// every hop is a proxy
+	return host
+}
+
+func isTrustedProxy(ip string) bool {
+	loadTrustedProxies.Do(func() {
+		for _, proxy := range strings.Split(os.Getenv(KeyTrustedProxies), ",") {
+			proxy = strings.TrimSpace(proxy)
+			if proxy == "" {
+				continue
+			}
+			prefix, err := netip.ParsePrefix(proxy)
+			if err != nil {
+				addr, addrErr := netip.ParseAddr(proxy)
+				if addrErr != nil {
+					log.Errorf("ignoring invalid trusted proxy %s, %v", proxy, err)
+					continue
+				}
+				prefix = netip.PrefixFrom(addr, addr.BitLen())
+			}
+			trustedProxies = append(trustedProxies, prefix.Masked())
+		}
+	})
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
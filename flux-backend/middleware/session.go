@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SessionChecker reports if the session is still active (neither revoked nor expired)
+type SessionChecker func(ctx context.Context, sessionID uuid.UUID) (bool, error)
+
+var sessionChecker SessionChecker
+
+// InitializeMiddleware registers the checker used by JWTMiddleware to reject revoked sessions
+func InitializeMiddleware(checker SessionChecker) {
+	sessionChecker = checker
+}
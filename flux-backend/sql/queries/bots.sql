@@ -0,0 +1,49 @@
+-- name: ClaimPendingRelaySubmission :one
+-- pending submissions that no worker is relaying, including the ones
+-- claimed by a worker that stopped before saving the verdict
+SELECT
+    s.id,
+    s.language,
+    s.solution,
+    p.platform,
+    p.submission_link
+FROM
+    submissions AS s
+JOIN
+    problems AS p ON s.problem_id = p.id
+WHERE
+    s.status = 'pending'
+AND
+    (
+        s.bot_account_id IS NULL OR
+        -- the worker relaying it died, its bot lease has run out
+        EXISTS (
+            SELECT 1 FROM bots AS b
+            WHERE b.id = s.bot_account_id
+            AND (b.leased_until IS NULL OR b.leased_until < NOW())
+        )
+    )
+AND
+    p.platform IS NOT NULL
+ORDER BY
+    s.created_at
+LIMIT 1
+FOR UPDATE OF s SKIP LOCKED;
+
+-- name: AcquireFreeBot :one
+UPDATE bots SET
+    leased_until = sqlc.arg('leased_until')
+WHERE id = (
+    SELECT id FROM bots
+    WHERE
+        platform = sqlc.arg('platform')
+    AND
+        (leased_until IS NULL OR leased_until < NOW())
+    ORDER BY updated_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: ReleaseBot :exec
+UPDATE bots SET leased_until = NULL WHERE id = $1;
@@ -5,14 +5,24 @@ INSERT INTO contests (
     start_time,
     end_time,
     is_published,
-    lock_id
+    lock_id,
+    self_registration,
+    registration_start,
+    registration_end,
+    max_participants,
+    team_contest
 ) VALUES (
     sqlc.arg('title'),
     sqlc.arg('created_by'),
     sqlc.arg('start_time'),
     sqlc.arg('end_time'),
     sqlc.arg('is_published'),
-    sqlc.arg('lock_id')
+    sqlc.arg('lock_id'),
+    sqlc.arg('self_registration'),
+    sqlc.narg('registration_start'),
+    sqlc.narg('registration_end'),
+    sqlc.narg('max_participants'),
+    sqlc.arg('team_contest')
 )
 RETURNING *;
 
@@ -34,7 +44,7 @@ RETURNING *;
 -- name: UnRegisterContestUsers :exec
 DELETE FROM contest_registered_users WHERE contest_id=$1;
 
--- name: RegisterUserToContest :one
+-- name: RegisterUserToContest :exec
 INSERT INTO contest_registered_users (
     user_id,
     contest_id
@@ -42,7 +52,16 @@ INSERT INTO contest_registered_users (
     $1,
     $2
 )
-RETURNING *;
+ON CONFLICT (user_id, contest_id) DO NOTHING;
+
+-- name: UnregisterUserFromContest :execrows
+DELETE FROM contest_registered_users WHERE contest_id=$1 AND user_id=$2;
+
+-- name: CountContestUsers :one
+SELECT COUNT(*) FROM contest_registered_users WHERE contest_id=$1;
+
+-- name: LockContestForUpdate :one
+SELECT max_participants FROM contests WHERE id=$1 FOR UPDATE;
 
 -- name: DeleteProblemsByContestId :exec
 DELETE FROM contest_problems WHERE contest_id = $1;
@@ -53,7 +72,13 @@ DELETE FROM contest_registered_users WHERE contest_id = $1;
 -- name: IsUserRegisteredInContest :one
 SELECT EXISTS(
     SELECT contest_id, user_id FROM
-     contest_registered_users WHERE contest_id=$1 AND user_id=$1
+     contest_registered_users WHERE contest_id=$1 AND user_id=$2
+);
+
+-- name: IsProblemInContest :one
+SELECT EXISTS(
+    SELECT contest_id, problem_id FROM
+     contest_problems WHERE contest_id=$1 AND problem_id=$2
 );
 
 -- name: GetContestByID :one
@@ -116,9 +141,66 @@ SELECT contest_id FROM contest_registered_users WHERE user_id=$1;
 UPDATE contests SET
     title=$1,
     start_time=$2,
-    end_time=$3
+    end_time=$3,
+    self_registration=$5,
+    registration_start=$6,
+    registration_end=$7,
+    max_participants=$8
 WHERE id=$4
 RETURNING *;
 
 -- name: DeleteContestByID :exec
-DELETE FROM contests WHERE id=$1;
\ No newline at end of file
+DELETE FROM contests WHERE id=$1;
+-- name: RegisterTeamToContest :exec
+INSERT INTO contest_registered_teams (
+    team_id,
+    contest_id
+) VALUES (
+    $1,
+    $2
+)
+ON CONFLICT (team_id, contest_id) DO NOTHING;
+
+-- name: UnregisterTeamFromContest :execrows
+DELETE FROM contest_registered_teams WHERE contest_id=$1 AND team_id=$2;
+
+-- name: UnRegisterContestTeams :exec
+DELETE FROM contest_registered_teams WHERE contest_id=$1;
+
+-- name: CountContestTeams :one
+SELECT COUNT(*) FROM contest_registered_teams WHERE contest_id=$1;
+
+-- name: GetContestTeams :many
+SELECT
+    t.id,
+    t.name
+FROM
+    contest_registered_teams AS crt
+JOIN
+    teams AS t ON crt.team_id = t.id
+WHERE
+    crt.contest_id = $1
+ORDER BY
+    t.name;
+
+-- name: GetUserTeamInContest :one
+SELECT
+    crt.team_id
+FROM
+    contest_registered_teams AS crt
+JOIN
+    team_members AS tm ON crt.team_id = tm.team_id
+WHERE
+    crt.contest_id = $1 AND tm.user_id = $2;
+
+-- name: HasTeamMemberInOtherContestTeam :one
+-- a user can participate through only one team in a contest
+SELECT EXISTS(
+    SELECT tm.user_id FROM
+     team_members AS tm
+    JOIN
+     contest_registered_teams AS crt ON tm.team_id = crt.team_id
+    WHERE crt.contest_id = $1
+    AND crt.team_id <> $2
+    AND tm.user_id IN (SELECT user_id FROM team_members WHERE team_id = $2)
+);
@@ -4,13 +4,15 @@ INSERT INTO locks (
     created_by,
     description,
     lock_type,
-    timeout
+    timeout,
+    access
 ) VALUES (
     $1, -- name
     $2, -- created_by
     $3, -- description
     $4, -- lock_type: either timer or manual
-    $5  -- timeout: null only if manual
+    $5, -- timeout: null only if manual
+    $6  -- access: role that can see the locked entities before expiry
 )
 RETURNING *;
 
@@ -18,11 +20,12 @@ RETURNING *;
 SELECT * FROM locks WHERE id=sqlc.arg('group_d');
 
 -- name: UpdateLockDetails :one
+-- the timeout only moves with a reschedule, a null access keeps the current one
 UPDATE locks
 SET
     name = $2,
-    timeout = $3,
-    description = $4
+    description = $3,
+    access = COALESCE(sqlc.narg('access'), access)
 WHERE
     id = $1
 RETURNING *;
@@ -40,4 +43,129 @@ OFFSET sqlc.arg('offset');
 
 -- name: DeleteLockById :exec
 DELETE FROM locks 
-WHERE id=$1;
\ No newline at end of file
+WHERE id=$1;
+
+-- name: GetPendingTimerLocks :many
+-- timer locks whose release was not published yet
+SELECT l.* FROM locks l
+WHERE
+    l.lock_type = 'timer'
+    AND NOT EXISTS (
+        SELECT 1 FROM lock_release_events e
+        WHERE e.lock_id = l.id AND e.published_at IS NOT NULL
+    );
+
+-- name: ClaimLockRelease :execrows
+-- only one claim of an expired timer lock succeeds,
+-- an unpublished claim older than stale_before can be taken again
+INSERT INTO lock_release_events (lock_id, timeout)
+SELECT id, timeout FROM locks
+WHERE id = $1 AND lock_type = 'timer' AND timeout <= NOW()
+ON CONFLICT (lock_id) DO UPDATE SET claimed_at = NOW()
+WHERE
+    lock_release_events.published_at IS NULL
+    AND lock_release_events.claimed_at < sqlc.arg('stale_before');
+
+-- name: MarkLockReleasePublished :exec
+UPDATE lock_release_events SET published_at = NOW() WHERE lock_id = $1;
+
+-- name: ReleaseManualLock :one
+UPDATE locks SET released_at = NOW()
+WHERE id = $1 AND lock_type = 'manual' AND released_at IS NULL
+RETURNING *;
+
+-- name: EngageManualLock :one
+UPDATE locks SET released_at = NULL
+WHERE id = $1 AND lock_type = 'manual' AND released_at IS NOT NULL
+RETURNING *;
+
+-- name: CreateLockAuditLog :one
+INSERT INTO lock_audit_logs (lock_id, lock_name, action, performed_by, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetLockAuditLogs :many
+SELECT
+    a.id,
+    a.action,
+    a.performed_at,
+    a.note,
+    u.user_name as performed_by
+FROM
+    lock_audit_logs a
+JOIN
+    users u ON a.performed_by = u.id
+WHERE
+    a.lock_id = $1
+ORDER BY
+    a.performed_at DESC;
+
+-- name: GetLockAllowedUsers :many
+SELECT u.id, u.user_name, u.roll_no
+FROM lock_allowed_users a
+JOIN users u ON a.user_id = u.id
+WHERE a.lock_id = $1
+ORDER BY u.user_name;
+
+-- name: AddLockAllowedUsers :execrows
+INSERT INTO lock_allowed_users (lock_id, user_id)
+SELECT $1, id FROM users WHERE user_name = ANY(sqlc.arg('user_names')::text[])
+ON CONFLICT DO NOTHING;
+
+-- name: DeleteLockAllowedUsers :exec
+DELETE FROM lock_allowed_users WHERE lock_id = $1;
+
+-- name: GetUserAllowedLockIDs :many
+SELECT lock_id FROM lock_allowed_users WHERE user_id = $1;
+
+-- name: IsLockUserAllowed :one
+SELECT EXISTS(
+    SELECT 1 FROM lock_allowed_users WHERE lock_id = $1 AND user_id = $2
+);
+
+-- name: GetLockProblems :many
+SELECT id, title FROM problems WHERE lock_id = $1 ORDER BY id;
+
+-- name: GetLockContests :many
+SELECT id, title, end_time FROM contests WHERE lock_id = $1 ORDER BY end_time;
+
+-- name: GetLockTournamentRounds :many
+SELECT id, tournament_id, round_number, title FROM tournament_rounds
+WHERE lock_id = $1
+ORDER BY tournament_id, round_number;
+
+-- name: ReassignLockProblems :execrows
+UPDATE problems SET lock_id = sqlc.arg('target_lock_id') WHERE lock_id = sqlc.arg('lock_id');
+
+-- name: ReassignLockContests :execrows
+UPDATE contests SET lock_id = sqlc.arg('target_lock_id') WHERE lock_id = sqlc.arg('lock_id');
+
+-- name: ReassignLockTournamentRounds :execrows
+UPDATE tournament_rounds SET lock_id = sqlc.arg('target_lock_id') WHERE lock_id = sqlc.arg('lock_id');
+
+-- name: RescheduleTimerLock :one
+-- only a timer lock that has not expired can be rescheduled
+UPDATE locks SET timeout = $2
+WHERE id = $1 AND lock_type = 'timer' AND timeout > NOW()
+RETURNING *;
+
+-- name: GetLockContestsForUpdate :many
+SELECT id, title, end_time, registration_start, registration_end
+FROM contests WHERE lock_id = $1 FOR UPDATE;
+
+-- name: DeleteLockReleaseEvent :exec
+DELETE FROM lock_release_events WHERE lock_id = $1;
+
+-- name: GetLockContestRecipients :many
+-- registered users and members of registered teams of the contests of a lock
+SELECT DISTINCT c.id as contest_id, c.title, u.email
+FROM contests c
+JOIN (
+    SELECT contest_id, user_id FROM contest_registered_users
+    UNION
+    SELECT rt.contest_id, tm.user_id
+    FROM contest_registered_teams rt
+    JOIN team_members tm ON rt.team_id = tm.team_id
+) r ON r.contest_id = c.id
+JOIN users u ON r.user_id = u.id
+WHERE c.lock_id = $1;
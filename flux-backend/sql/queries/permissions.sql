@@ -0,0 +1,45 @@
+-- name: GetRoleInheritance :many
+SELECT role_name, parent_role FROM role_inheritance ORDER BY role_name, parent_role;
+
+-- name: GetRolePermissions :many
+-- every role with its own permissions, roles without permissions have a single row with null
+SELECT
+    r.role_name,
+    rp.permission
+FROM
+    roles r
+LEFT JOIN
+    role_permissions rp ON r.role_name = rp.role_name
+ORDER BY
+    r.role_name, rp.permission;
+
+-- name: GetUserEffectiveRoles :many
+-- roles of the user along with every role they inherit
+WITH RECURSIVE effective_roles AS (
+    SELECT ur.role_name FROM user_roles ur WHERE ur.user_id = $1
+    UNION
+    SELECT ri.parent_role FROM role_inheritance ri
+    JOIN effective_roles er ON ri.role_name = er.role_name
+)
+SELECT role_name FROM effective_roles ORDER BY role_name;
+
+-- name: GetUserPermissions :many
+-- permissions of every role the user holds or inherits
+WITH RECURSIVE effective_roles AS (
+    SELECT ur.role_name FROM user_roles ur WHERE ur.user_id = $1
+    UNION
+    SELECT ri.parent_role FROM role_inheritance ri
+    JOIN effective_roles er ON ri.role_name = er.role_name
+)
+SELECT DISTINCT rp.permission
+FROM role_permissions rp
+JOIN effective_roles er ON rp.role_name = er.role_name
+ORDER BY rp.permission;
+
+-- name: GrantRolePermission :execrows
+INSERT INTO role_permissions (role_name, permission)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RevokeRolePermission :execrows
+DELETE FROM role_permissions WHERE role_name = $1 AND permission = $2;
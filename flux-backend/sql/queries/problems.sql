@@ -13,7 +13,11 @@ INSERT INTO problems (
     difficulty,
     submission_link,
     platform,
-    lock_id
+    lock_id,
+    checker_mode,
+    checker_epsilon,
+    checker_language,
+    checker_source
 ) VALUES (
     $1, -- title
     $2, -- statement
@@ -28,7 +32,11 @@ INSERT INTO problems (
     $10, -- difficulty (can be NULL)
     $11, -- submission_link (can be NULL)
     $12, -- platform (can be NULL)
-    $13 -- lock_id
+    $13, -- lock_id
+    $14, -- checker_mode
+    $15, -- checker_epsilon (can be NULL)
+    $16, -- checker_language (can be NULL)
+    $17 -- checker_source (can be NULL)
 )
 RETURNING *;
 
@@ -42,7 +50,8 @@ SELECT
 
     -- Select only the 'access' column from the 'locks' table
     locks.access as lock_access,
-    locks.timeout as lock_timeout
+    locks.timeout as lock_timeout,
+    locks.released_at as lock_released_at
 FROM
     problems
 LEFT JOIN
@@ -66,9 +75,13 @@ SET
     submission_link = $10,
     platform = $11,
     last_updated_by = $12,
-    lock_id = $13
+    lock_id = $13,
+    checker_mode = $14,
+    checker_epsilon = $15,
+    checker_language = $16,
+    checker_source = $17
 WHERE
-    id = $14
+    id = $18
 RETURNING *;
 
 -- name: GetProblemsByFilters :many
@@ -81,6 +94,7 @@ SELECT
     p.created_at,
     l.id as lock_id,
     l.timeout as lock_timeout,
+    l.released_at as lock_released_at,
     l.access as lock_access
 FROM
     problems AS p
@@ -118,7 +132,7 @@ OFFSET
     sqlc.arg('offset');
 
 -- name: GetProblemAuth :one
-SELECT locks.id, locks.access, locks.timeout
+SELECT locks.id, locks.access, locks.timeout, locks.released_at
 FROM problems
 LEFT JOIN locks ON problems.lock_id = locks.id
 WHERE problems.id = $1;
@@ -0,0 +1,59 @@
+-- name: TakeRateLimitToken :one
+-- refills the bucket and takes a token, no row is returned if it is empty
+INSERT INTO rate_limit_buckets (key, tokens, updated_at)
+VALUES (sqlc.arg('key'), sqlc.arg('burst')::DOUBLE PRECISION - 1, NOW())
+ON CONFLICT (key) DO UPDATE SET
+    tokens = LEAST(
+        sqlc.arg('burst')::DOUBLE PRECISION,
+        rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * sqlc.arg('rate')::DOUBLE PRECISION
+    ) - 1,
+    updated_at = NOW()
+WHERE LEAST(
+    sqlc.arg('burst')::DOUBLE PRECISION,
+    rate_limit_buckets.tokens + EXTRACT(EPOCH FROM NOW() - rate_limit_buckets.updated_at) * sqlc.arg('rate')::DOUBLE PRECISION
+) >= 1
+RETURNING tokens;
+
+-- name: GetRateLimitTokens :one
+-- tokens in the bucket as of now, after the refill
+SELECT LEAST(
+    sqlc.arg('burst')::DOUBLE PRECISION,
+    tokens + EXTRACT(EPOCH FROM NOW() - updated_at) * sqlc.arg('rate')::DOUBLE PRECISION
+)::DOUBLE PRECISION
+FROM rate_limit_buckets WHERE key = sqlc.arg('key');
+
+-- name: AddRateLimitFailure :one
+-- the failures are counted afresh once the window has passed
+INSERT INTO rate_limit_failures (key, failures, window_start)
+VALUES (sqlc.arg('key'), 1, NOW())
+ON CONFLICT (key) DO UPDATE SET
+    failures = CASE
+        WHEN rate_limit_failures.window_start < NOW() - make_interval(secs => sqlc.arg('window_seconds')::DOUBLE PRECISION)
+        THEN 1 ELSE rate_limit_failures.failures + 1
+    END,
+    window_start = CASE
+        WHEN rate_limit_failures.window_start < NOW() - make_interval(secs => sqlc.arg('window_seconds')::DOUBLE PRECISION)
+        THEN NOW() ELSE rate_limit_failures.window_start
+    END
+RETURNING failures;
+
+-- name: ResetRateLimitFailures :exec
+DELETE FROM rate_limit_failures WHERE key = $1;
+
+-- name: LockRateLimitKey :exec
+INSERT INTO rate_limit_failures (key, locked_until)
+VALUES ($1, $2)
+ON CONFLICT (key) DO UPDATE SET locked_until = $2;
+
+-- name: GetRateLimitLockedUntil :one
+SELECT locked_until FROM rate_limit_failures WHERE key = $1;
+
+-- name: PruneRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets
+WHERE updated_at < NOW() - make_interval(secs => sqlc.arg('older_than_seconds')::DOUBLE PRECISION);
+
+-- name: PruneRateLimitFailures :exec
+-- failures of locked keys are kept until the lock is over
+DELETE FROM rate_limit_failures
+WHERE window_start < NOW() - make_interval(secs => sqlc.arg('older_than_seconds')::DOUBLE PRECISION)
+AND (locked_until IS NULL OR locked_until < NOW());
@@ -1,2 +1,28 @@
 -- name: GetUserRolesByUserName :many
-SELECT * FROM user_roles WHERE user_id = $1;
\ No newline at end of file
+SELECT * FROM user_roles WHERE user_id = $1;
+
+-- name: GetRoleHolders :many
+-- every role with the users holding it, roles without holders have a single row of nulls
+SELECT
+    r.role_name,
+    u.user_name,
+    u.roll_no
+FROM
+    roles r
+LEFT JOIN
+    user_roles ur ON r.role_name = ur.role_name
+LEFT JOIN
+    users u ON ur.user_id = u.id
+ORDER BY
+    r.role_name, u.user_name;
+
+-- name: GrantUserRole :execrows
+INSERT INTO user_roles (user_id, role_name)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: RevokeUserRole :execrows
+DELETE FROM user_roles WHERE user_id = $1 AND role_name = $2;
+
+-- name: RoleExists :one
+SELECT EXISTS(SELECT 1 FROM roles WHERE role_name = $1);
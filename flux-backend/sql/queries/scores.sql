@@ -0,0 +1,165 @@
+-- name: MarkProblemSolved :execrows
+INSERT INTO solved (
+    user_id,
+    contest_id,
+    problem_id
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT DO NOTHING;
+
+-- name: AddUserScore :exec
+INSERT INTO user_scores (
+    user_id,
+    contest_id,
+    problem_id,
+    score,
+    submission_id
+)
+SELECT
+    sqlc.arg('user_id')::uuid,
+    cp.contest_id,
+    cp.problem_id,
+    cp.score,
+    sqlc.arg('submission_id')::uuid
+FROM
+    contest_problems AS cp
+WHERE
+    cp.contest_id = sqlc.arg('contest_id')::uuid AND cp.problem_id = sqlc.arg('problem_id')::int
+ON CONFLICT (user_id, contest_id, problem_id) DO NOTHING;
+
+-- name: GetContestLeaderboard :many
+WITH participants AS (
+    -- registered users of private contests and anyone who scored in a published one
+    SELECT user_id FROM contest_registered_users WHERE contest_id = sqlc.arg('contest_id')::uuid
+    UNION
+    SELECT user_id FROM user_scores WHERE contest_id = sqlc.arg('contest_id')::uuid
+),
+problem_penalties AS (
+    -- minutes from the start of the contest to the accepted submission,
+    -- plus 20 minutes for every rejected attempt before it
+    SELECT
+        us.user_id,
+        us.score,
+        (EXTRACT(EPOCH FROM (s.created_at - sqlc.arg('start_time')::timestamptz)) / 60)::int
+        + 20 * (
+            SELECT COUNT(*) FROM submissions AS ws
+            WHERE ws.contest_id = us.contest_id
+            AND ws.problem_id = us.problem_id
+            AND ws.submitted_by = us.user_id
+            AND ws.created_at < s.created_at
+            AND ws.status IN ('wrong_answer', 'time_limit_exceeded', 'memory_limit_exceeded', 'runtime_error')
+        )::int AS penalty
+    FROM
+        user_scores AS us
+    JOIN
+        submissions AS s ON us.submission_id = s.id
+    WHERE
+        us.contest_id = sqlc.arg('contest_id')::uuid
+),
+totals AS (
+    SELECT
+        p.user_id,
+        COALESCE(SUM(pp.score), 0)::int AS total_score,
+        COALESCE(SUM(pp.penalty), 0)::int AS penalty,
+        COUNT(pp.user_id)::int AS solved_count
+    FROM
+        participants AS p
+    LEFT JOIN
+        problem_penalties AS pp ON p.user_id = pp.user_id
+    GROUP BY
+        p.user_id
+)
+SELECT
+    (RANK() OVER (ORDER BY t.total_score DESC, t.penalty ASC))::int AS rank,
+    u.user_name,
+    u.roll_no,
+    t.total_score,
+    t.penalty,
+    t.solved_count
+FROM
+    totals AS t
+JOIN
+    users AS u ON t.user_id = u.id
+ORDER BY
+    rank, u.user_name
+LIMIT
+    sqlc.arg('limit')
+OFFSET
+    sqlc.arg('offset');
+
+-- name: AddTeamScore :execrows
+INSERT INTO team_scores (
+    team_id,
+    contest_id,
+    problem_id,
+    score,
+    submission_id
+)
+SELECT
+    sqlc.arg('team_id')::uuid,
+    cp.contest_id,
+    cp.problem_id,
+    cp.score,
+    sqlc.arg('submission_id')::uuid
+FROM
+    contest_problems AS cp
+WHERE
+    cp.contest_id = sqlc.arg('contest_id')::uuid AND cp.problem_id = sqlc.arg('problem_id')::int
+ON CONFLICT (team_id, contest_id, problem_id) DO NOTHING;
+
+-- name: GetContestTeamLeaderboard :many
+WITH problem_penalties AS (
+    -- same as the individual leaderboard, but attempts of all the members count
+    SELECT
+        ts.team_id,
+        ts.score,
+        (EXTRACT(EPOCH FROM (s.created_at - sqlc.arg('start_time')::timestamptz)) / 60)::int
+        + 20 * (
+            SELECT COUNT(*) FROM submissions AS ws
+            WHERE ws.contest_id = ts.contest_id
+            AND ws.problem_id = ts.problem_id
+            AND ws.team_id = ts.team_id
+            AND ws.created_at < s.created_at
+            AND ws.status IN ('wrong_answer', 'time_limit_exceeded', 'memory_limit_exceeded', 'runtime_error')
+        )::int AS penalty
+    FROM
+        team_scores AS ts
+    JOIN
+        submissions AS s ON ts.submission_id = s.id
+    WHERE
+        ts.contest_id = sqlc.arg('contest_id')::uuid
+),
+totals AS (
+    SELECT
+        crt.team_id,
+        COALESCE(SUM(pp.score), 0)::int AS total_score,
+        COALESCE(SUM(pp.penalty), 0)::int AS penalty,
+        COUNT(pp.team_id)::int AS solved_count
+    FROM
+        contest_registered_teams AS crt
+    LEFT JOIN
+        problem_penalties AS pp ON crt.team_id = pp.team_id
+    WHERE
+        crt.contest_id = sqlc.arg('contest_id')::uuid
+    GROUP BY
+        crt.team_id
+)
+SELECT
+    (RANK() OVER (ORDER BY t.total_score DESC, t.penalty ASC))::int AS rank,
+    tm.name AS team_name,
+    t.total_score,
+    t.penalty,
+    t.solved_count
+FROM
+    totals AS t
+JOIN
+    teams AS tm ON t.team_id = tm.id
+ORDER BY
+    rank, tm.name
+LIMIT
+    sqlc.arg('limit')
+OFFSET
+    sqlc.arg('offset');
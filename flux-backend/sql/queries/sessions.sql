@@ -0,0 +1,40 @@
+-- name: CreateSession :one
+INSERT INTO sessions (user_id, hashed_refresh_token, expires_at, two_factor_verified)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetSessionByID :one
+SELECT * FROM sessions WHERE id = $1;
+
+-- name: RotateSessionToken :execrows
+-- compares the old hash so only one of the concurrent refreshes wins,
+-- the old hash is kept to tell the losers apart from a stolen token
+UPDATE sessions SET
+    previous_hashed_refresh_token = hashed_refresh_token,
+    hashed_refresh_token = sqlc.arg('new_hashed_refresh_token'),
+    last_refreshed_at = NOW()
+WHERE
+    id = sqlc.arg('id')
+AND
+    hashed_refresh_token = sqlc.arg('old_hashed_refresh_token')
+AND
+    revoked_at IS NULL;
+
+-- name: RevokeSession :execrows
+UPDATE sessions SET revoked_at = NOW()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeOtherUserSessions :execrows
+-- every session of the user except the current one
+UPDATE sessions SET revoked_at = NOW()
+WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL;
+
+-- name: RevokeUserSessions :execrows
+UPDATE sessions SET revoked_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
+
+-- name: IsSessionActive :one
+SELECT EXISTS(
+    SELECT 1 FROM sessions
+    WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+);
@@ -0,0 +1,98 @@
+-- name: CreateSubmission :one
+INSERT INTO submissions (
+    submitted_by,
+    contest_id,
+    problem_id,
+    language,
+    solution,
+    status,
+    team_id
+) VALUES (
+    $1, -- submitted_by
+    $2, -- contest_id: null for practice submissions
+    $3, -- problem_id
+    $4, -- language
+    $5, -- solution
+    $6, -- status
+    $7  -- team_id: null for individual submissions
+)
+RETURNING *;
+
+-- name: GetSubmissionByID :one
+SELECT
+    s.*,
+    u.user_name
+FROM
+    submissions AS s
+JOIN
+    users AS u ON s.submitted_by = u.id
+WHERE
+    s.id = $1;
+
+-- name: GetSubmissionsByFilters :many
+SELECT
+    s.id,
+    s.submitted_by,
+    u.user_name,
+    s.contest_id,
+    s.problem_id,
+    s.language,
+    s.status,
+    s.created_at,
+    s.updated_at,
+    s.team_id
+FROM
+    submissions AS s
+JOIN
+    users AS u ON s.submitted_by = u.id
+WHERE
+    -- Optional filter by the user who submitted
+    (sqlc.narg('submitted_by')::uuid IS NULL OR s.submitted_by = sqlc.narg('submitted_by')::uuid)
+AND
+    -- Optional filter by contest
+    (sqlc.narg('contest_id')::uuid IS NULL OR s.contest_id = sqlc.narg('contest_id')::uuid)
+AND
+    -- Optional filter by problem
+    (sqlc.narg('problem_id')::int IS NULL OR s.problem_id = sqlc.narg('problem_id')::int)
+AND
+    -- Optional filter by language
+    (sqlc.narg('language')::text IS NULL OR s.language = sqlc.narg('language')::text)
+AND
+    -- Optional filter by status
+    (sqlc.narg('status')::text IS NULL OR s.status = sqlc.narg('status')::text)
+ORDER BY
+    s.created_at DESC
+LIMIT
+    sqlc.arg('limit')
+OFFSET
+    sqlc.arg('offset');
+
+-- name: UpdateSubmissionStatus :one
+UPDATE submissions SET
+    status=$2
+WHERE id=$1
+RETURNING *;
+
+-- name: SetSubmissionBot :exec
+UPDATE submissions SET
+    bot_account_id=$2
+WHERE id=$1;
+
+-- name: SetSubmissionWebsiteData :exec
+UPDATE submissions SET
+    website_data=$2
+WHERE id=$1;
+
+-- name: GetPendingLocalSubmissions :many
+SELECT
+    s.*
+FROM
+    submissions AS s
+JOIN
+    problems AS p ON s.problem_id = p.id
+WHERE
+    s.status = 'pending'
+AND
+    p.platform IS NULL
+ORDER BY
+    s.created_at;
@@ -0,0 +1,109 @@
+-- name: CreateTeam :one
+INSERT INTO teams (
+    name,
+    created_by,
+    max_size
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+RETURNING *;
+
+-- name: GetTeamByID :one
+SELECT * FROM teams WHERE id=$1;
+
+-- name: GetTeamByName :one
+SELECT * FROM teams WHERE name=$1;
+
+-- name: GetUserTeams :many
+SELECT
+    t.*
+FROM
+    teams AS t
+JOIN
+    team_members AS tm ON t.id = tm.team_id
+WHERE
+    tm.user_id = $1
+ORDER BY
+    t.name;
+
+-- name: LockTeamForUpdate :one
+SELECT max_size FROM teams WHERE id=$1 FOR UPDATE;
+
+-- name: AddTeamMember :exec
+INSERT INTO team_members (
+    team_id,
+    user_id
+) VALUES (
+    $1,
+    $2
+);
+
+-- name: RemoveTeamMember :execrows
+DELETE FROM team_members WHERE team_id=$1 AND user_id=$2;
+
+-- name: GetTeamMembers :many
+SELECT
+    u.id,
+    u.user_name,
+    u.roll_no,
+    tm.joined_at
+FROM
+    team_members AS tm
+JOIN
+    users AS u ON tm.user_id = u.id
+WHERE
+    tm.team_id = $1
+ORDER BY
+    tm.joined_at;
+
+-- name: CountTeamMembers :one
+SELECT COUNT(*) FROM team_members WHERE team_id=$1;
+
+-- name: IsUserInTeam :one
+SELECT EXISTS(
+    SELECT team_id, user_id FROM
+     team_members WHERE team_id=$1 AND user_id=$2
+);
+
+-- name: CreateTeamInvite :exec
+INSERT INTO team_invites (
+    team_id,
+    user_id,
+    invited_by
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (team_id, user_id) DO NOTHING;
+
+-- name: DeleteTeamInvite :execrows
+DELETE FROM team_invites WHERE team_id=$1 AND user_id=$2;
+
+-- name: GetUserTeamInvites :many
+SELECT
+    t.id AS team_id,
+    t.name AS team_name,
+    u.user_name AS invited_by,
+    ti.created_at
+FROM
+    team_invites AS ti
+JOIN
+    teams AS t ON ti.team_id = t.id
+JOIN
+    users AS u ON ti.invited_by = u.id
+WHERE
+    ti.user_id = $1
+ORDER BY
+    ti.created_at DESC;
+
+-- name: IsTeamInUnfinishedContest :one
+SELECT EXISTS(
+    SELECT crt.team_id FROM
+     contest_registered_teams AS crt
+    JOIN
+     contests AS c ON crt.contest_id = c.id
+    WHERE crt.team_id = $1 AND c.end_time > NOW()
+);
@@ -0,0 +1,52 @@
+-- name: LockProblemForUpdate :one
+SELECT id FROM problems WHERE id = $1 FOR UPDATE;
+
+-- name: AddProblemTestCase :one
+INSERT INTO problem_test_cases (
+    problem_id,
+    position,
+    test_group,
+    points,
+    storage,
+    input_key,
+    output_key,
+    input_size,
+    output_size,
+    input_sha256,
+    output_sha256,
+    created_by
+) VALUES (
+    sqlc.arg('problem_id'),
+    (SELECT COALESCE(MAX(position), 0) + 1 FROM problem_test_cases WHERE problem_id = sqlc.arg('problem_id')),
+    sqlc.narg('test_group'),
+    sqlc.arg('points'),
+    sqlc.arg('storage'),
+    sqlc.arg('input_key'),
+    sqlc.arg('output_key'),
+    sqlc.arg('input_size'),
+    sqlc.arg('output_size'),
+    sqlc.arg('input_sha256'),
+    sqlc.arg('output_sha256'),
+    sqlc.arg('created_by')
+)
+RETURNING *;
+
+-- name: GetProblemTestCases :many
+SELECT * FROM problem_test_cases WHERE problem_id = $1 ORDER BY position;
+
+-- name: GetProblemTestCaseByID :one
+SELECT * FROM problem_test_cases WHERE id = $1;
+
+-- name: SetProblemTestCasePosition :execrows
+UPDATE problem_test_cases SET
+    position = $3
+WHERE id = $1 AND problem_id = $2;
+
+-- name: DeleteProblemTestCase :exec
+DELETE FROM problem_test_cases WHERE id = $1;
+
+-- name: ShiftProblemTestCasePositions :exec
+-- closes the gap left by a deleted test
+UPDATE problem_test_cases SET
+    position = position - 1
+WHERE problem_id = $1 AND position > $2;
@@ -12,4 +12,19 @@ ORDER BY created_at DESC
 LIMIT 1;
 
 -- name: DeleteByEmailAndPurpose :exec
-DELETE FROM tokens WHERE email = $1 AND purpose = $2;
\ No newline at end of file
+DELETE FROM tokens WHERE email = $1 AND purpose = $2;
+
+-- name: DeleteExpiredTokens :execrows
+DELETE FROM tokens WHERE expires_at < NOW();
+
+-- name: DeleteToken :exec
+DELETE FROM tokens WHERE id = $1;
+
+-- name: ConsumeToken :one
+-- deleting the token is what uses it, a concurrent use finds no row
+DELETE FROM tokens WHERE id = $1
+RETURNING *;
+
+-- name: IncrementTokenAttempts :one
+UPDATE tokens SET attempts = attempts + 1 WHERE id = $1
+RETURNING attempts;
@@ -28,8 +28,9 @@ AND
 
 -- name: CreateTournamentRound :one
 INSERT INTO tournament_rounds (
-    tournament_id, round_number, title, lock_id, created_by
-) VALUES ($1, $2, $3, $4, $5)
+    tournament_id, round_number, title, lock_id, created_by, scoring, best_n,
+    advancement, advance_k, advance_threshold
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING *;
 
 -- name: GetTournamentById :one
@@ -70,10 +71,16 @@ SELECT
     tr.title,
     tr.lock_id,
     tr.created_by,
+    tr.scoring,
+    tr.best_n,
+    tr.advancement,
+    tr.advance_k,
+    tr.advance_threshold,
 
     -- lock fields
     l.access,
-    l.timeout
+    l.timeout,
+    l.released_at
 FROM
     tournament_rounds tr
 LEFT JOIN
@@ -83,6 +90,17 @@ ON
 WHERE
     tr.tournament_id = $1 AND tr.round_number = $2;
 
+-- name: GetTournamentRoundEndTime :one
+SELECT
+    -- a round without contests has nothing to wait for
+    COALESCE(MAX(c.end_time), '1970-01-01'::timestamptz)::timestamptz
+FROM
+    contests c
+JOIN
+    tournament_contests tc ON c.id = tc.contest_id
+WHERE
+    tc.round_id = $1;
+
 -- name: GetTournamentContests :many
 SELECT contest_id FROM tournament_contests WHERE round_id = $1;
 
@@ -90,8 +108,8 @@ SELECT contest_id FROM tournament_contests WHERE round_id = $1;
 DELETE FROM tournament_contests WHERE round_id = $1;
 
 -- name: AddTournamentContest :exec
-INSERT INTO tournament_contests (round_id, contest_id)
-VALUES ($1, $2);
+INSERT INTO tournament_contests (round_id, contest_id, weight)
+VALUES ($1, $2, $3);
 
 -- name: GetTournamentsByFilters :many
 SELECT
@@ -120,4 +138,125 @@ ORDER BY
 LIMIT
     sqlc.arg('limit')
 OFFSET
-    sqlc.arg('offset');
\ No newline at end of file
+    sqlc.arg('offset');
+
+-- name: UpdateTournamentRoundScoring :execrows
+UPDATE tournament_rounds SET
+    scoring = $3,
+    best_n = $4
+WHERE
+    tournament_id = $1 AND round_number = $2;
+
+-- name: GetTournamentStandingsVersion :one
+-- standings only change when a contest of the tournament ends or when a late
+-- verdict changes the scores of an ended contest, the latest of those times
+-- identifies a version of them
+SELECT
+    GREATEST(
+        COALESCE(MAX(c.end_time), '1970-01-01'::timestamptz),
+        COALESCE(MAX(us.updated_at), '1970-01-01'::timestamptz),
+        COALESCE(MAX(ts.created_at), '1970-01-01'::timestamptz)
+    )::timestamptz
+FROM
+    contests c
+JOIN
+    tournament_contests tc ON c.id = tc.contest_id
+JOIN
+    tournament_rounds tr ON tc.round_id = tr.id
+LEFT JOIN LATERAL (
+    SELECT MAX(updated_at) AS updated_at FROM user_scores WHERE contest_id = c.id
+) us ON TRUE
+LEFT JOIN LATERAL (
+    SELECT MAX(created_at) AS created_at FROM team_scores WHERE contest_id = c.id
+) ts ON TRUE
+WHERE
+    tr.tournament_id = $1
+AND
+    c.end_time <= NOW();
+
+-- name: GetTournamentUserScores :many
+-- total score of every user in each ended contest of the tournament
+SELECT
+    tr.round_number,
+    tr.scoring,
+    tr.best_n,
+    tc.contest_id,
+    tc.weight,
+    u.id AS user_id,
+    u.user_name,
+    u.roll_no,
+    SUM(us.score)::int AS score
+FROM
+    tournament_rounds tr
+JOIN
+    tournament_contests tc ON tr.id = tc.round_id
+JOIN
+    contests c ON tc.contest_id = c.id
+JOIN
+    user_scores us ON c.id = us.contest_id
+JOIN
+    users u ON us.user_id = u.id
+WHERE
+    tr.tournament_id = sqlc.arg('tournament_id')::uuid
+AND
+    c.end_time <= sqlc.arg('ended_before')::timestamptz
+GROUP BY
+    tr.round_number, tr.scoring, tr.best_n, tc.contest_id, tc.weight, u.id
+ORDER BY
+    tr.round_number;
+
+-- name: GetTournamentTeamScores :many
+-- total score of every team in each ended team contest of the tournament
+SELECT
+    tr.round_number,
+    tr.scoring,
+    tr.best_n,
+    tc.contest_id,
+    tc.weight,
+    tm.id AS team_id,
+    tm.name AS team_name,
+    SUM(ts.score)::int AS score
+FROM
+    tournament_rounds tr
+JOIN
+    tournament_contests tc ON tr.id = tc.round_id
+JOIN
+    contests c ON tc.contest_id = c.id
+JOIN
+    team_scores ts ON c.id = ts.contest_id
+JOIN
+    teams tm ON ts.team_id = tm.id
+WHERE
+    tr.tournament_id = sqlc.arg('tournament_id')::uuid
+AND
+    c.end_time <= sqlc.arg('ended_before')::timestamptz
+GROUP BY
+    tr.round_number, tr.scoring, tr.best_n, tc.contest_id, tc.weight, tm.id
+ORDER BY
+    tr.round_number;
+
+-- name: UpdateTournamentRoundAdvancement :execrows
+UPDATE tournament_rounds SET
+    advancement = $3,
+    advance_k = $4,
+    advance_threshold = $5
+WHERE
+    tournament_id = $1 AND round_number = $2;
+
+-- name: GetTournamentRoundUserScores :many
+-- total score of every user in each contest of the round
+SELECT
+    tc.contest_id,
+    tc.weight,
+    u.user_name,
+    SUM(us.score)::int AS score
+FROM
+    tournament_contests tc
+JOIN
+    user_scores us ON tc.contest_id = us.contest_id
+JOIN
+    users u ON us.user_id = u.id
+WHERE
+    tc.round_id = $1
+GROUP BY
+    tc.contest_id, tc.weight, u.id;
@@ -0,0 +1,58 @@
+-- name: GetUserTotp :one
+SELECT * FROM user_totp WHERE user_id = $1;
+
+-- name: UpsertUserTotp :one
+-- a confirmed secret is never replaced, no row is returned then
+INSERT INTO user_totp (user_id, secret)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET
+    secret = EXCLUDED.secret,
+    last_used_step = 0,
+    created_at = NOW()
+WHERE user_totp.confirmed_at IS NULL
+RETURNING *;
+
+-- name: ConfirmUserTotp :execrows
+UPDATE user_totp SET confirmed_at = NOW()
+WHERE user_id = $1 AND confirmed_at IS NULL;
+
+-- name: UseTotpStep :execrows
+-- a code is accepted only once, concurrent uses of the same code race here
+UPDATE user_totp SET last_used_step = sqlc.arg('step')
+WHERE user_id = sqlc.arg('user_id') AND last_used_step < sqlc.arg('step');
+
+-- name: DeleteUserTotp :execrows
+DELETE FROM user_totp WHERE user_id = $1;
+
+-- name: CreateRecoveryCodes :exec
+INSERT INTO totp_recovery_codes (user_id, hashed_code)
+SELECT sqlc.arg('user_id')::uuid, unnest(sqlc.arg('hashed_codes')::varchar[]);
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM totp_recovery_codes WHERE user_id = $1;
+
+-- name: UseRecoveryCode :execrows
+UPDATE totp_recovery_codes SET used_at = NOW()
+WHERE user_id = $1 AND hashed_code = $2 AND used_at IS NULL;
+
+-- name: CountUnusedRecoveryCodes :one
+SELECT COUNT(*) FROM totp_recovery_codes
+WHERE user_id = $1 AND used_at IS NULL;
+
+-- name: CreateLoginChallenge :one
+INSERT INTO login_challenges (user_id, hashed_token, remember_for_month, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetLoginChallenge :one
+SELECT * FROM login_challenges WHERE id = $1;
+
+-- name: IncrementLoginChallengeAttempts :one
+UPDATE login_challenges SET attempts = attempts + 1 WHERE id = $1
+RETURNING attempts;
+
+-- name: DeleteLoginChallenge :exec
+DELETE FROM login_challenges WHERE id = $1;
+
+-- name: DeleteExpiredLoginChallenges :execrows
+DELETE FROM login_challenges WHERE expires_at < NOW();
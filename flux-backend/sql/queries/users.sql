@@ -6,6 +6,9 @@ RETURNING *;
 -- name: GetUserByUserName :one
 SELECT * FROM users WHERE user_name = $1;
 
+-- name: GetUserByEmail :one
+SELECT * FROM users WHERE LOWER(email) = LOWER($1);
+
 -- name: GetUserByRollNumber :one
 SELECT * FROM users WHERE roll_no = $1;
 
@@ -43,4 +46,72 @@ WHERE
         roll_no = ANY(sqlc.narg('roll_nos')::text[])
     )
 LIMIT sqlc.arg('limit')
-OFFSET sqlc.arg('offset');
\ No newline at end of file
+OFFSET sqlc.arg('offset');
+
+-- name: GetUserContestHistory :many
+-- ended contests the user took part in, latest first. private contests count
+-- through registration, published ones through a score and team contests
+-- through a registered team of the user
+WITH participations AS (
+    SELECT cru.contest_id, NULL::uuid AS team_id
+    FROM contest_registered_users cru
+    WHERE cru.user_id = $1
+    UNION
+    SELECT us.contest_id, NULL::uuid AS team_id
+    FROM user_scores us
+    WHERE us.user_id = $1
+    UNION
+    SELECT crt.contest_id, crt.team_id
+    FROM contest_registered_teams crt
+    JOIN team_members tm ON crt.team_id = tm.team_id
+    WHERE tm.user_id = $1
+)
+SELECT
+    c.id,
+    c.title,
+    -- contests guarded by a timer lock start when the lock expires
+    COALESCE(c.start_time, l.timeout) AS start_time,
+    c.end_time,
+    (CASE WHEN p.team_id IS NULL THEN (
+        SELECT COALESCE(SUM(us.score), 0) FROM user_scores us
+        WHERE us.user_id = $1 AND us.contest_id = c.id
+    ) ELSE (
+        SELECT COALESCE(SUM(ts.score), 0) FROM team_scores ts
+        WHERE ts.team_id = p.team_id AND ts.contest_id = c.id
+    ) END)::INTEGER AS score,
+    -- a problem is scored once per participant, so the scores are the solved problems
+    (CASE WHEN p.team_id IS NULL THEN (
+        SELECT COUNT(*) FROM user_scores us
+        WHERE us.user_id = $1 AND us.contest_id = c.id
+    ) ELSE (
+        SELECT COUNT(*) FROM team_scores ts
+        WHERE ts.team_id = p.team_id AND ts.contest_id = c.id
+    ) END)::BIGINT AS solved
+FROM
+    participations p
+JOIN
+    contests c ON p.contest_id = c.id
+LEFT JOIN
+    locks l ON c.lock_id = l.id
+WHERE
+    c.end_time <= NOW()
+ORDER BY
+    c.end_time DESC
+LIMIT $2;
+
+-- name: GetUserSolvedCount :one
+-- distinct problems solved by the user across all contests
+SELECT COUNT(DISTINCT problem_id) FROM solved WHERE user_id = $1;
+
+-- name: IsEmailTaken :one
+SELECT EXISTS(SELECT 1 FROM users WHERE email = $1);
+
+-- name: UpdateUserProfile :one
+-- a null user_name keeps the current one
+UPDATE users
+SET first_name = $2, last_name = $3, user_name = COALESCE(sqlc.narg('user_name'), user_name)
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserEmail :execrows
+UPDATE users SET email = $2 WHERE id = $1;
@@ -5,8 +5,8 @@
 -- and also quickly check if the score should be added to user for duplicate submission
 CREATE TABLE solved (
     user_id UUID NOT NULL REFERENCES users(id),
-    contest_id UUID NOT NULL REFERENCES contest(id),
-    problem_id UUID NOT NULL REFERENCES problems(id),
+    contest_id UUID NOT NULL REFERENCES contests(id),
+    problem_id INTEGER NOT NULL REFERENCES problems(id),
     
     -- The composite primary key ensures a user can only have one "solved" entry
     -- for a specific problem within a specific contest.
@@ -24,14 +24,15 @@ CREATE INDEX idx_solved_problem_id ON solved(problem_id);
 -- score for a user on a specific problem in a contest.
 CREATE TABLE user_scores (
     user_id UUID NOT NULL REFERENCES users(id),
-    contest_id UUID NOT NULL REFERENCES contest(id),
-    problem_id UUID NOT NULL REFERENCES problems(id),
+    contest_id UUID NOT NULL REFERENCES contests(id),
+    problem_id INTEGER NOT NULL REFERENCES problems(id),
     score INTEGER NOT NULL,
     updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
     
     -- References the specific submission that resulted in this score.
     -- Used for calculating penalties and providing a link to the winning submission.
-    submission_id UUID NOT NULL REFERENCES submissions(id),
+    -- submissions are created in the next migration, so the foreign key is added there
+    submission_id UUID NOT NULL,
     
     -- The composite primary key ensures a user only has one final score entry
     -- for a specific problem within a specific contest.
@@ -2,11 +2,11 @@
 -- Submissions Table
 CREATE TABLE submissions (
     id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-    bot_account_id UUID NOT NULL REFERENCES bots(id), -- The bot account used for the submission
+    bot_account_id UUID REFERENCES bots(id), -- The bot account used for the submission (null until relayed or for local problems)
     website_data JSONB, -- Stores platform-specific submission data like the site submission ID
     submitted_by UUID NOT NULL REFERENCES users(id), -- The user who made the submission
-    contest_id UUID REFERENCES contest(id), -- The contest the submission belongs to (optional, can be null)
-    problem_id UUID NOT NULL REFERENCES problems(id), -- The problem that was submitted
+    contest_id UUID REFERENCES contests(id), -- The contest the submission belongs to (optional, can be null)
+    problem_id INTEGER NOT NULL REFERENCES problems(id), -- The problem that was submitted
     language VARCHAR(50) NOT NULL, -- The programming language used
     solution TEXT NOT NULL, -- The submitted code
     status TEXT, -- The final status of the submission (e.g., 'Accepted', 'Wrong Answer')
@@ -20,6 +20,12 @@ CREATE INDEX idx_submissions_contest_id ON submissions(contest_id);
 CREATE INDEX idx_submissions_problem_id ON submissions(problem_id);
 CREATE INDEX idx_submissions_bot_account_id ON submissions(bot_account_id);
 CREATE INDEX idx_submissions_language ON submissions(language);
+CREATE INDEX idx_submissions_status ON submissions(status);
+
+-- user_scores is created before submissions, link its winning submission here
+ALTER TABLE user_scores
+    ADD CONSTRAINT fk_user_scores_submission
+    FOREIGN KEY (submission_id) REFERENCES submissions(id);
 
 -- +goose StatementBegin
 -- Trigger to update 'updated_at' column
@@ -35,7 +41,9 @@ $$ language 'plpgsql';
 CREATE TRIGGER update_submissions_updated_at BEFORE UPDATE ON submissions FOR EACH ROW EXECUTE FUNCTION update_submissions_updated_at_column();
 
 -- +goose down
+ALTER TABLE user_scores DROP CONSTRAINT fk_user_scores_submission;
 DROP TRIGGER update_submissions_updated_at ON submissions;
+DROP INDEX idx_submissions_status;
 DROP INDEX idx_submissions_bot_account_id;
 DROP INDEX idx_submissions_problem_id;
 DROP INDEX idx_submissions_contest_id;
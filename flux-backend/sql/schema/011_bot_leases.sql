@@ -0,0 +1,11 @@
+-- +goose up
+-- A bot can relay only one submission at a time. A bot is free when it has
+-- no lease or its lease has expired (e.g. the server restarted mid relay).
+ALTER TABLE bots ADD COLUMN leased_until TIMESTAMP WITH TIME ZONE;
+
+-- index for picking a free bot of a platform
+CREATE INDEX idx_bots_platform_leased_until ON bots(platform, leased_until);
+
+-- +goose down
+DROP INDEX idx_bots_platform_leased_until;
+ALTER TABLE bots DROP COLUMN leased_until;
@@ -0,0 +1,41 @@
+-- +goose up
+-- Hidden test data of problems judged locally. The input and output are
+-- stored in a blob store, the row only keeps their keys and checksums.
+CREATE TABLE problem_test_cases (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    problem_id INTEGER NOT NULL REFERENCES problems(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL, -- tests are judged in the order of their position, starting from 1
+    test_group VARCHAR(50), -- subtask the test belongs to
+    points INTEGER NOT NULL DEFAULT 0,
+    storage VARCHAR(20) NOT NULL, -- name of the blob store holding the data
+    input_key TEXT NOT NULL,
+    output_key TEXT NOT NULL,
+    input_size BIGINT NOT NULL,
+    output_size BIGINT NOT NULL,
+    input_sha256 CHAR(64) NOT NULL,
+    output_sha256 CHAR(64) NOT NULL,
+    created_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    -- deferred so that tests can be reordered within a transaction
+    CONSTRAINT uq_problem_test_cases_position UNIQUE (problem_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- +goose StatementBegin
+-- Trigger to update 'updated_at' column
+CREATE OR REPLACE FUNCTION update_problem_test_cases_updated_at_column()
+RETURNS TRIGGER AS $func$
+BEGIN
+    NEW.updated_at = NOW();
+    RETURN NEW;
+END;
+$func$ language 'plpgsql';
+-- +goose StatementEnd
+
+CREATE TRIGGER update_problem_test_cases_updated_at BEFORE UPDATE ON problem_test_cases FOR EACH ROW EXECUTE FUNCTION update_problem_test_cases_updated_at_column();
+
+-- +goose down
+DROP TRIGGER update_problem_test_cases_updated_at ON problem_test_cases;
+DROP FUNCTION update_problem_test_cases_updated_at_column();
+DROP TABLE problem_test_cases;
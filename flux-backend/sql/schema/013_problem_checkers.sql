@@ -0,0 +1,25 @@
+-- +goose up
+-- How the output of a locally judged problem is checked.
+-- exact: line by line ignoring trailing whitespace, token: whitespace separated tokens,
+-- float: tokens with numbers compared up to checker_epsilon,
+-- custom: a testlib compatible checker program written by the author
+CREATE TYPE checker_mode AS ENUM (
+    'exact',
+    'token',
+    'float',
+    'custom'
+);
+
+ALTER TABLE problems
+    ADD COLUMN checker_mode checker_mode NOT NULL DEFAULT 'exact',
+    ADD COLUMN checker_epsilon DOUBLE PRECISION,
+    ADD COLUMN checker_language VARCHAR(20),
+    ADD COLUMN checker_source TEXT;
+
+-- +goose down
+ALTER TABLE problems
+    DROP COLUMN checker_source,
+    DROP COLUMN checker_language,
+    DROP COLUMN checker_epsilon,
+    DROP COLUMN checker_mode;
+DROP TYPE checker_mode;
@@ -0,0 +1,14 @@
+-- +goose up
+-- Participants may register themselves for a contest if the contest allows it.
+-- An optional window restricts when they can do so and an optional cap
+-- restricts how many users can be registered at once.
+ALTER TABLE contests ADD COLUMN self_registration BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE contests ADD COLUMN registration_start TIMESTAMP WITH TIME ZONE;
+ALTER TABLE contests ADD COLUMN registration_end TIMESTAMP WITH TIME ZONE;
+ALTER TABLE contests ADD COLUMN max_participants INTEGER CHECK (max_participants > 0);
+
+-- +goose down
+ALTER TABLE contests DROP COLUMN max_participants;
+ALTER TABLE contests DROP COLUMN registration_end;
+ALTER TABLE contests DROP COLUMN registration_start;
+ALTER TABLE contests DROP COLUMN self_registration;
@@ -0,0 +1,90 @@
+-- +goose up
+-- Teams Table
+-- A team participates in team contests as a single unit
+CREATE TABLE teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(50) NOT NULL UNIQUE,
+    created_by UUID NOT NULL REFERENCES users(id),
+    max_size INTEGER NOT NULL CHECK (max_size > 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_teams_created_by ON teams(created_by);
+
+CREATE TRIGGER update_teams_updated_at BEFORE UPDATE ON teams FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Team Members Table
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    joined_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user_id ON team_members(user_id);
+
+-- Team Invites Table
+-- An invite is deleted once it is accepted or declined
+CREATE TABLE team_invites (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id),
+    invited_by UUID NOT NULL REFERENCES users(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_invites_user_id ON team_invites(user_id);
+
+-- A team contest registers teams instead of individual users
+ALTER TABLE contests ADD COLUMN team_contest BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Contest Registered Teams Table
+CREATE TABLE contest_registered_teams (
+    team_id UUID NOT NULL REFERENCES teams(id),
+    contest_id UUID NOT NULL REFERENCES contests(id),
+
+    PRIMARY KEY (team_id, contest_id)
+);
+
+CREATE INDEX idx_contest_registered_teams_contest_id ON contest_registered_teams(contest_id);
+
+-- submissions of a team contest are made on behalf of the team
+ALTER TABLE submissions ADD COLUMN team_id UUID REFERENCES teams(id);
+CREATE INDEX idx_submissions_team_id ON submissions(team_id);
+
+-- Team Scores Table
+-- Same as user_scores, but a problem is scored once for the whole team.
+-- A row here also marks the problem as solved by the team.
+CREATE TABLE team_scores (
+    team_id UUID NOT NULL REFERENCES teams(id),
+    contest_id UUID NOT NULL REFERENCES contests(id),
+    problem_id INTEGER NOT NULL REFERENCES problems(id),
+    score INTEGER NOT NULL,
+    submission_id UUID NOT NULL REFERENCES submissions(id),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+
+    PRIMARY KEY (team_id, contest_id, problem_id)
+);
+
+CREATE INDEX idx_team_scores_contest_id ON team_scores(contest_id);
+CREATE INDEX idx_team_scores_submission_id ON team_scores(submission_id);
+
+-- +goose down
+DROP INDEX idx_team_scores_submission_id;
+DROP INDEX idx_team_scores_contest_id;
+DROP TABLE team_scores;
+DROP INDEX idx_submissions_team_id;
+ALTER TABLE submissions DROP COLUMN team_id;
+DROP INDEX idx_contest_registered_teams_contest_id;
+DROP TABLE contest_registered_teams;
+ALTER TABLE contests DROP COLUMN team_contest;
+DROP INDEX idx_team_invites_user_id;
+DROP TABLE team_invites;
+DROP INDEX idx_team_members_user_id;
+DROP TABLE team_members;
+DROP TRIGGER update_teams_updated_at ON teams;
+DROP INDEX idx_teams_created_by;
+DROP TABLE teams;
@@ -0,0 +1,29 @@
+-- +goose up
+-- How the contests of a round are combined into a round score.
+-- sum: scores of all the contests are added,
+-- best_n: only the best_n highest contest scores are added,
+-- weighted: each contest score is scaled by its weight (in percent) before adding
+CREATE TYPE round_scoring AS ENUM (
+    'sum',
+    'best_n',
+    'weighted'
+);
+
+ALTER TABLE tournament_rounds
+    ADD COLUMN scoring round_scoring NOT NULL DEFAULT 'sum',
+    ADD COLUMN best_n INTEGER CHECK (best_n > 0),
+    -- best_n is set only for best_n rounds
+    ADD CONSTRAINT chk_rounds_best_n CHECK ((scoring = 'best_n') = (best_n IS NOT NULL));
+
+-- weight of the contest in percent, used only by weighted rounds
+ALTER TABLE tournament_contests
+    ADD COLUMN weight INTEGER NOT NULL DEFAULT 100 CHECK (weight >= 0);
+
+-- +goose down
+ALTER TABLE tournament_contests
+    DROP COLUMN weight;
+ALTER TABLE tournament_rounds
+    DROP CONSTRAINT chk_rounds_best_n,
+    DROP COLUMN best_n,
+    DROP COLUMN scoring;
+DROP TYPE round_scoring;
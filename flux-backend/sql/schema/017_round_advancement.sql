@@ -0,0 +1,31 @@
+-- +goose up
+-- Who advances from a round to the next one.
+-- none: nobody is registered automatically,
+-- top_k: participants ranked in the top advance_k by round score,
+-- top_k_per_contest: participants ranked in the top advance_k of any contest of the round,
+-- threshold: participants with a round score of at least advance_threshold
+CREATE TYPE round_advancement AS ENUM (
+    'none',
+    'top_k',
+    'top_k_per_contest',
+    'threshold'
+);
+
+ALTER TABLE tournament_rounds
+    ADD COLUMN advancement round_advancement NOT NULL DEFAULT 'none',
+    ADD COLUMN advance_k INTEGER CHECK (advance_k > 0),
+    ADD COLUMN advance_threshold DOUBLE PRECISION,
+    -- advance_k is set only for top_k rules and advance_threshold only for threshold rules
+    ADD CONSTRAINT chk_rounds_advance_k
+        CHECK ((advancement IN ('top_k', 'top_k_per_contest')) = (advance_k IS NOT NULL)),
+    ADD CONSTRAINT chk_rounds_advance_threshold
+        CHECK ((advancement = 'threshold') = (advance_threshold IS NOT NULL));
+
+-- +goose down
+ALTER TABLE tournament_rounds
+    DROP CONSTRAINT chk_rounds_advance_threshold,
+    DROP CONSTRAINT chk_rounds_advance_k,
+    DROP COLUMN advance_threshold,
+    DROP COLUMN advance_k,
+    DROP COLUMN advancement;
+DROP TYPE round_advancement;
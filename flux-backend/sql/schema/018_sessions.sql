@@ -0,0 +1,21 @@
+-- +goose up
+-- A session is created on every login and lives until it expires or is revoked.
+-- The short lived access tokens carry the session id, the refresh token
+-- rotates on every refresh and only its sha256 hash is stored
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashed_refresh_token VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
+CREATE INDEX idx_sessions_expires_at ON sessions(expires_at);
+
+-- +goose down
+DROP INDEX idx_sessions_expires_at;
+DROP INDEX idx_sessions_user_id;
+DROP TABLE sessions;
@@ -0,0 +1,59 @@
+-- +goose up
+-- Services authorize named permissions instead of role names.
+-- A role holds its own permissions and every permission of the roles it inherits,
+-- so the mapping can be changed here without touching the code
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role_name VARCHAR(50) NOT NULL REFERENCES roles(role_name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role_name, permission)
+);
+
+-- role_name inherits every permission of parent_role
+CREATE TABLE role_inheritance (
+    role_name VARCHAR(50) NOT NULL REFERENCES roles(role_name) ON DELETE CASCADE,
+    parent_role VARCHAR(50) NOT NULL REFERENCES roles(role_name) ON DELETE CASCADE,
+    PRIMARY KEY (role_name, parent_role),
+    CHECK (role_name <> parent_role)
+);
+
+INSERT INTO roles (role_name) VALUES
+    ('role_manager'),
+    ('role_hc')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO permissions (name, description) VALUES
+    ('contest.manage_public', 'update public contests'),
+    ('contest.edit_any', 'update or delete contests created by others'),
+    ('lock.create', 'create locks'),
+    ('lock.view', 'search locks'),
+    ('lock.edit_any', 'update or delete locks created by others'),
+    ('problem.create', 'add problems'),
+    ('problem.edit_any', 'view and update problems created by others'),
+    ('submission.view_any', 'view submissions of other users'),
+    ('tournament.manage', 'create tournaments, rounds and change their rules'),
+    ('role.manage', 'grant and revoke roles and their permissions');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_manager', 'contest.manage_public'),
+    ('role_manager', 'lock.create'),
+    ('role_manager', 'lock.view'),
+    ('role_manager', 'problem.create'),
+    ('role_manager', 'submission.view_any'),
+    ('role_manager', 'tournament.manage'),
+    ('role_hc', 'contest.edit_any'),
+    ('role_hc', 'lock.edit_any'),
+    ('role_hc', 'problem.edit_any'),
+    ('role_hc', 'role.manage');
+
+INSERT INTO role_inheritance (role_name, parent_role) VALUES
+    ('role_hc', 'role_manager');
+
+-- +goose down
+DROP TABLE role_inheritance;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
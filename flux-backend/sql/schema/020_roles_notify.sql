@@ -0,0 +1,41 @@
+-- +goose up
+-- Every instance caches the roles of users, these triggers tell all of them
+-- (LISTEN user_roles_changed) what to drop. The payload is the id of the user
+-- whose roles changed or '*' when the role to permission mapping changed.
+-- Notifications are delivered only when the transaction commits
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_user_roles_changed()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        PERFORM pg_notify('user_roles_changed', OLD.user_id::text);
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        PERFORM pg_notify('user_roles_changed', NEW.user_id::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_role_mapping_changed()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('user_roles_changed', '*');
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+-- +goose StatementEnd
+
+CREATE TRIGGER user_roles_notify AFTER INSERT OR UPDATE OR DELETE ON user_roles FOR EACH ROW EXECUTE FUNCTION notify_user_roles_changed();
+CREATE TRIGGER role_permissions_notify AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON role_permissions FOR EACH STATEMENT EXECUTE FUNCTION notify_role_mapping_changed();
+CREATE TRIGGER role_inheritance_notify AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON role_inheritance FOR EACH STATEMENT EXECUTE FUNCTION notify_role_mapping_changed();
+
+-- +goose down
+DROP TRIGGER role_inheritance_notify ON role_inheritance;
+DROP TRIGGER role_permissions_notify ON role_permissions;
+DROP TRIGGER user_roles_notify ON user_roles;
+DROP FUNCTION notify_role_mapping_changed();
+DROP FUNCTION notify_user_roles_changed();
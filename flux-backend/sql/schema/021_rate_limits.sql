@@ -0,0 +1,25 @@
+-- +goose up
+-- Shared state of the rate limiter when instances use the postgres store.
+-- A bucket is refilled lazily from updated_at whenever a token is taken
+CREATE TABLE rate_limit_buckets (
+    key VARCHAR(320) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- failed logins of an account within the current window and its lockout
+CREATE TABLE rate_limit_failures (
+    key VARCHAR(320) PRIMARY KEY,
+    failures INTEGER NOT NULL DEFAULT 0,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets(updated_at);
+CREATE INDEX idx_rate_limit_failures_window_start ON rate_limit_failures(window_start);
+
+-- +goose down
+DROP INDEX idx_rate_limit_failures_window_start;
+DROP INDEX idx_rate_limit_buckets_updated_at;
+DROP TABLE rate_limit_failures;
+DROP TABLE rate_limit_buckets;
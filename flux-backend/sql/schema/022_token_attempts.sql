@@ -0,0 +1,6 @@
+-- +goose up
+-- wrong guesses of a verification token, the token is deleted after a few of them
+ALTER TABLE tokens ADD COLUMN attempts INTEGER NOT NULL DEFAULT 0;
+
+-- +goose down
+ALTER TABLE tokens DROP COLUMN attempts;
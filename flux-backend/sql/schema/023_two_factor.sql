@@ -0,0 +1,39 @@
+-- +goose up
+-- A totp secret is unconfirmed until the user proves they stored it by entering a code.
+-- last_used_step is the time step of the last accepted code, so a code cannot be replayed
+CREATE TABLE user_totp (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- only the sha256 hash of a recovery code is stored, each code works once
+CREATE TABLE totp_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashed_code VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, hashed_code)
+);
+
+-- A login with a valid password of a user with 2fa creates a challenge,
+-- the session is created only when the challenge is answered with a code
+CREATE TABLE login_challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    hashed_token VARCHAR(64) NOT NULL,
+    remember_for_month BOOLEAN NOT NULL DEFAULT FALSE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_login_challenges_expires_at ON login_challenges(expires_at);
+
+-- +goose down
+DROP INDEX idx_login_challenges_expires_at;
+DROP TABLE login_challenges;
+DROP TABLE totp_recovery_codes;
+DROP TABLE user_totp;
@@ -0,0 +1,17 @@
+-- +goose up
+-- The lock scheduler publishes a "lock released" event when a timer lock expires.
+-- A row is claimed before publishing, so the event is published once even with
+-- several instances, and locks without a row are scheduled again after a restart
+CREATE TABLE lock_release_events (
+    lock_id UUID PRIMARY KEY REFERENCES locks(id) ON DELETE CASCADE,
+    timeout TIMESTAMP WITH TIME ZONE NOT NULL,
+    published_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- locks that expired before the scheduler existed are not announced
+INSERT INTO lock_release_events (lock_id, timeout, published_at)
+SELECT id, timeout, timeout FROM locks
+WHERE lock_type = 'timer' AND timeout <= NOW();
+
+-- +goose down
+DROP TABLE lock_release_events;
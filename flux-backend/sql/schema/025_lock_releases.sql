@@ -0,0 +1,27 @@
+-- +goose up
+-- A manual lock stays engaged until it is released explicitly,
+-- it can be engaged again afterwards. Timer locks release themselves
+ALTER TABLE locks ADD COLUMN released_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE locks ADD CONSTRAINT locks_released_manual_only
+    CHECK (lock_type = 'manual' OR released_at IS NULL);
+
+CREATE TYPE lock_action AS ENUM ('release', 'engage');
+
+-- every release and engage of a lock, with who did it and when
+CREATE TABLE lock_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    lock_id UUID NOT NULL REFERENCES locks(id) ON DELETE CASCADE,
+    action lock_action NOT NULL,
+    performed_by UUID NOT NULL REFERENCES users(id),
+    performed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_lock_audit_logs_lock_id ON lock_audit_logs(lock_id);
+
+-- +goose down
+DROP INDEX idx_lock_audit_logs_lock_id;
+DROP TABLE lock_audit_logs;
+DROP TYPE lock_action;
+ALTER TABLE locks DROP CONSTRAINT locks_released_manual_only;
+ALTER TABLE locks DROP COLUMN released_at;
@@ -0,0 +1,11 @@
+-- +goose up
+-- users who can see the entities of a lock before its expiry
+-- without holding its access role, e.g. the problem setters of a contest
+CREATE TABLE lock_allowed_users (
+    lock_id UUID NOT NULL REFERENCES locks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (lock_id, user_id)
+);
+
+-- +goose down
+DROP TABLE lock_allowed_users;
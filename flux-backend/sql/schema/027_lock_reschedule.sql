@@ -0,0 +1,12 @@
+-- +goose up
+-- postponing a timer lock moves the start of its public contests,
+-- so it is kept apart from lock.edit_any
+INSERT INTO permissions (name, description) VALUES
+    ('lock.reschedule', 'reschedule timer locks that have not expired');
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('role_hc', 'lock.reschedule');
+
+-- +goose down
+DELETE FROM role_permissions WHERE permission = 'lock.reschedule';
+DELETE FROM permissions WHERE name = 'lock.reschedule';
@@ -0,0 +1,7 @@
+-- +goose up
+-- roles that require 2fa can only refresh sessions that were started with it,
+-- sessions created before 2fa or before the role was granted are not
+ALTER TABLE sessions ADD COLUMN two_factor_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose down
+ALTER TABLE sessions DROP COLUMN two_factor_verified;
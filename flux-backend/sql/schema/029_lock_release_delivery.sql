@@ -0,0 +1,15 @@
+-- +goose up
+-- An event is published at least once: published_at is set only after the
+-- subscribers ran, so a claim left by a crashed instance is taken again once stale
+ALTER TABLE lock_release_events ADD COLUMN claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+UPDATE lock_release_events SET claimed_at = published_at;
+ALTER TABLE lock_release_events
+    ALTER COLUMN published_at DROP NOT NULL,
+    ALTER COLUMN published_at DROP DEFAULT;
+
+-- +goose down
+UPDATE lock_release_events SET published_at = claimed_at WHERE published_at IS NULL;
+ALTER TABLE lock_release_events
+    ALTER COLUMN published_at SET DEFAULT NOW(),
+    ALTER COLUMN published_at SET NOT NULL;
+ALTER TABLE lock_release_events DROP COLUMN claimed_at;
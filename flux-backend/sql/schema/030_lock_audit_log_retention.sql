@@ -0,0 +1,18 @@
+-- +goose up
+-- the audit trail outlives its lock, the name tells which lock it was
+ALTER TABLE lock_audit_logs ADD COLUMN lock_name TEXT NOT NULL DEFAULT '';
+UPDATE lock_audit_logs a SET lock_name = l.name FROM locks l WHERE a.lock_id = l.id;
+ALTER TABLE lock_audit_logs ALTER COLUMN lock_name DROP DEFAULT;
+
+ALTER TABLE lock_audit_logs ALTER COLUMN lock_id DROP NOT NULL;
+ALTER TABLE lock_audit_logs DROP CONSTRAINT lock_audit_logs_lock_id_fkey;
+ALTER TABLE lock_audit_logs ADD CONSTRAINT lock_audit_logs_lock_id_fkey
+    FOREIGN KEY (lock_id) REFERENCES locks(id) ON DELETE SET NULL;
+
+-- +goose down
+DELETE FROM lock_audit_logs WHERE lock_id IS NULL;
+ALTER TABLE lock_audit_logs DROP CONSTRAINT lock_audit_logs_lock_id_fkey;
+ALTER TABLE lock_audit_logs ADD CONSTRAINT lock_audit_logs_lock_id_fkey
+    FOREIGN KEY (lock_id) REFERENCES locks(id) ON DELETE CASCADE;
+ALTER TABLE lock_audit_logs ALTER COLUMN lock_id SET NOT NULL;
+ALTER TABLE lock_audit_logs DROP COLUMN lock_name;
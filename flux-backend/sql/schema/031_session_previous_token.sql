@@ -0,0 +1,8 @@
+-- +goose up
+-- concurrent refreshes (e.g. two tabs) present the same token, the one that
+-- loses the race must not be taken for a stolen token, so the token replaced
+-- by the last rotation is kept and accepted as reused for a short while
+ALTER TABLE sessions ADD COLUMN previous_hashed_refresh_token VARCHAR(64);
+
+-- +goose down
+ALTER TABLE sessions DROP COLUMN previous_hashed_refresh_token;